@@ -0,0 +1,385 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+)
+
+// alterTableStmt is ALTER TABLE with its clauses in order, the oracle parser accepts only a few forms of it.
+// Clauses are *addClause, *modifyClause, *renameTableClause, *unsupportedClause or the ast clauses
+// for dropping and renaming columns and constraints.
+type alterTableStmt struct {
+	sourceNode
+	TableSchema string
+	Table       string
+	Clauses     []any
+}
+
+// addClause holds the added columns and out-of-line constraints as a CREATE TABLE statement.
+type addClause struct {
+	Create  *ast.CreateTableStmt
+	Clauses *tableClauses
+}
+
+type modifyClause struct {
+	Columns []modifyColumn
+}
+
+type modifyColumn struct {
+	Name string
+	// DataType is nil when the type is kept
+	DataType element.Datatype
+	// Nullable is "true", "false" or empty when kept
+	Nullable string
+	// HasDefault is set when the clause gives a default, Default is nil for DEFAULT NULL
+	HasDefault bool
+	Default    *DefaultValue
+	Unique     bool
+	PrimaryKey bool
+	Checks     []checkDef
+}
+
+type renameTableClause struct {
+	Name   string
+	Quoted bool
+}
+
+type unsupportedClause struct {
+	Text string
+}
+
+// renameStmt is RENAME old TO new.
+type renameStmt struct {
+	sourceNode
+	Name    string
+	NewName string
+	// Quoted is set when the new name is quoted
+	Quoted bool
+}
+
+type dropTableStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+}
+
+type dropIndexStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+}
+
+func parseAlterTable(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("ALTER", "TABLE")
+	alter := &alterTableStmt{sourceNode: sourceNode{text: stmt.Source}}
+	alter.TableSchema, alter.Table = r.qualifiedName()
+
+	for !r.done() {
+		start := r.pos
+		switch {
+		case r.accept("ADD"):
+			elems := r.tokens[r.pos:]
+			if r.peek().Is("(") {
+				r.group()
+				elems = r.tokens[start+1 : r.pos]
+			} else {
+				r.pos = nextAlterClause(r.tokens, r.pos)
+				elems = r.tokens[start+1 : r.pos]
+			}
+			clause, err := parseAddElements(stmt, elems)
+			if err != nil {
+				return nil, err
+			}
+			alter.Clauses = append(alter.Clauses, clause)
+		case r.accept("MODIFY", "CONSTRAINT"), r.accept("MODIFY", "PRIMARY"), r.accept("MODIFY", "UNIQUE"):
+			r.pos = nextAlterClause(r.tokens, r.pos)
+			alter.Clauses = append(alter.Clauses, &unsupportedClause{Text: clauseText(stmt, r.tokens[start:r.pos])})
+		case r.accept("MODIFY"):
+			var elems [][]token
+			if r.peek().Is("(") {
+				elems = splitTopLevel(r.group(), ",")
+			} else {
+				end := nextAlterClause(r.tokens, r.pos)
+				elems = [][]token{r.tokens[r.pos:end]}
+				r.pos = end
+			}
+			clause := &modifyClause{}
+			for _, elem := range elems {
+				col, err := parseModifyColumn(stmt, elem)
+				if err != nil {
+					return nil, err
+				}
+				clause.Columns = append(clause.Columns, col)
+			}
+			alter.Clauses = append(alter.Clauses, clause)
+		case r.accept("DROP", "COLUMN"), r.accept("SET", "UNUSED", "COLUMN"):
+			clause := &ast.DropColumnClause{Columns: []*element.Identifier{toIdentifier(r.next())}}
+			alter.Clauses = append(alter.Clauses, clause)
+			r.pos = nextAlterClause(r.tokens, r.pos)
+		case r.accept("DROP", "("), r.accept("SET", "UNUSED", "("):
+			r.pos -= 1
+			clause := &ast.DropColumnClause{}
+			for _, col := range splitTopLevel(r.group(), ",") {
+				if len(col) > 0 {
+					clause.Columns = append(clause.Columns, toIdentifier(col[0]))
+				}
+			}
+			alter.Clauses = append(alter.Clauses, clause)
+			r.pos = nextAlterClause(r.tokens, r.pos)
+		case r.accept("DROP", "CONSTRAINT"):
+			spec := &ast.OutOfLineConstraint{}
+			spec.Name = toIdentifier(r.next())
+			alter.Clauses = append(alter.Clauses, &ast.DropConstraintClause{Constraint: spec})
+			r.pos = nextAlterClause(r.tokens, r.pos)
+		case r.accept("DROP", "PRIMARY", "KEY"):
+			spec := &ast.OutOfLineConstraint{}
+			spec.Type = ast.ConstraintTypePK
+			alter.Clauses = append(alter.Clauses, &ast.DropConstraintClause{Constraint: spec})
+			r.pos = nextAlterClause(r.tokens, r.pos)
+		case r.accept("DROP", "UNIQUE"):
+			spec := &ast.OutOfLineConstraint{}
+			spec.Type = ast.ConstraintTypeUnique
+			for _, col := range splitTopLevel(r.group(), ",") {
+				if len(col) > 0 {
+					spec.Columns = append(spec.Columns, toIdentifier(col[0]))
+				}
+			}
+			alter.Clauses = append(alter.Clauses, &ast.DropConstraintClause{Constraint: spec})
+			r.pos = nextAlterClause(r.tokens, r.pos)
+		case r.accept("RENAME", "COLUMN"):
+			oldName := toIdentifier(r.next())
+			if !r.accept("TO") {
+				return nil, syntaxError(stmt, r.peek(), "expected TO")
+			}
+			alter.Clauses = append(alter.Clauses, &ast.RenameColumnClause{OldName: oldName, NewName: toIdentifier(r.next())})
+		case r.accept("RENAME", "CONSTRAINT"):
+			oldName := toIdentifier(r.next())
+			if !r.accept("TO") {
+				return nil, syntaxError(stmt, r.peek(), "expected TO")
+			}
+			alter.Clauses = append(alter.Clauses, &ast.RenameConstraintClause{OldName: oldName, NewName: toIdentifier(r.next())})
+		case r.accept("RENAME", "TO"):
+			_, name := r.qualifiedName()
+			alter.Clauses = append(alter.Clauses, &renameTableClause{Name: name, Quoted: r.tokens[r.pos-1].Kind == tokenQuotedIdent})
+		default:
+			r.pos = nextAlterClause(r.tokens, r.pos+1)
+			alter.Clauses = append(alter.Clauses, &unsupportedClause{Text: clauseText(stmt, r.tokens[start:r.pos])})
+		}
+	}
+	return alter, nil
+}
+
+// nextAlterClause returns the position of the next ADD, MODIFY, DROP or RENAME clause.
+func nextAlterClause(toks []token, pos int) int {
+	depth := 0
+	for i := pos; i < len(toks); i++ {
+		t := toks[i]
+		switch {
+		case t.Is("("):
+			depth += 1
+		case t.Is(")"):
+			depth -= 1
+		case depth > 0:
+		case t.Is("DROP") && i+1 < len(toks) && toks[i+1].Is("INDEX"):
+			// DROP CONSTRAINT name DROP INDEX
+		case t.Is("ADD"), t.Is("MODIFY"), t.Is("DROP"), t.Is("RENAME"):
+			return i
+		}
+	}
+	return len(toks)
+}
+
+func clauseText(stmt statement, toks []token) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	return stmt.Source[toks[0].Offset-stmt.Offset : toks[len(toks)-1].End()-stmt.Offset]
+}
+
+// parseAddElements parses the columns and constraints of an ADD clause as the relational
+// properties of a CREATE TABLE statement. The source keeps the offsets of the ALTER statement,
+// so syntax errors point into the original source.
+func parseAddElements(stmt statement, elems []token) (*addClause, error) {
+	if len(elems) == 0 {
+		return nil, syntaxError(stmt, token{Offset: stmt.Offset + len(stmt.Source), Line: stmt.Line}, "expected column or constraint")
+	}
+	parenthesized := elems[0].Is("(")
+	start := elems[0].Offset - stmt.Offset
+	end := elems[len(elems)-1].End() - stmt.Offset
+
+	b := []byte(blankTokens(stmt.Source, stmt.Offset, stmt.Tokens))
+	copy(b[start:end], stmt.Source[start:end])
+	prefix := "CREATE TABLE x"
+	if !parenthesized {
+		prefix += "("
+	}
+	copy(b[start-len(prefix):start], prefix)
+	source := string(b[:end])
+	if !parenthesized {
+		source += ")"
+	}
+
+	sub := statement{
+		Index:  stmt.Index,
+		File:   stmt.File,
+		Source: source,
+		Tokens: shiftTokens(tokenize(source), stmt),
+		Offset: stmt.Offset,
+		Line:   stmt.Line,
+		Column: stmt.Column,
+	}
+	blanked, clauses := scanColumnClauses(sub)
+	if n := len(splitTopLevel(elementTokens(sub.Tokens), ",")); n == len(clauses.Checks) {
+		// the oracle parser rejects a table without columns and constraints
+		create := &ast.CreateTableStmt{
+			TableName: &ast.TableName{Table: &element.Identifier{Value: "x"}},
+			RelTable:  &ast.RelTableDef{},
+		}
+		return &addClause{Create: create, Clauses: clauses}, nil
+	}
+	nodes, err := parseOracle(clauses.substituteUserTypes(blanked, sub.Offset))
+	if err != nil {
+		return nil, err
+	}
+	create := castCreateTableStmt(nodes[0])
+	if create == nil {
+		return nil, syntaxError(stmt, elems[0], "expected column or constraint")
+	}
+	clauses.linkConstraints(create)
+	return &addClause{Create: create, Clauses: clauses}, nil
+}
+
+// shiftTokens moves tokens of a source starting at the statement to the position of the statement.
+func shiftTokens(toks []token, stmt statement) []token {
+	for i := range toks {
+		if toks[i].Line == 1 {
+			toks[i].Column += stmt.Column - 1
+		}
+		toks[i].Line += stmt.Line - 1
+		toks[i].Offset += stmt.Offset
+	}
+	return toks
+}
+
+// parseModifyColumn reads "name [datatype] [DEFAULT expr] [[NOT] NULL] [constraints]",
+// the data type is parsed by the oracle parser as a column of a CREATE TABLE statement.
+func parseModifyColumn(stmt statement, elem []token) (modifyColumn, error) {
+	col := modifyColumn{}
+	if len(elem) == 0 {
+		return col, syntaxError(stmt, token{Offset: stmt.Offset, Line: stmt.Line}, "expected column")
+	}
+	col.Name = elem[0].Value()
+
+	i := 1
+	for i < len(elem) && !isColumnClauseStart(elem[i]) {
+		if elem[i].Is("(") {
+			r := newTokenReader(elem[i:])
+			r.group()
+			i += r.pos
+			continue
+		}
+		i += 1
+	}
+	if i > 1 {
+		source := "CREATE TABLE x (" + clauseText(stmt, elem[:i]) + ")"
+		nodes, err := parseOracle(source)
+		if err != nil {
+			return col, syntaxError(stmt, elem[1], "invalid data type of column %v", col.Name)
+		}
+		defs := cast(castCreateTableStmt(nodes[0]).RelTable.TableStructs, castColDefTableStmt)
+		if len(defs) != 1 {
+			return col, syntaxError(stmt, elem[1], "invalid data type of column %v", col.Name)
+		}
+		col.DataType = defs[0].Datatype
+	}
+
+	for i < len(elem) {
+		switch {
+		case elem[i].Is("DEFAULT"):
+			exprStart := i + 1
+			onNull := exprStart+1 < len(elem) && elem[exprStart].Is("ON") && elem[exprStart+1].Is("NULL")
+			if onNull {
+				exprStart += 2
+			}
+			expr := defaultExprTokens(elem[exprStart:])
+			col.HasDefault = true
+			col.Default = parseDefault(stmt, expr, onNull)
+			i = exprStart + len(expr)
+		case elem[i].Is("NOT") && i+1 < len(elem) && elem[i+1].Is("NULL"):
+			col.Nullable = "false"
+			i += 2
+		case elem[i].Is("NULL"):
+			col.Nullable = "true"
+			i += 1
+		case elem[i].Is("UNIQUE"):
+			col.Unique = true
+			i += 1
+		case elem[i].Is("PRIMARY"):
+			col.PrimaryKey = true
+			i += 2
+		case elem[i].Is("CHECK"), elem[i].Is("CONSTRAINT") && i+2 < len(elem) && elem[i+2].Is("CHECK"):
+			def, n := parseCheckClause(stmt, elem[i:])
+			if n == 0 {
+				return col, syntaxError(stmt, elem[i], "invalid check constraint")
+			}
+			def.Column = col.Name
+			col.Checks = append(col.Checks, def)
+			i += n
+		default:
+			i += 1
+		}
+	}
+	return col, nil
+}
+
+func isColumnClauseStart(t token) bool {
+	for _, keyword := range []string{"DEFAULT", "NOT", "NULL", "CONSTRAINT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK",
+		"VISIBLE", "INVISIBLE", "ENCRYPT", "DECRYPT", "COLLATE", "GENERATED", "ENABLE", "DISABLE"} {
+		if t.Is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRename(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("RENAME")
+	node := &renameStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Name = r.next().Value()
+	if !r.accept("TO") {
+		return nil, syntaxError(stmt, r.peek(), "expected TO")
+	}
+	newName := r.next()
+	node.NewName, node.Quoted = newName.Value(), newName.Kind == tokenQuotedIdent
+	return node, nil
+}
+
+func parseDropTable(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("DROP", "TABLE")
+	r.accept("IF", "EXISTS")
+	node := &dropTableStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+func parseDropIndex(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("DROP", "INDEX")
+	r.accept("IF", "EXISTS")
+	node := &dropIndexStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+func alterClauseKind(clause any) string {
+	switch c := clause.(type) {
+	case *unsupportedClause:
+		return strings.Join(strings.Fields(c.Text), " ")
+	}
+	return nodeKind(clause)
+}
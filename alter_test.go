@@ -0,0 +1,141 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// parseDDL parses the script and prints it back as Oracle DDL with the messages of the diagnostics.
+func parseDDL(t *testing.T, sql string, opts ParseOptions) (string, []string) {
+	t.Helper()
+	db, diags, err := ParseWithOptions(sql, opts)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	files, err := GenerateOracleDDL(db, GetDefaultOracleDDLConfig())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	return files["schema.sql"], mapping(diags, func(d Diagnostic) string { return d.Message })
+}
+
+// TestParseWithOptionsReplay checks that a script altering its tables ends up in the schema created at once.
+func TestParseWithOptionsReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   ParseOptions
+		// want creates the schema the script ends up in
+		want  string
+		diags []string
+	}{
+		{
+			name: "add columns and constraints",
+			script: `CREATE TABLE dept (id NUMBER(10));
+CREATE TABLE emp (id NUMBER(10), name VARCHAR2(50));
+ALTER TABLE dept ADD CONSTRAINT pk_dept PRIMARY KEY (id);
+ALTER TABLE emp ADD (dept_id NUMBER(10), salary NUMBER(8,2) DEFAULT 0 NOT NULL);
+ALTER TABLE emp ADD CONSTRAINT fk_emp_dept FOREIGN KEY (dept_id) REFERENCES dept (id);`,
+			want: `CREATE TABLE dept (id NUMBER(10), CONSTRAINT pk_dept PRIMARY KEY (id));
+CREATE TABLE emp (id NUMBER(10), name VARCHAR2(50), dept_id NUMBER(10), salary NUMBER(8,2) DEFAULT 0 NOT NULL,
+  CONSTRAINT fk_emp_dept FOREIGN KEY (dept_id) REFERENCES dept (id));`,
+		},
+		{
+			name: "modify columns",
+			script: `CREATE TABLE t (id NUMBER(10), name VARCHAR2(50), note VARCHAR2(10) NOT NULL);
+ALTER TABLE t MODIFY (name VARCHAR2(100) NOT NULL, note NULL);
+ALTER TABLE t MODIFY id PRIMARY KEY;`,
+			want: `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(100) NOT NULL, note VARCHAR2(10));`,
+		},
+		{
+			name: "rename table and column",
+			script: `CREATE TABLE old_t (id NUMBER(10) PRIMARY KEY, qty NUMBER CHECK (qty > 0));
+CREATE TABLE child (id NUMBER(10), t_id NUMBER(10) REFERENCES old_t (id));
+ALTER TABLE old_t RENAME COLUMN qty TO amount;
+RENAME old_t TO new_t;`,
+			want: `CREATE TABLE new_t (id NUMBER(10) PRIMARY KEY, amount NUMBER CHECK (AMOUNT > 0));
+CREATE TABLE child (id NUMBER(10), t_id NUMBER(10) REFERENCES new_t (id));`,
+		},
+		{
+			name: "drop column and constraints",
+			script: `CREATE TABLE t (id NUMBER(10), code VARCHAR2(10), extra NUMBER,
+  CONSTRAINT pk_t PRIMARY KEY (id), CONSTRAINT uq_t_code UNIQUE (code));
+ALTER TABLE t DROP COLUMN extra;
+ALTER TABLE t DROP CONSTRAINT uq_t_code;`,
+			want: `CREATE TABLE t (id NUMBER(10), code VARCHAR2(10), CONSTRAINT pk_t PRIMARY KEY (id));`,
+		},
+		{
+			name: "drop primary key keeps the column order of a new one",
+			script: `CREATE TABLE t (a NUMBER, b NUMBER, CONSTRAINT pk_t PRIMARY KEY (a));
+ALTER TABLE t DROP PRIMARY KEY;
+ALTER TABLE t ADD CONSTRAINT pk_t PRIMARY KEY (b, a);`,
+			want: `CREATE TABLE t (a NUMBER, b NUMBER, CONSTRAINT pk_t PRIMARY KEY (b, a));`,
+		},
+		{
+			name: "drop and create tables and indexes",
+			script: `CREATE TABLE a (id NUMBER);
+CREATE TABLE b (id NUMBER);
+CREATE INDEX ix_a ON a (id);
+CREATE INDEX ix_b ON b (id);
+DROP TABLE b;
+DROP INDEX ix_a;
+CREATE TABLE b (code VARCHAR2(5));`,
+			want: `CREATE TABLE a (id NUMBER);
+CREATE TABLE b (code VARCHAR2(5));`,
+		},
+		{
+			name: "table created again",
+			script: `CREATE TABLE t (id NUMBER);
+CREATE TABLE t (code VARCHAR2(5));`,
+			want:  `CREATE TABLE t (code VARCHAR2(5));`,
+			diags: []string{"table T created again, the former definition is dropped"},
+		},
+		{
+			name: "unknown tables",
+			script: `CREATE TABLE t (id NUMBER);
+ALTER TABLE nope ADD (x NUMBER);
+DROP TABLE gone;
+DROP INDEX ix_gone;`,
+			want: `CREATE TABLE t (id NUMBER);`,
+			diags: []string{
+				"alter of unknown table NOPE ignored",
+				"drop of unknown table GONE ignored",
+				"drop of unknown index IX_GONE ignored",
+			},
+		},
+		{
+			name: "rename to a used name",
+			script: `CREATE TABLE a (id NUMBER PRIMARY KEY);
+CREATE TABLE b (a_id NUMBER REFERENCES a (id));
+CREATE INDEX ix_b ON b (a_id);
+CREATE VIEW v AS SELECT id FROM a;
+RENAME a TO b;
+ALTER TABLE a RENAME TO v;`,
+			want: `CREATE TABLE a (id NUMBER PRIMARY KEY);
+CREATE TABLE b (a_id NUMBER REFERENCES a (id));
+CREATE INDEX ix_b ON b (a_id);
+CREATE VIEW v AS SELECT id FROM a;`,
+			diags: []string{
+				"rename of A to B ignored: the name is used by a table",
+				"rename of A to V ignored: the name is used by a view",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := parseDDL(t, tt.script, tt.opts)
+			want, wantDiags := parseDDL(t, tt.want, tt.opts)
+			if len(wantDiags) > 0 {
+				t.Fatalf("want script reports %v", wantDiags)
+			}
+			if got != want {
+				t.Errorf("got\n%v\nwant\n%v", got, want)
+			}
+			if !slices.Equal(diags, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", diags, tt.diags)
+			}
+		})
+	}
+}
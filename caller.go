@@ -0,0 +1,513 @@
+package ddlcode
+
+import (
+	"fmt"
+	gotoken "go/token"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// GoCallerConfig generates a Go type calling the routines through database/sql with named arguments.
+type GoCallerConfig struct {
+	ExportDir string
+	Package   string
+	// Name names the file and the type, such as the name of the PL/SQL package the routines belong to
+	Name     string
+	Routines []*Routine
+	Template *template.Template
+	// SourceComments adds the file and line each method is generated from
+	SourceComments bool
+}
+
+// JavaCallerConfig generates a Spring component calling the routines through SimpleJdbcCall,
+// it is written to the repository package next to the SqlExecutor classes.
+type JavaCallerConfig struct {
+	ExportDir string
+	Package   string
+	// Name names the class, such as the name of the PL/SQL package the routines belong to
+	Name     string
+	Routines []*Routine
+	Template *template.Template
+	// SourceComments adds the file and line each method is generated from
+	SourceComments bool
+}
+
+// nullableParameter is the attribute of the parameters, PL/SQL passes NULL to any of them.
+var nullableParameter = AttributeMap{ast.ConstraintTypeNull: nil}
+
+var goCallerTmpl, _ = template.New("goCaller").Funcs(GormFuncMap).Funcs(template.FuncMap{
+	"ToParameterName": toGoParameterName,
+	"ToParameterType": toGoParameterType,
+	"ToCallArgs":      toGoCallArgs,
+	"ToCallSQL":       func(routine *Routine) string { return strconv.Quote(toCallSQL(routine)) },
+	"ToRoutineTypes":  func(routines []*Routine) string { return goUserTypeDecls(parameterColumns(routines)) },
+	"ToLower":         strings.ToLower,
+}).Parse(`package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+// {{ToCamel .Name}} calls stored procedures and functions through DB, a *sql.DB, *sql.Conn or *sql.Tx.
+type {{ToCamel .Name}} struct {
+	DB interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+}
+{{- range .Routines}}
+
+// {{$.MethodName .}} calls the {{ToLower .Kind}} {{.QualifiedName}}.
+{{- if and $.SourceComments .Span.Line}}
+// generated from {{.Span}}
+{{- end}}
+func (c {{ToCamel $.Name}}) {{$.MethodName .}}(ctx context.Context{{range .Parameters}}, {{ToParameterName .}} {{if .IsOut}}*{{end}}{{ToParameterType .}}{{end}}) {{if .IsFunction}}({{ToParameterType .Return}}, error){{else}}error{{end}} {
+{{- if .IsFunction}}
+	var result {{ToParameterType .Return}}
+{{- end}}
+	_, err := c.DB.ExecContext(ctx, {{ToCallSQL .}}{{range ToCallArgs .}},
+		{{.}}{{end}})
+	return {{if .IsFunction}}result, {{end}}err
+}
+{{- end}}{{ToRoutineTypes .Routines}}
+`)
+
+func GetDefaultGoCallerConfig() GoCallerConfig {
+	return GoCallerConfig{
+		ExportDir: ".",
+		Template:  goCallerTmpl,
+	}
+}
+
+func GenerateGoCaller(config GoCallerConfig) (map[string]string, error) {
+	files := map[string]string{}
+	path := filepath.Join(config.ExportDir, strcase.ToLowerCamel(config.Name)+".go")
+	content, err := generateFile(config.Template, config)
+	if err != nil {
+		return nil, err
+	}
+	files[path] = content
+	return files, nil
+}
+
+// MethodName names the method calling the routine, overloads are numbered in the order they are declared.
+func (c GoCallerConfig) MethodName(routine *Routine) string {
+	return strcase.ToCamel(routineMethodName(c.Routines, routine))
+}
+
+// Imports lists the packages the types of the parameters need.
+func (c GoCallerConfig) Imports() []string {
+	imports := []string{"context", "database/sql"}
+	for _, routine := range c.Routines {
+		params := slices.Clone(routine.Parameters)
+		if routine.Return != nil {
+			params = append(params, routine.Return)
+		}
+		for _, param := range params {
+			name := toGoParameterType(param)
+			if strings.Contains(name, "driver.") && !slices.Contains(imports, "database/sql/driver") {
+				imports = append(imports, "database/sql/driver")
+			}
+			if strings.Contains(name, "time.") && !slices.Contains(imports, "time") {
+				imports = append(imports, "time")
+			}
+		}
+	}
+	slices.Sort(imports)
+	return imports
+}
+
+// routineMethodName is the name of the routine, followed by its number among the routines of that name
+// from the second one on.
+func routineMethodName(routines []*Routine, routine *Routine) string {
+	n := 0
+	for _, r := range routines {
+		if r.Name == routine.Name {
+			n++
+		}
+		if r == routine {
+			break
+		}
+	}
+	if n > 1 {
+		return fmt.Sprintf("%v_%v", routine.Name, n)
+	}
+	return routine.Name
+}
+
+// goReservedNames are the names the generated methods use besides the parameters.
+var goReservedNames = []string{"c", "ctx", "err", "result"}
+
+func toGoParameterName(param *Parameter) string {
+	name := strcase.ToLowerCamel(param.Name)
+	if gotoken.IsKeyword(name) || slices.Contains(goReservedNames, name) {
+		name += "_"
+	}
+	return name
+}
+
+// toGoParameterType maps the parameter as a nullable column, BOOLEAN to sql.NullBool, SYS_REFCURSOR to
+// the driver.Rows of the cursor and types without a column type to any.
+func toGoParameterType(param *Parameter) string {
+	if param.DataType == nil {
+		switch strings.ToUpper(param.Type) {
+		case "BOOLEAN":
+			return "sql.NullBool"
+		case "SYS_REFCURSOR":
+			return "driver.Rows"
+		}
+		return "any"
+	}
+	return toGoType(param.DataType, nullableParameter)
+}
+
+// bindName names the bind variable of the parameter, names Oracle takes only in double quotes are numbered.
+func bindName(routine *Routine, param *Parameter) string {
+	if !needsQuoting(param.Name) {
+		return param.Name
+	}
+	return fmt.Sprintf("P%v", slices.Index(routine.Parameters, param)+1)
+}
+
+// resultBindName names the bind variable of the function result apart from the parameters.
+func resultBindName(routine *Routine) string {
+	name := "RESULT"
+	for slices.ContainsFunc(routine.Parameters, func(p *Parameter) bool { return bindName(routine, p) == name }) {
+		name += "_"
+	}
+	return name
+}
+
+// toCallSQL is the anonymous block calling the routine with the parameters passed by name,
+// e.g. "BEGIN :RESULT := APP.ORDER_API.ORDER_COUNT(P_SINCE => :P_SINCE); END;".
+func toCallSQL(routine *Routine) string {
+	names := []string{}
+	for _, name := range []string{routine.Schema, routine.Package, routine.Name} {
+		if name != "" {
+			names = append(names, quoteIdentifier(name))
+		}
+	}
+	call := strings.Builder{}
+	call.WriteString("BEGIN ")
+	if routine.IsFunction() {
+		fmt.Fprintf(&call, ":%v := ", resultBindName(routine))
+	}
+	call.WriteString(strings.Join(names, "."))
+	if len(routine.Parameters) > 0 {
+		args := []string{}
+		for _, param := range routine.Parameters {
+			args = append(args, fmt.Sprintf("%v => :%v", quoteIdentifier(param.Name), bindName(routine, param)))
+		}
+		fmt.Fprintf(&call, "(%v)", strings.Join(args, ", "))
+	}
+	call.WriteString("; END;")
+	return call.String()
+}
+
+// toGoCallArgs passes the parameters as sql.Named arguments, OUT and IN OUT parameters through sql.Out.
+func toGoCallArgs(routine *Routine) []string {
+	args := []string{}
+	if routine.IsFunction() {
+		args = append(args, fmt.Sprintf("sql.Named(%q, sql.Out{Dest: &result})", resultBindName(routine)))
+	}
+	for _, param := range routine.Parameters {
+		value := toGoParameterName(param)
+		switch param.Mode {
+		case "OUT":
+			value = fmt.Sprintf("sql.Out{Dest: %v}", value)
+		case "IN OUT":
+			value = fmt.Sprintf("sql.Out{Dest: %v, In: true}", value)
+		}
+		args = append(args, fmt.Sprintf("sql.Named(%q, %v)", bindName(routine, param), value))
+	}
+	return args
+}
+
+// parameterColumns returns the parameters and results of user-defined types as columns,
+// so their types are declared as those of columns.
+func parameterColumns(routines []*Routine) []*Column {
+	columns := []*Column{}
+	for _, routine := range routines {
+		params := slices.Clone(routine.Parameters)
+		if routine.Return != nil {
+			params = append(params, routine.Return)
+		}
+		for _, param := range params {
+			if _, ok := param.DataType.(*UserDatatype); ok {
+				columns = append(columns, &Column{Name: param.Name, Type: param.Type, DataType: param.DataType, Attribute: nullableParameter})
+			}
+		}
+	}
+	return columns
+}
+
+var JavaCallerTemplate = `package {{.Package}}.repository;
+
+import java.sql.Types;
+import java.util.List;
+import java.util.Map;
+{{ GetParameterImportPaths .Routines }}
+
+import org.springframework.jdbc.core.ColumnMapRowMapper;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.core.SqlInOutParameter;
+import org.springframework.jdbc.core.SqlOutParameter;
+import org.springframework.jdbc.core.SqlParameter;
+import org.springframework.jdbc.core.namedparam.MapSqlParameterSource;
+import org.springframework.jdbc.core.simple.SimpleJdbcCall;
+import org.springframework.stereotype.Component;
+
+@Component
+public class {{ToCamel .Name}}Caller {
+{{- range .Routines}}
+  private final SimpleJdbcCall {{$.FieldName .}};
+{{- end}}
+
+  public {{ToCamel .Name}}Caller(JdbcTemplate jdbcTemplate) {
+{{- range .Routines}}
+    this.{{$.FieldName .}} = new SimpleJdbcCall(jdbcTemplate)
+{{- with .Schema}}
+        .withSchemaName("{{.}}")
+{{- end}}
+{{- with .Package}}
+        .withCatalogName("{{.}}")
+{{- end}}
+        .with{{if .IsFunction}}Function{{else}}Procedure{{end}}Name("{{.Name}}")
+        .withoutProcedureColumnMetaDataAccess()
+{{- with ToSqlParameters .}}
+        .declareParameters(
+            {{Join . ",\n            "}})
+{{- end}};
+{{- end}}
+  }
+{{- range .Routines}}
+{{if and $.SourceComments .Span.Line}}
+  // generated from {{.Span}}
+{{- end}}
+  public {{ToReturnType .}} {{$.FieldName .}}({{ToParameterList .}}) {
+    MapSqlParameterSource params = new MapSqlParameterSource();
+{{- range .Parameters}}
+{{- if ne .Mode "OUT"}}
+    params.addValue("{{.Name}}", {{ToParameterName .}});
+{{- end}}
+{{- end}}
+{{- if .IsFunction}}
+    return {{$.FieldName .}}.executeFunction({{ToReturnClass .}}, params);
+{{- else if HasOutParameter .}}
+    return {{$.FieldName .}}.execute(params);
+{{- else}}
+    {{$.FieldName .}}.execute(params);
+{{- end}}
+  }
+{{- end}}
+}
+`
+
+func GetDefaultJavaCallerConfig() JavaCallerConfig {
+	config := JavaCallerConfig{
+		ExportDir: ".",
+	}
+	var err error
+	config.Template, err = template.New("javaCaller").Funcs(JavaFuncMap).Parse(JavaCallerTemplate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config
+}
+
+func GenerateJavaCaller(config JavaCallerConfig) (map[string]string, error) {
+	files := map[string]string{}
+	path := filepath.Join(config.ExportDir, "repository", strcase.ToCamel(config.Name)+"Caller.java")
+	content, err := generateFile(config.Template, config)
+	if err != nil {
+		return nil, err
+	}
+	files[path] = content
+	return files, nil
+}
+
+// FieldName names the SimpleJdbcCall of the routine and the method calling it, overloads are numbered.
+func (c JavaCallerConfig) FieldName(routine *Routine) string {
+	return strcase.ToLowerCamel(routineMethodName(c.Routines, routine))
+}
+
+func getParameterImportPaths(routines []*Routine) string {
+	importPaths := map[string]struct{}{}
+	for _, routine := range routines {
+		params := slices.Clone(routine.Parameters)
+		if routine.Return != nil {
+			params = append(params, routine.Return)
+		}
+		for _, param := range params {
+			// user-defined types are passed as Object
+			if _, ok := param.DataType.(*UserDatatype); !ok && param.DataType != nil {
+				addJavaImportPaths(importPaths, param.DataType)
+			}
+		}
+	}
+	paths := maps.Keys(importPaths)
+	slices.Sort(paths)
+	return strings.Join(paths, "\n")
+}
+
+// toJavaParameterType maps the parameter as a column, BOOLEAN to Boolean, a cursor to the list of its rows.
+// User-defined types are passed as java.sql.Struct or java.sql.Array objects.
+func toJavaParameterType(param *Parameter) string {
+	if _, ok := param.DataType.(*UserDatatype); ok {
+		return "Object"
+	}
+	if param.DataType == nil {
+		switch strings.ToUpper(param.Type) {
+		case "BOOLEAN":
+			return "Boolean"
+		case "SYS_REFCURSOR":
+			return "List<Map<String, Object>>"
+		}
+		return "Object"
+	}
+	return toJavaType(param.DataType)
+}
+
+// toJavaSqlType is the java.sql.Types constant the parameter is declared with.
+func toJavaSqlType(param *Parameter) string {
+	if datatype, ok := param.DataType.(*UserDatatype); ok {
+		switch {
+		case datatype.Ref:
+			return "Types.REF"
+		case !datatype.IsPlaceholder() && datatype.Type.IsCollection():
+			return "Types.ARRAY"
+		}
+		return "Types.STRUCT"
+	}
+	if param.DataType == nil {
+		switch strings.ToUpper(param.Type) {
+		case "BOOLEAN":
+			return "Types.BOOLEAN"
+		case "SYS_REFCURSOR":
+			return "Types.REF_CURSOR"
+		}
+		return "Types.OTHER"
+	}
+	switch param.DataType.DataDef() {
+	case element.DataDefChar, element.DataDefNChar, element.DataDefCharacter, element.DataDefNationalCharacter, element.DataDefNationalChar:
+		return "Types.CHAR"
+	case element.DataDefVarchar2, element.DataDefNVarChar2, element.DataDefCharacterVarying, element.DataDefCharVarying, element.DataDefNCharVarying, element.DataDefVarchar, element.DataDefNationalCharacterVarying, element.DataDefNationalCharVarying:
+		return "Types.VARCHAR"
+	case element.DataDefInteger, element.DataDefInt, element.DataDefSmallInt:
+		return "Types.INTEGER"
+	case element.DataDefNumber, element.DataDefDecimal, element.DataDefDec, element.DataDefNumeric:
+		return "Types.NUMERIC"
+	case element.DataDefFloat, element.DataDefReal, element.DataDefBinaryFloat:
+		return "Types.FLOAT"
+	case element.DataDefBinaryDouble, element.DataDefDoublePrecision:
+		return "Types.DOUBLE"
+	case element.DataDefDate, element.DataDefTimestamp:
+		// an Oracle DATE has a time of day
+		return "Types.TIMESTAMP"
+	case element.DataDefClob, element.DataDefNClob:
+		return "Types.CLOB"
+	case element.DataDefBlob, element.DataDefBFile:
+		return "Types.BLOB"
+	case element.DataDefRaw, element.DataDefLongRaw:
+		return "Types.VARBINARY"
+	case element.DataDefLong:
+		return "Types.LONGVARCHAR"
+	case element.DataDefRowId, element.DataDefURowId:
+		return "Types.ROWID"
+	}
+	return "Types.OTHER"
+}
+
+// toSqlParameters declares the parameters of the call, the result of a function comes first.
+func toSqlParameters(routine *Routine) []string {
+	declare := func(kind, name string, param *Parameter) string {
+		sqlType := toJavaSqlType(param)
+		switch datatype, ok := param.DataType.(*UserDatatype); {
+		case sqlType == "Types.REF_CURSOR" && kind != "SqlParameter":
+			return fmt.Sprintf(`new %v("%v", %v, new ColumnMapRowMapper())`, kind, name, sqlType)
+		case ok:
+			return fmt.Sprintf(`new %v("%v", %v, "%v")`, kind, name, sqlType, datatype.Name)
+		}
+		return fmt.Sprintf(`new %v("%v", %v)`, kind, name, sqlType)
+	}
+	parameters := []string{}
+	if routine.IsFunction() {
+		parameters = append(parameters, declare("SqlOutParameter", "RETURN", routine.Return))
+	}
+	for _, param := range routine.Parameters {
+		kind := "SqlParameter"
+		switch param.Mode {
+		case "OUT":
+			kind = "SqlOutParameter"
+		case "IN OUT":
+			kind = "SqlInOutParameter"
+		}
+		parameters = append(parameters, declare(kind, param.Name, param))
+	}
+	return parameters
+}
+
+// toJavaParameterList declares the arguments of the method, OUT parameters come back in the result map.
+func toJavaParameterList(routine *Routine) string {
+	args := []string{}
+	for _, param := range routine.Parameters {
+		if param.Mode != "OUT" {
+			args = append(args, toJavaParameterType(param)+" "+toJavaParameterName(param))
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// javaKeywords are the reserved words and literals of Java, which cannot name a parameter.
+var javaKeywords = []string{
+	"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const", "continue",
+	"default", "do", "double", "else", "enum", "extends", "false", "final", "finally", "float", "for", "goto", "if",
+	"implements", "import", "instanceof", "int", "interface", "long", "native", "new", "null", "package", "private",
+	"protected", "public", "return", "short", "static", "strictfp", "super", "switch", "synchronized", "this",
+	"throw", "throws", "transient", "true", "try", "void", "volatile", "while", "_",
+}
+
+// javaReservedNames are the names the generated methods use besides the parameters.
+var javaReservedNames = []string{"params"}
+
+func toJavaParameterName(param *Parameter) string {
+	name := strcase.ToLowerCamel(param.Name)
+	if slices.Contains(javaKeywords, name) || slices.Contains(javaReservedNames, name) {
+		name += "_"
+	}
+	return name
+}
+
+func hasOutParameter(routine *Routine) bool {
+	return slices.ContainsFunc(routine.Parameters, (*Parameter).IsOut)
+}
+
+// toJavaReturnType is the result of the function, the OUT parameters by name for a procedure having some.
+func toJavaReturnType(routine *Routine) string {
+	switch {
+	case routine.IsFunction():
+		return toJavaParameterType(routine.Return)
+	case hasOutParameter(routine):
+		return "Map<String, Object>"
+	}
+	return "void"
+}
+
+// toJavaReturnClass is the class executeFunction converts the result to.
+func toJavaReturnClass(routine *Routine) string {
+	name := toJavaParameterType(routine.Return)
+	if i := strings.Index(name, "<"); i >= 0 {
+		name = name[:i]
+	}
+	return name + ".class"
+}
@@ -0,0 +1,148 @@
+package ddlcode
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const callerScript = `CREATE TABLE app.orders (id NUMBER(10) PRIMARY KEY, placed DATE);
+CREATE OR REPLACE PACKAGE app.order_api AS
+  TYPE id_list IS TABLE OF NUMBER;
+  FUNCTION order_count(p_since IN DATE DEFAULT SYSDATE) RETURN PLS_INTEGER;
+  PROCEDURE place(p_id IN app.orders.id%TYPE, "new" OUT VARCHAR2, params IN OUT NUMBER, p_ok BOOLEAN);
+  PROCEDURE place(p_ids IN id_list, p_cur OUT SYS_REFCURSOR);
+END order_api;
+/
+CREATE FUNCTION app.ping RETURN VARCHAR2 AS BEGIN RETURN 'x'; END;
+/`
+
+// callerRoutines returns the routines of the package followed by the standalone ones.
+func callerRoutines(t *testing.T) []*Routine {
+	t.Helper()
+	db := parseSchema(t, callerScript)
+	if len(db.Packages) != 1 {
+		t.Fatalf("got %v packages, want 1", len(db.Packages))
+	}
+	return append(slices.Clone(db.Packages[0].Routines), db.Routines...)
+}
+
+func TestRoutines(t *testing.T) {
+	routines := callerRoutines(t)
+	// signatures lists the parameters as "NAME MODE TYPE", the type followed by "=" when it has a default
+	// and by "?" when it has no column type, P_ID takes the type of ORDERS.ID
+	signatures := mapping(routines, func(routine *Routine) string {
+		params := mapping(routine.Parameters, func(p *Parameter) string {
+			text := p.Name + " " + p.Mode + " " + p.Type
+			if p.HasDefault {
+				text += "="
+			}
+			if p.DataType == nil {
+				text += "?"
+			}
+			return text
+		})
+		text := routine.Kind + " " + routine.QualifiedName() + "(" + strings.Join(params, ", ") + ")"
+		if routine.Return != nil {
+			text += " RETURN " + routine.Return.Type
+		}
+		return text
+	})
+	want := []string{
+		"FUNCTION APP.ORDER_API.ORDER_COUNT(P_SINCE IN DATE=) RETURN PLS_INTEGER",
+		`PROCEDURE APP.ORDER_API.PLACE(P_ID IN app.orders.id%TYPE, new OUT VARCHAR2, PARAMS IN OUT NUMBER, P_OK IN BOOLEAN?)`,
+		"PROCEDURE APP.ORDER_API.PLACE(P_IDS IN id_list, P_CUR OUT SYS_REFCURSOR?)",
+		"FUNCTION APP.PING() RETURN VARCHAR2",
+	}
+	if !slices.Equal(signatures, want) {
+		t.Errorf("got routines\n%v\nwant\n%v", strings.Join(signatures, "\n"), strings.Join(want, "\n"))
+	}
+
+	_, diags, err := ParseWithOptions(`CREATE TABLE app.orders (id NUMBER);
+CREATE PROCEDURE app.send(p_msg IN app.message_t, p_id IN app.orders.missing%TYPE) AS BEGIN NULL; END;
+/`, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := mapping(diags, func(d Diagnostic) string { return d.Message })
+	if want := []string{
+		"parameter P_MSG of APP.SEND has unknown type APP.MESSAGE_T",
+		"parameter P_ID of APP.SEND has the type of unknown column ORDERS.MISSING",
+	}; !slices.Equal(messages, want) {
+		t.Errorf("got diagnostics %q, want %q", messages, want)
+	}
+}
+
+func TestGenerateGoCaller(t *testing.T) {
+	config := GetDefaultGoCallerConfig()
+	config.Package = "db"
+	config.Name = "order_api"
+	config.Routines = callerRoutines(t)
+	files, err := GenerateGoCaller(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, ok := files["orderApi.go"]
+	if !ok {
+		t.Fatalf("got files %v, want orderApi.go", maps.Keys(files))
+	}
+	for _, want := range []string{
+		"\t\"database/sql/driver\"\n",
+		"func (c OrderApi) OrderCount(ctx context.Context, pSince sql.NullTime) (sql.NullInt32, error) {",
+		`"BEGIN :RESULT := APP.ORDER_API.ORDER_COUNT(P_SINCE => :P_SINCE); END;"`,
+		"func (c OrderApi) Place(ctx context.Context, pId sql.NullFloat64, new *sql.NullString, params *sql.NullFloat64, pOk sql.NullBool) error {",
+		`"BEGIN APP.ORDER_API.PLACE(P_ID => :P_ID, \"new\" => :P2, PARAMS => :PARAMS, P_OK => :P_OK); END;"`,
+		`sql.Named("P2", sql.Out{Dest: new}),`,
+		`sql.Named("PARAMS", sql.Out{Dest: params, In: true}),`,
+		"func (c OrderApi) Place2(ctx context.Context, pIds IdList, pCur *driver.Rows) error {",
+		`"BEGIN :RESULT := APP.PING; END;"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in\n%v", want, content)
+		}
+	}
+}
+
+func TestGenerateJavaCaller(t *testing.T) {
+	config := GetDefaultJavaCallerConfig()
+	config.Package = "com.example"
+	config.Name = "order_api"
+	config.Routines = callerRoutines(t)
+	files, err := GenerateJavaCaller(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, ok := files["repository/OrderApiCaller.java"]
+	if !ok {
+		t.Fatalf("got files %v, want repository/OrderApiCaller.java", maps.Keys(files))
+	}
+	for _, want := range []string{
+		"import java.util.Date;\n",
+		`new SqlOutParameter("RETURN", Types.INTEGER),`,
+		`new SqlParameter("P_SINCE", Types.TIMESTAMP));`,
+		`new SqlOutParameter("new", Types.VARCHAR),`,
+		`new SqlInOutParameter("PARAMS", Types.NUMERIC),`,
+		`new SqlOutParameter("P_CUR", Types.REF_CURSOR, new ColumnMapRowMapper()));`,
+		"public Integer orderCount(Date pSince) {",
+		// OUT parameters are no arguments, params names the parameter source of the method
+		"public Map<String, Object> place(Long pId, Long params_, Boolean pOk) {",
+		`params.addValue("PARAMS", params_);`,
+		"public Map<String, Object> place2(Object pIds) {",
+		"return ping.executeFunction(String.class, params);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in\n%v", want, content)
+		}
+	}
+}
+
+func TestJavaParameterName(t *testing.T) {
+	tests := map[string]string{"P_ID": "pId", "NEW": "new_", "params": "params_", "CLASS": "class_", "record": "record"}
+	for name, want := range tests {
+		if got := toJavaParameterName(&Parameter{Name: name}); got != want {
+			t.Errorf("got %v for %v, want %v", got, name, want)
+		}
+	}
+}
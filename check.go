@@ -0,0 +1,132 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+// checkDef is a CHECK clause as written, the oracle parser rejects nearly all of them.
+type checkDef struct {
+	Span SourceSpan
+	Name string
+	// Column is set for inline constraints
+	Column     string
+	Expression string
+	Tokens     []token
+}
+
+// parseCheckClause reads "[CONSTRAINT name] CHECK (condition)" and returns the number of tokens read,
+// it returns 0 when the tokens are not a check constraint.
+func parseCheckClause(stmt statement, toks []token) (checkDef, int) {
+	r := newTokenReader(toks)
+	def := checkDef{}
+	if r.accept("CONSTRAINT") {
+		def.Name = r.next().Value()
+	}
+	if !r.accept("CHECK") || !r.peek().Is("(") {
+		return def, 0
+	}
+	def.Tokens = r.group()
+	def.Span = spanOf(stmt.File, toks[:r.pos])
+	if len(def.Tokens) > 0 {
+		def.Expression = stmt.Source[def.Tokens[0].Offset-stmt.Offset : def.Tokens[len(def.Tokens)-1].End()-stmt.Offset]
+	}
+	return def, r.pos
+}
+
+// translateCheck links a check constraint to the columns its condition refers to.
+func translateCheck(table *Table, def checkDef) (*CheckConstraint, error) {
+	check := &CheckConstraint{Name: def.Name, Expression: def.Expression}
+	if def.Column != "" {
+		col := table.getColumn(def.Column)
+		if col == nil {
+			return nil, fmt.Errorf("unknown column: %v.%v", table.Table, def.Column)
+		}
+		check.Columns = append(check.Columns, col)
+	}
+	for _, t := range def.Tokens {
+		if t.Kind != tokenWord && t.Kind != tokenQuotedIdent {
+			continue
+		}
+		if col := table.getColumn(t.Value()); col != nil && !slices.Contains(check.Columns, col) {
+			check.Columns = append(check.Columns, col)
+		}
+	}
+	if column, values, ok := parseInList(def.Tokens); ok {
+		col := table.getColumn(column)
+		if col != nil && (def.Column == "" || col == check.Columns[0]) {
+			check.Values = values
+		}
+	}
+	return check, nil
+}
+
+// renameColumn rewrites the references to a renamed column in the condition, as Oracle does.
+func (check *CheckConstraint) renameColumn(oldName, newName string, quoted bool) {
+	name := quoteIdentifier(newName)
+	if quoted {
+		name = `"` + strings.ReplaceAll(newName, `"`, `""`) + `"`
+	}
+	expression := strings.Builder{}
+	last := 0
+	for _, t := range tokenize(check.Expression) {
+		if (t.Kind == tokenWord || t.Kind == tokenQuotedIdent) && t.Value() == oldName {
+			expression.WriteString(check.Expression[last:t.Offset])
+			expression.WriteString(name)
+			last = t.End()
+		}
+	}
+	expression.WriteString(check.Expression[last:])
+	check.Expression = expression.String()
+}
+
+// parseInList recognizes "col IN (literal, ...)" conditions.
+func parseInList(toks []token) (column string, values []string, ok bool) {
+	for len(toks) > 2 && toks[0].Is("(") {
+		r := newTokenReader(toks)
+		inner := r.group()
+		if !r.done() {
+			break
+		}
+		toks = inner
+	}
+	if len(toks) < 4 || (toks[0].Kind != tokenWord && toks[0].Kind != tokenQuotedIdent) || !toks[1].Is("IN") {
+		return "", nil, false
+	}
+	r := newTokenReader(toks[2:])
+	items := splitTopLevel(r.group(), ",")
+	if !r.done() || len(items) == 0 {
+		return "", nil, false
+	}
+	for _, item := range items {
+		switch {
+		case len(item) == 1 && (item[0].Kind == tokenString || item[0].Kind == tokenNumber):
+			values = append(values, item[0].Value())
+		case len(item) == 2 && item[0].Is("-") && item[1].Kind == tokenNumber:
+			values = append(values, "-"+item[1].Text)
+		default:
+			return "", nil, false
+		}
+	}
+	return toks[0].Value(), values, true
+}
+
+// isEnumColumn reports whether a character column is restricted to a list of values,
+// generators turn such columns into enums. Primary keys are left alone.
+func isEnumColumn(col *Column) bool {
+	return len(col.AllowedValues()) > 0 && isCharacterType(col) && !col.Attribute.IsPrimaryKey()
+}
+
+func isCharacterType(col *Column) bool {
+	if col.DataType == nil {
+		return false
+	}
+	switch col.DataType.DataDef() {
+	case element.DataDefChar, element.DataDefVarchar2, element.DataDefNChar, element.DataDefNVarChar2, element.DataDefCharacter, element.DataDefCharacterVarying, element.DataDefCharVarying, element.DataDefNCharVarying, element.DataDefVarchar, element.DataDefNationalCharacter, element.DataDefNationalCharacterVarying, element.DataDefNationalChar, element.DataDefNationalCharVarying:
+		return true
+	}
+	return false
+}
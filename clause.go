@@ -0,0 +1,226 @@
+package ddlcode
+
+import (
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+// tableClauses holds what the oracle parser drops from the relational properties of CREATE TABLE
+// or of ALTER TABLE ADD.
+type tableClauses struct {
+	Columns map[string]*columnClauses
+	// Checks are the out-of-line check constraints
+	Checks []checkDef
+	// Spans locates the inline references and, once linked to the parsed statement, the out-of-line constraints
+	Spans map[*ast.OutOfLineConstraint]SourceSpan
+	// constraints are the spans of the out-of-line constraints other than checks, in order
+	constraints []SourceSpan
+	// Properties follow the relational properties of CREATE TABLE
+	Properties tableProperties
+}
+
+// scanColumnClauses reads the column clauses of a CREATE TABLE statement the oracle parser drops.
+// Clauses the oracle parser rejects are blanked in the returned source, out-of-line check
+// constraints are returned separately.
+func scanColumnClauses(stmt statement) (string, *tableClauses) {
+	source := stmt.Source
+	clauses := &tableClauses{
+		Columns: map[string]*columnClauses{},
+		Checks:  []checkDef{},
+		Spans:   map[*ast.OutOfLineConstraint]SourceSpan{},
+	}
+
+	group := elementTokens(stmt.Tokens)
+	for _, elem := range splitTopLevel(group, ",") {
+		if len(elem) == 0 {
+			continue
+		}
+		if def, n := parseCheckClause(stmt, elem); n > 0 {
+			clauses.Checks = append(clauses.Checks, def)
+			source = blankTokens(source, stmt.Offset, withSeparator(group, elem))
+			continue
+		}
+		if isOutOfLineConstraintStart(elem[0]) {
+			clauses.constraints = append(clauses.constraints, spanOf(stmt.File, elem))
+			continue
+		}
+		if len(elem) < 2 {
+			continue
+		}
+		c := &columnClauses{Span: spanOf(stmt.File, elem)}
+		if datatype, n := userDatatype(elem[1:]); datatype != nil {
+			c.UserType, c.typeTokens = datatype, elem[1:1+n]
+		}
+		for i := 1; i < len(elem); i++ {
+			switch {
+			case elem[i].Is("GENERATED"):
+				// the oracle parser rejects GENERATED BY DEFAULT
+				identity, n := parseIdentityClause(elem[i:])
+				if identity == nil {
+					continue
+				}
+				c.Identity = identity
+				source = blankTokens(source, stmt.Offset, elem[i:i+n])
+				i += n - 1
+			case elem[i].Is("DEFAULT"):
+				exprStart := i + 1
+				onNull := exprStart+1 < len(elem) && elem[exprStart].Is("ON") && elem[exprStart+1].Is("NULL")
+				if onNull {
+					exprStart += 2
+				}
+				expr := defaultExprTokens(elem[exprStart:])
+				c.Default = parseDefault(stmt, expr, onNull)
+				if c.Default != nil && c.Default.Kind == DefaultNextval {
+					c.SequenceSchema, c.SequenceName = c.Default.Schema, c.Default.Value
+				}
+				source = blankTokens(source, stmt.Offset, elem[i:exprStart+len(expr)])
+				i = exprStart + len(expr) - 1
+			case elem[i].Is("REFERENCES"):
+				spec, n := parseInlineReference(elem[0], elem[:i], elem[i:])
+				c.References = append(c.References, spec)
+				start := i
+				if spec.Name != nil {
+					start -= 2
+				}
+				clauses.Spans[spec] = spanOf(stmt.File, elem[start:i+n])
+				source = blankTokens(source, stmt.Offset, elem[start:i+n])
+			case elem[i].Is("CHECK"):
+				start := i
+				if i >= 2 && elem[i-2].Is("CONSTRAINT") {
+					start -= 2
+				}
+				def, n := parseCheckClause(stmt, elem[start:])
+				if n == 0 {
+					continue
+				}
+				def.Column = elem[0].Value()
+				c.Checks = append(c.Checks, def)
+				source = blankTokens(source, stmt.Offset, elem[start:start+n])
+				i = start + n - 1
+			}
+		}
+		clauses.Columns[elem[0].Value()] = c
+	}
+	return source, clauses
+}
+
+// linkConstraints pairs the out-of-line constraints of the parsed statement with their spans.
+func (c *tableClauses) linkConstraints(ct *ast.CreateTableStmt) {
+	specs := []*ast.OutOfLineConstraint{}
+	for _, def := range ct.RelTable.TableStructs {
+		if spec, ok := def.(*ast.OutOfLineConstraint); ok {
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) != len(c.constraints) {
+		return
+	}
+	for i, spec := range specs {
+		c.Spans[spec] = c.constraints[i]
+	}
+}
+
+// elementTokens returns the tokens within the first parentheses, the relational properties of CREATE TABLE.
+func elementTokens(toks []token) []token {
+	r := newTokenReader(toks)
+	for !r.done() && !r.peek().Is("(") {
+		r.next()
+	}
+	return r.group()
+}
+
+// withSeparator extends an element of a list by the comma separating it from its neighbour,
+// so the element can be blanked without leaving an empty list item.
+func withSeparator(list, elem []token) []token {
+	start := slices.IndexFunc(list, func(t token) bool { return t.Offset == elem[0].Offset })
+	end := start + len(elem)
+	if start > 0 {
+		return list[start-1 : end]
+	}
+	if end < len(list) {
+		return list[start : end+1]
+	}
+	return elem
+}
+
+type columnClauses struct {
+	Span     SourceSpan
+	Identity *Identity
+	// Default is the DEFAULT expression, the oracle parser drops it
+	Default        *DefaultValue
+	SequenceSchema string
+	SequenceName   string
+	// References are the inline REFERENCES clauses, the oracle parser drops their target
+	References []*ast.OutOfLineConstraint
+	Checks     []checkDef
+	// UserType is the user-defined type of the column, the oracle parser gets a placeholder instead
+	UserType   *UserDatatype
+	typeTokens []token
+}
+
+func isOutOfLineConstraintStart(t token) bool {
+	return t.Is("CONSTRAINT") || t.Is("PRIMARY") || t.Is("UNIQUE") || t.Is("FOREIGN") || t.Is("CHECK")
+}
+
+// defaultExprTokens returns the tokens of a default expression up to the next column clause.
+func defaultExprTokens(toks []token) []token {
+	depth := 0
+	for i, t := range toks {
+		switch {
+		case t.Is("("):
+			depth += 1
+		case t.Is(")"):
+			depth -= 1
+		case depth > 0:
+		case t.Is("NOT"), t.Is("NULL"), t.Is("CONSTRAINT"), t.Is("PRIMARY"), t.Is("UNIQUE"),
+			t.Is("REFERENCES"), t.Is("CHECK"), t.Is("ENABLE"), t.Is("DISABLE"), t.Is("ENCRYPT"):
+			if i > 0 {
+				return toks[:i]
+			}
+		}
+	}
+	return toks
+}
+
+// parseInlineReference turns "[CONSTRAINT name] REFERENCES t [(cols)] [ON DELETE action]"
+// of a column definition into an out-of-line constraint on that column, it also returns
+// the number of tokens of the clause.
+func parseInlineReference(column token, before, toks []token) (*ast.OutOfLineConstraint, int) {
+	spec := &ast.OutOfLineConstraint{
+		Columns: []*element.Identifier{toIdentifier(column)},
+	}
+	spec.Type = ast.ConstraintTypeReferences
+	if n := len(before); n >= 2 && before[n-2].Is("CONSTRAINT") {
+		spec.Name = toIdentifier(before[n-1])
+	}
+
+	r := newTokenReader(toks[1:])
+	tableName := &ast.TableName{Table: toIdentifier(r.next())}
+	if r.peek().Is(".") {
+		r.next()
+		tableName.Schema, tableName.Table = tableName.Table, toIdentifier(r.next())
+	}
+	spec.Reference = &ast.ReferenceClause{Table: tableName}
+	for _, col := range splitTopLevel(r.group(), ",") {
+		if len(col) > 0 {
+			spec.Reference.Columns = append(spec.Reference.Columns, toIdentifier(col[0]))
+		}
+	}
+	if r.accept("ON", "DELETE") {
+		switch {
+		case r.accept("CASCADE"):
+			spec.DeleteAction = &ast.ReferenceOption{Type: ast.RefOptCascade}
+		case r.accept("SET", "NULL"):
+			spec.DeleteAction = &ast.ReferenceOption{Type: ast.RefOptSetNull}
+		}
+	}
+	return spec, r.pos + 1
+}
+
+func toIdentifier(t token) *element.Identifier {
+	if t.Kind == tokenQuotedIdent {
+		return &element.Identifier{Typ: element.IdentifierTypeQuoted, Value: t.Value()}
+	}
+	return &element.Identifier{Typ: element.IdentifierTypeNonQuoted, Value: t.Value()}
+}
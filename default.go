@@ -0,0 +1,109 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type DefaultKind int
+
+const (
+	// DefaultString is a character literal, Value holds the text without quotes
+	DefaultString DefaultKind = iota
+	DefaultNumber
+	// DefaultDate is a DATE 'yyyy-mm-dd' literal, Value holds the date
+	DefaultDate
+	// DefaultTimestamp is a TIMESTAMP '...' literal, Value holds the timestamp
+	DefaultTimestamp
+	// DefaultCurrentTime is SYSDATE, SYSTIMESTAMP, CURRENT_DATE, CURRENT_TIMESTAMP or LOCALTIMESTAMP,
+	// Value holds the function with its precision if given
+	DefaultCurrentTime
+	// DefaultNextval is seq.NEXTVAL, Value holds the sequence name
+	DefaultNextval
+	// DefaultExpression is any other expression, Value holds it as written
+	DefaultExpression
+)
+
+// DefaultValue is the DEFAULT expression of a column.
+type DefaultValue struct {
+	Kind  DefaultKind
+	Value string
+	// Schema qualifies the sequence of DefaultNextval
+	Schema string
+	// OnNull is set for DEFAULT ON NULL, the default then also replaces explicit NULLs
+	OnNull bool
+}
+
+var currentTimeFunctions = []string{"SYSDATE", "SYSTIMESTAMP", "CURRENT_DATE", "CURRENT_TIMESTAMP", "LOCALTIMESTAMP"}
+
+// parseDefault reads the expression of a DEFAULT clause, it returns nil for DEFAULT NULL.
+func parseDefault(stmt statement, expr []token, onNull bool) *DefaultValue {
+	for len(expr) > 2 && expr[0].Is("(") {
+		r := newTokenReader(expr)
+		inner := r.group()
+		if !r.done() {
+			break
+		}
+		expr = inner
+	}
+	if len(expr) == 0 || (len(expr) == 1 && expr[0].Is("NULL")) {
+		return nil
+	}
+
+	value := &DefaultValue{Kind: DefaultExpression, OnNull: onNull}
+	switch {
+	case len(expr) == 1 && expr[0].Kind == tokenString:
+		value.Kind, value.Value = DefaultString, expr[0].Value()
+	case len(expr) == 1 && expr[0].Kind == tokenNumber:
+		value.Kind, value.Value = DefaultNumber, expr[0].Text
+	case len(expr) == 2 && (expr[0].Is("-") || expr[0].Is("+")) && expr[1].Kind == tokenNumber:
+		value.Kind, value.Value = DefaultNumber, strings.TrimPrefix(expr[0].Text, "+")+expr[1].Text
+	case len(expr) == 2 && expr[0].Is("DATE") && expr[1].Kind == tokenString:
+		value.Kind, value.Value = DefaultDate, expr[1].Value()
+	case len(expr) == 2 && expr[0].Is("TIMESTAMP") && expr[1].Kind == tokenString:
+		value.Kind, value.Value = DefaultTimestamp, expr[1].Value()
+	case isCurrentTimeFunction(expr):
+		value.Kind, value.Value = DefaultCurrentTime, strings.ToUpper(strings.Join(mapping(expr, func(t token) string { return t.Text }), ""))
+	default:
+		if schema, name, ok := nextvalReference(expr); ok {
+			value.Kind, value.Schema, value.Value = DefaultNextval, schema, name
+			break
+		}
+		value.Value = stmt.Source[expr[0].Offset-stmt.Offset : expr[len(expr)-1].End()-stmt.Offset]
+	}
+	return value
+}
+
+// isCurrentTimeFunction recognizes SYSDATE and the like, optionally followed by a precision.
+func isCurrentTimeFunction(expr []token) bool {
+	if !slices.ContainsFunc(currentTimeFunctions, expr[0].Is) {
+		return false
+	}
+	switch len(expr) {
+	case 1:
+		return true
+	case 4:
+		return expr[1].Is("(") && expr[2].Kind == tokenNumber && expr[3].Is(")")
+	}
+	return false
+}
+
+// OracleSQL prints the default expression as Oracle SQL, without the DEFAULT keyword.
+func (d *DefaultValue) OracleSQL() string {
+	switch d.Kind {
+	case DefaultString:
+		return quoteString(d.Value)
+	case DefaultDate:
+		return "DATE " + quoteString(d.Value)
+	case DefaultTimestamp:
+		return "TIMESTAMP " + quoteString(d.Value)
+	case DefaultNextval:
+		return qualifiedName(d.Schema, d.Value) + ".NEXTVAL"
+	}
+	return d.Value
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,159 @@
+package ddlcode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	// StatementIndex is -1 for the diagnostics of generators, which report on the model
+	StatementIndex int    `json:"statement_index"`
+	File           string `json:"file,omitempty"`
+	Line           int    `json:"line"`
+	Column         int    `json:"column"`
+	// EndLine and EndColumn end the statement or the definition the diagnostic is about
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	Message   string `json:"message"`
+}
+
+type ParseOptions struct {
+	// WarningsAsErrors makes ParseWithOptions return an error when any warning is reported.
+	WarningsAsErrors bool
+	// Recover keeps parsing the remaining statements when one of them is rejected by the parser,
+	// the rejected statements are reported as errors.
+	Recover bool
+	// SkipSequenceNaming disables linking a sequence named <TABLE>_SEQ to the primary key of TABLE.
+	SkipSequenceNaming bool
+	// FileName is reported in diagnostics and source spans, ParseFiles and ParseFS name the files themselves.
+	FileName string
+	// FileOrder is the order ParseFiles and ParseFS parse the files in.
+	FileOrder FileOrder
+	// TableOrder is the order of Database.Tables, the columns, keys and indexes of Database follow it.
+	TableOrder TableOrder
+	// DefaultSchema is the schema of unqualified table names. When empty, unqualified names have no schema
+	// and match a qualified name unless that is ambiguous.
+	DefaultSchema string
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return "unknown"
+}
+
+func (d Diagnostic) String() string {
+	if d.File != "" {
+		return fmt.Sprintf("%v:%v:%v: %v: %v", d.File, d.Line, d.Column, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%v:%v: %v: %v", d.Line, d.Column, d.Severity, d.Message)
+}
+
+type diagnostics []Diagnostic
+
+func (ds *diagnostics) add(severity Severity, stmt statement, span SourceSpan, format string, args ...any) {
+	if span.Line == 0 {
+		span = stmt.span()
+	}
+	*ds = append(*ds, Diagnostic{
+		Severity:       severity,
+		StatementIndex: stmt.Index,
+		File:           stmt.File,
+		Line:           span.Line,
+		Column:         span.Column,
+		EndLine:        span.EndLine,
+		EndColumn:      span.EndColumn,
+		Message:        fmt.Sprintf(format, args...),
+	})
+}
+
+func (ds *diagnostics) warnf(stmt statement, format string, args ...any) {
+	ds.add(SeverityWarning, stmt, SourceSpan{}, format, args...)
+}
+
+// warnAt reports a warning on a definition within the statement, or on the statement when the span is unknown.
+func (ds *diagnostics) warnAt(stmt statement, span SourceSpan, format string, args ...any) {
+	ds.add(SeverityWarning, stmt, span, format, args...)
+}
+
+// warnModel reports a warning on a definition of the model, generators have no statement to report on.
+func (ds *diagnostics) warnModel(span SourceSpan, format string, args ...any) {
+	*ds = append(*ds, Diagnostic{
+		Severity:       SeverityWarning,
+		StatementIndex: -1,
+		File:           span.File,
+		Line:           span.Line,
+		Column:         span.Column,
+		EndLine:        span.EndLine,
+		EndColumn:      span.EndColumn,
+		Message:        fmt.Sprintf(format, args...),
+	})
+}
+
+func (ds diagnostics) count(severity Severity) int {
+	n := 0
+	for _, d := range ds {
+		if d.Severity == severity {
+			n += 1
+		}
+	}
+	return n
+}
+
+var syntaxErrorPattern = regexp.MustCompile(`at line (\d+):(\d+)`)
+
+// syntaxError reports an error at the token in the format of the oracle parser,
+// so statements parsed by ddlcode itself get the same diagnostics.
+func syntaxError(stmt statement, t token, format string, args ...any) error {
+	return fmt.Errorf("syntax error, %v, at line %v:%v", fmt.Sprintf(format, args...), t.Line-stmt.Line+1, t.Offset-stmt.Offset)
+}
+
+// syntaxErrorDiagnostic converts an error of the oracle parser into a diagnostic,
+// the parser reports the line and the byte offset of the offending token within the statement.
+func syntaxErrorDiagnostic(stmt statement, err error) Diagnostic {
+	span := stmt.span()
+	d := Diagnostic{
+		Severity:       SeverityError,
+		StatementIndex: stmt.Index,
+		File:           stmt.File,
+		Line:           span.Line,
+		Column:         span.Column,
+		EndLine:        span.EndLine,
+		EndColumn:      span.EndColumn,
+		Message:        err.Error(),
+	}
+
+	m := syntaxErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return d
+	}
+	line, _ := strconv.Atoi(m[1])
+	offset, _ := strconv.Atoi(m[2])
+	if line < 1 || offset > len(stmt.Source) {
+		return d
+	}
+	d.Line = stmt.Line + line - 1
+	if line == 1 {
+		d.Column = stmt.Column + offset
+	} else {
+		d.Column = offset - strings.LastIndex(stmt.Source[:offset], "\n")
+	}
+	return d
+}
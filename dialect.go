@@ -0,0 +1,698 @@
+package ddlcode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+// Dialect is a database the schema is translated to.
+type Dialect int
+
+const (
+	DialectPostgres Dialect = iota
+	DialectMySQL
+	DialectSQLite
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "PostgreSQL"
+	case DialectMySQL:
+		return "MySQL"
+	case DialectSQLite:
+		return "SQLite"
+	}
+	return "unknown"
+}
+
+// oracleOnlyWords are the functions and keywords of Oracle SQL the other dialects do not know or read otherwise.
+var oracleOnlyWords = []string{
+	"NVL", "NVL2", "DECODE", "SYSDATE", "SYSTIMESTAMP", "ROWNUM", "ROWID", "DUAL", "MINUS", "CONNECT", "PRIOR",
+	"INSTR", "SUBSTRB", "LENGTHB", "ADD_MONTHS", "MONTHS_BETWEEN", "LAST_DAY", "LISTAGG", "REGEXP_LIKE", "BITAND",
+}
+
+// portableFunctions are the functions the dialects compute as Oracle does, expressions kept as written which call
+// other functions are reported. SUBSTR is not among them as Oracle reads the position 0 as 1.
+var portableFunctions = []string{
+	"UPPER", "LOWER", "TRIM", "ABS", "ROUND", "COALESCE", "NULLIF", "CAST", "LENGTH",
+	"COUNT", "SUM", "MIN", "MAX", "AVG", "ROW_NUMBER", "RANK", "DENSE_RANK",
+}
+
+// dialectOnlyReadings are the operators and the portable functions a dialect reads otherwise than Oracle:
+// MySQL takes || for OR and counts the LENGTH of a string in bytes.
+var dialectOnlyReadings = map[Dialect][]string{
+	DialectMySQL: {"||", "LENGTH"},
+}
+
+// expressionKeywords may precede a parenthesis which is no function call, such as IN (...) or EXISTS (...).
+var expressionKeywords = []string{
+	"AND", "OR", "NOT", "IN", "EXISTS", "ANY", "ALL", "SOME", "AS", "ON", "USING", "SELECT", "DISTINCT", "FROM",
+	"WHERE", "BY", "HAVING", "JOIN", "UNION", "INTERSECT", "EXCEPT", "CASE", "WHEN", "THEN", "ELSE", "BETWEEN",
+	"LIKE", "OVER", "VALUES",
+}
+
+var lowerCaseIdentifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// maxIdentifierLengths are the lengths the dialects truncate or reject longer names at.
+var maxIdentifierLengths = map[Dialect]int{
+	DialectPostgres: 63,
+	DialectMySQL:    64,
+}
+
+// CheckConformance reports the definitions of the schema the generator of the dialect leaves out or changes,
+// as the dialect cannot express them or ddlcode does not translate them. version is the version of the target
+// database such as "5.7", the latest when empty, only the MySQL features depend on it.
+func CheckConformance(db Database, dialect Dialect, version string) []Diagnostic {
+	diags := diagnostics{}
+	checkName := func(span SourceSpan, name string) {
+		if max, ok := maxIdentifierLengths[dialect]; ok && len(name) > max {
+			diags.warnModel(span, "name %v is longer than %v characters, the limit of %v", name, max, dialect)
+		}
+	}
+
+	for _, seq := range db.Sequences {
+		switch {
+		case dialect != DialectPostgres:
+			diags.warnModel(SourceSpan{}, "sequence %v left out, %v has no sequences", seq.Name, dialect)
+		case exceedsBigint(seq.MinValue), exceedsBigint(seq.MaxValue):
+			diags.warnModel(SourceSpan{}, "bounds of sequence %v beyond bigint left out", seq.Name)
+		}
+	}
+
+	schemas := []string{}
+	for _, table := range db.Tables {
+		if dialect == DialectSQLite && table.Schema != "" && !slices.Contains(schemas, table.Schema) {
+			schemas = append(schemas, table.Schema)
+			diags.warnModel(table.Span, "schema %v left out, SQLite schemas are attached databases", table.Schema)
+		}
+		checkName(table.Span, table.Table)
+		if strings.HasSuffix(table.Type, " temporary table") {
+			diags.warnModel(table.Span, "%v %v created as a regular table, %v temporary tables belong to a session", table.Type, table.Table, dialect)
+		}
+		if table.Engine != "" && table.Engine != "HEAP" {
+			diags.warnModel(table.Span, "ORGANIZATION %v of %v left out", table.Engine, table.Table)
+		}
+		if table.Tablespace != "" || len(table.Storage) > 0 {
+			diags.warnModel(table.Span, "tablespace and storage of %v left out", table.Table)
+		}
+		if table.Partitioning != nil {
+			diags.warnModel(table.Span, "partitioning of %v left out", table.Table)
+		}
+		if dialect == DialectSQLite && (table.Comment != "" || slices.ContainsFunc(table.Columns, func(c *Column) bool { return c.Comment != "" })) {
+			diags.warnModel(table.Span, "comments of %v left out, SQLite has no comments", table.Table)
+		}
+		if dialect == DialectMySQL && !versionAtLeast(version, "8.0.16") {
+			for _, check := range table.CheckConstraints {
+				diags.warnModel(check.Span, "check constraint of %v ignored by MySQL before 8.0.16", table.Table)
+			}
+		}
+
+		autoIncrement := autoIncrementColumn(table, dialect)
+		for _, col := range table.Columns {
+			checkName(col.Span, col.Name)
+			if col.Identity != nil && col.Identity.Generation == "BY DEFAULT ON NULL" || col.DefaultValue != nil && col.DefaultValue.OnNull {
+				diags.warnModel(col.Span, "ON NULL of %v.%v left out, %v only applies defaults to omitted columns", table.Table, col.Name, dialect)
+			}
+			if dialect == DialectMySQL && col.DataType.DataDef() == element.DataDefTimestamp {
+				if datatype := col.DataType.(*element.Timestamp); datatype.WithTimeZone || datatype.WithLocalTimeZone {
+					diags.warnModel(col.Span, "time zone of %v.%v left out, MySQL DATETIME has none", table.Table, col.Name)
+				}
+			}
+			if dialect == DialectMySQL && !versionAtLeast(version, "8.0.13") && col.DefaultValue != nil && col.DefaultValue.Kind == DefaultExpression {
+				diags.warnModel(col.Span, "expression default of %v.%v needs MySQL 8.0.13", table.Table, col.Name)
+			}
+			if dialect == DialectPostgres || !isNumbered(col) {
+				continue
+			}
+			switch {
+			case col != autoIncrement && dialect == DialectMySQL:
+				diags.warnModel(col.Span, "numbering of %v.%v left out, MySQL only numbers a single key column", table.Table, col.Name)
+			case col != autoIncrement:
+				diags.warnModel(col.Span, "numbering of %v.%v left out, SQLite only numbers an integer primary key", table.Table, col.Name)
+			case col.Identity == nil:
+			case dialect == DialectMySQL && !isDefaultIdentity(col.Identity.SequenceOptions, true):
+				diags.warnModel(col.Span, "identity options of %v.%v other than START WITH left out", table.Table, col.Name)
+			case dialect == DialectSQLite && !isDefaultIdentity(col.Identity.SequenceOptions, false):
+				diags.warnModel(col.Span, "identity options of %v.%v left out", table.Table, col.Name)
+			}
+		}
+
+		for _, index := range table.Indexes {
+			checkName(index.Span, index.Name)
+			if index.Bitmap {
+				diags.warnModel(index.Span, "bitmap index %v created as a b-tree index", index.Name)
+			}
+			if dialect != DialectSQLite && index.Schema != "" && index.Schema != table.Schema {
+				diags.warnModel(index.Span, "index %v created in the schema of table %v", index.Name, table.Table)
+			}
+			if dialect != DialectMySQL {
+				continue
+			}
+			if !versionAtLeast(version, "8.0.13") && slices.ContainsFunc(index.Columns, func(c IndexColumn) bool { return c.Column == nil }) {
+				diags.warnModel(index.Span, "expression index %v needs MySQL 8.0.13", index.Name)
+			}
+			if !versionAtLeast(version, "8.0") && slices.ContainsFunc(index.Columns, func(c IndexColumn) bool { return c.Direction == "DESC" }) {
+				diags.warnModel(index.Span, "descending index %v is ascending before MySQL 8.0", index.Name)
+			}
+		}
+	}
+
+	for _, view := range db.Views {
+		if view.Materialized && dialect != DialectPostgres {
+			diags.warnModel(view.Span, "materialized view %v created as a view, %v has no materialized views", view.Name, dialect)
+		}
+	}
+	for _, trigger := range db.Triggers {
+		diags.warnModel(trigger.Span, "trigger %v not translated", trigger.Name)
+	}
+	for _, synonym := range db.Synonyms {
+		diags.warnModel(synonym.Span, "synonym %v left out, %v has no synonyms", synonym.Name, dialect)
+	}
+	for _, pkg := range db.Packages {
+		diags.warnModel(pkg.Span, "package %v left out, %v has no packages", pkg.Name, dialect)
+	}
+	for _, routine := range db.Routines {
+		diags.warnModel(routine.Span, "%v %v not translated", strings.ToLower(routine.Kind), routine.Name)
+	}
+	for _, userType := range db.UserTypes {
+		diags.warnModel(userType.Span, "type %v not translated", userType.Name)
+	}
+	return diags
+}
+
+// exceedsBigint reports whether an integer given as text is beyond the range of a 64 bit integer.
+func exceedsBigint(value string) bool {
+	digits := strings.TrimPrefix(value, "-")
+	return len(digits) > 19 || len(digits) == 19 && digits > "9223372036854775807"
+}
+
+// versionAtLeast compares dotted versions, an empty version is the latest.
+func versionAtLeast(version, minimum string) bool {
+	if version == "" {
+		return true
+	}
+	parts, minimums := strings.Split(version, "."), strings.Split(minimum, ".")
+	for i, part := range minimums {
+		x, y := 0, 0
+		if i < len(parts) {
+			x, _ = strconv.Atoi(parts[i])
+		}
+		y, _ = strconv.Atoi(part)
+		if x != y {
+			return x > y
+		}
+	}
+	return true
+}
+
+// isDefaultIdentity reports whether the identity sets nothing MySQL or SQLite would lose, startWith keeps the
+// start value as MySQL does. The cache and the order only tune the numbering.
+func isDefaultIdentity(opts SequenceOptions, startWith bool) bool {
+	if startWith {
+		opts.StartWith = ""
+	}
+	if opts.IncrementBy == "1" {
+		opts.IncrementBy = ""
+	}
+	opts.Cache, opts.Order = "", false
+	return opts == SequenceOptions{}
+}
+
+// isNumbered reports whether the column is an identity or is filled from a sequence, by its default, by a trigger
+// or by the naming convention.
+func isNumbered(col *Column) bool {
+	return col.Identity != nil || col.Sequence != nil || col.DefaultValue != nil && col.DefaultValue.Kind == DefaultNextval
+}
+
+// autoIncrementColumn returns the column MySQL or SQLite numbers for the identity column or the primary key
+// filled from a sequence. MySQL numbers a single column of the primary key or of a unique constraint,
+// SQLite the only column of an integer primary key. It returns nil for PostgreSQL, which keeps identities.
+func autoIncrementColumn(table *Table, dialect Dialect) *Column {
+	if dialect == DialectPostgres {
+		return nil
+	}
+	pk := primaryKeyColumns(table)
+	for _, col := range table.Columns {
+		if !isNumbered(col) || col.Identity == nil && (len(pk) != 1 || pk[0] != col) {
+			continue
+		}
+		switch dialect {
+		case DialectMySQL:
+			if slices.Contains(pk, col) || slices.ContainsFunc(table.UniqueConstraints, func(uc *UniqueConstraint) bool {
+				return len(uc.Columns) == 1 && uc.Columns[0] == col
+			}) {
+				return col
+			}
+		case DialectSQLite:
+			if len(pk) == 1 && pk[0] == col && toSQLiteTypeName(col.DataType) == "integer" {
+				return col
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// dialectWriter prints the definitions of the model in the SQL of a dialect and collects the expressions
+// and the types it cannot translate, CheckConformance reports the rest.
+type dialectWriter struct {
+	dialect Dialect
+	indent  string
+	// types overrides the type mapping, see PostgresConfig.Types
+	types map[string]string
+	// engine is the storage engine of the MySQL tables
+	engine  string
+	version string
+	diags   diagnostics
+}
+
+// script prints the schemas, the sequences, the tables referenced tables first, the foreign keys closing a cycle,
+// the indexes, the comments and the views.
+func (w *dialectWriter) script(db Database) string {
+	sections := [][]string{}
+
+	schemas := []string{}
+	if w.dialect != DialectSQLite {
+		schemas = append(schemas, mapping(db.Sequences, func(s *Sequence) string { return s.Schema })...)
+		for _, table := range db.Tables {
+			schemas = append(schemas, table.Schema)
+		}
+		for _, view := range db.Views {
+			schemas = append(schemas, view.Schema)
+		}
+	}
+	created := []string{}
+	for _, schema := range schemas {
+		statement := "CREATE SCHEMA IF NOT EXISTS " + w.identifier(schema)
+		if schema != "" && !slices.Contains(created, statement) {
+			created = append(created, statement)
+		}
+	}
+	sections = append(sections, created)
+
+	sequences := []string{}
+	if w.dialect == DialectPostgres {
+		for _, seq := range db.Sequences {
+			sequences = append(sequences, "CREATE SEQUENCE "+w.objectName(seq.Schema, seq.Name)+w.sequenceOptions(seq.SequenceOptions))
+		}
+	}
+	sections = append(sections, sequences)
+
+	tables := sortByForeignKeys(sortTables(slices.Clone(db.Tables), TableOrderAlphabetical))
+	inline, deferred := foreignKeysAtCreation(tables, false)
+	if w.dialect == DialectSQLite {
+		// SQLite cannot add a foreign key to a table and does not check the referenced table exists
+		inline, deferred = map[*Table][]*ForeignKey{}, nil
+		for _, table := range tables {
+			inline[table] = table.ForeignKeys
+		}
+	}
+	created, added := []string{}, []string{}
+	for _, table := range tables {
+		created = append(created, w.createTable(table, inline[table]))
+	}
+	for _, fk := range deferred {
+		added = append(added, fmt.Sprintf("ALTER TABLE %v ADD %v", w.tableName(fk.Table), w.foreignKey(fk)))
+	}
+	sections = append(sections, created, added)
+
+	indexes, comments := []string{}, []string{}
+	for _, table := range tables {
+		for _, index := range table.Indexes {
+			indexes = append(indexes, w.createIndex(index))
+		}
+		if w.dialect != DialectPostgres {
+			continue
+		}
+		if table.Comment != "" {
+			comments = append(comments, fmt.Sprintf("COMMENT ON TABLE %v IS %v", w.tableName(table), quoteString(table.Comment)))
+		}
+		for _, col := range table.Columns {
+			if col.Comment != "" {
+				comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %v.%v IS %v", w.tableName(table), w.identifier(col.Name), quoteString(col.Comment)))
+			}
+		}
+	}
+	sections = append(sections, indexes, comments)
+
+	views := []string{}
+	for _, view := range sortViews(db.Views) {
+		w.checkExpression(view.Query, view.Span, "view %v", view.Name)
+		kind := "VIEW"
+		if view.Materialized && w.dialect == DialectPostgres {
+			kind = "MATERIALIZED VIEW"
+		}
+		views = append(views, fmt.Sprintf("CREATE %v %v AS\n%v", kind, w.objectName(view.Schema, view.Name), strings.TrimSpace(view.Query)))
+	}
+	sections = append(sections, views)
+
+	content := strings.Builder{}
+	for _, statements := range sections {
+		if len(statements) == 0 {
+			continue
+		}
+		if content.Len() > 0 {
+			content.WriteString("\n")
+		}
+		for _, statement := range statements {
+			fmt.Fprintf(&content, "%v;\n", statement)
+		}
+	}
+	return content.String()
+}
+
+// identifier folds a name Oracle takes unquoted to lower case, names which are not valid unquoted
+// identifiers or are reserved words of the dialect are quoted.
+func (w *dialectWriter) identifier(name string) string {
+	if unquotedIdentifierPattern.MatchString(name) {
+		name = strings.ToLower(name)
+	}
+	switch w.dialect {
+	case DialectMySQL:
+		if !lowerCaseIdentifierPattern.MatchString(name) || slices.Contains(mysqlReservedWords, name) {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+	case DialectSQLite:
+		if !lowerCaseIdentifierPattern.MatchString(name) || slices.Contains(sqliteKeywords, name) {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+	default:
+		if !lowerCaseIdentifierPattern.MatchString(name) || slices.Contains(postgresReservedWords, name) {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+	}
+	return name
+}
+
+// objectName qualifies the name by the schema, SQLite names are left unqualified.
+func (w *dialectWriter) objectName(schema, name string) string {
+	if schema == "" || w.dialect == DialectSQLite {
+		return w.identifier(name)
+	}
+	return w.identifier(schema) + "." + w.identifier(name)
+}
+
+func (w *dialectWriter) tableName(table *Table) string {
+	return w.objectName(table.Schema, table.Table)
+}
+
+func (w *dialectWriter) columnNames(columns []*Column) string {
+	return strings.Join(mapping(columns, func(c *Column) string { return w.identifier(c.Name) }), ", ")
+}
+
+func (w *dialectWriter) constraintName(name, text string) string {
+	if name == "" {
+		return text
+	}
+	return "CONSTRAINT " + w.identifier(name) + " " + text
+}
+
+// quote quotes a string literal, MySQL also reads backslashes as escapes.
+func (w *dialectWriter) quote(s string) string {
+	if w.dialect == DialectMySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return quoteString(s)
+}
+
+// checkExpression reports what an expression kept as written uses besides names, literals, operators and
+// portableFunctions: the calls of other functions, the Oracle keywords, the (+) outer joins and what the
+// dialect reads otherwise.
+func (w *dialectWriter) checkExpression(expression string, span SourceSpan, format string, args ...any) {
+	found := []string{}
+	add := func(text string) {
+		if !slices.Contains(found, text) {
+			found = append(found, text)
+		}
+	}
+	toks := tokenize(expression)
+	isOuterJoin := func(i int) bool {
+		return i+2 < len(toks) && toks[i].Is("(") && toks[i+1].Is("+") && toks[i+2].Is(")")
+	}
+	for i, t := range toks {
+		word := t.Value()
+		isCall := i+1 < len(toks) && toks[i+1].Is("(") && !isOuterJoin(i+1)
+		switch {
+		case t.Kind == tokenPunct && slices.Contains(dialectOnlyReadings[w.dialect], word):
+			add(word)
+		case t.Kind != tokenWord:
+		case isCall && slices.Contains(dialectOnlyReadings[w.dialect], word):
+			add(word)
+		case slices.Contains(oracleOnlyWords, word):
+			add(word)
+		case isCall && !slices.Contains(portableFunctions, word) && !slices.Contains(expressionKeywords, word):
+			add(word)
+		}
+		if isOuterJoin(i) {
+			add("(+)")
+		}
+	}
+	if len(found) > 0 {
+		w.diags.warnModel(span, "%v uses %v, kept as written", fmt.Sprintf(format, args...), strings.Join(found, ", "))
+	}
+}
+
+// column prints the column with its type, its identity, auto increment or default, NOT NULL and,
+// for MySQL, its comment. autoIncrement is the column MySQL or SQLite numbers.
+func (w *dialectWriter) column(table *Table, col *Column, autoIncrement *Column) string {
+	name := w.identifier(col.Name)
+	if col == autoIncrement && w.dialect == DialectSQLite {
+		return name + " integer PRIMARY KEY AUTOINCREMENT"
+	}
+	columnType := w.columnType(table, col)
+	if col == autoIncrement && !isMySQLIntegerType(columnType) {
+		w.diags.warnModel(col.Span, "type %v of %v.%v mapped to bigint, MySQL only numbers integer columns", columnType, table.Table, col.Name)
+		columnType = "bigint"
+	}
+	text := name + " " + columnType
+	switch {
+	case col.Identity != nil && w.dialect == DialectPostgres:
+		generation := col.Identity.Generation
+		if generation == "BY DEFAULT ON NULL" {
+			generation = "BY DEFAULT"
+		}
+		text += fmt.Sprintf(" GENERATED %v AS IDENTITY", generation)
+		if options := w.sequenceOptions(col.Identity.SequenceOptions); options != "" {
+			text += " (" + strings.TrimSpace(options) + ")"
+		}
+	case col == autoIncrement:
+		text += " AUTO_INCREMENT"
+	case col.Identity != nil:
+	case col.Sequence != nil && w.dialect == DialectPostgres && (col.DefaultValue == nil || col.DefaultValue.Kind != DefaultNextval):
+		// the column is filled by a trigger or named after the sequence, the default takes their place
+		text += fmt.Sprintf(" DEFAULT nextval(%v)", quoteString(w.objectName(col.Sequence.Schema, col.Sequence.Name)))
+	case col.DefaultValue != nil:
+		value, ok := w.defaultValue(table, col)
+		if ok && w.dialect == DialectMySQL && isMySQLTextType(columnType) && col.DefaultValue.Kind != DefaultExpression {
+			// MySQL only takes expression defaults for TEXT and BLOB columns, from 8.0.13 on
+			value = "(" + value + ")"
+			if !versionAtLeast(w.version, "8.0.13") {
+				w.diags.warnModel(col.Span, "default of %v.%v needs MySQL 8.0.13, TEXT and BLOB columns only take expression defaults", table.Table, col.Name)
+			}
+		}
+		if ok {
+			text += " DEFAULT " + value
+		}
+	}
+	if !col.IsNullable() && !col.Attribute.IsPrimaryKey() {
+		text += " NOT NULL"
+	}
+	if col.Comment != "" && w.dialect == DialectMySQL {
+		text += " COMMENT " + w.quote(col.Comment)
+	}
+	return text
+}
+
+// columnType maps the type of the column through the overrides and the mapping of the dialect,
+// a type which cannot be mapped becomes text.
+func (w *dialectWriter) columnType(table *Table, col *Column) string {
+	name, ok := w.mappedType(col)
+	if !ok {
+		w.diags.warnModel(col.Span, "type %v of %v.%v has no %v equivalent, mapped to %v", toSqlType(col.DataType), table.Table, col.Name, w.dialect, name)
+	}
+	return name
+}
+
+// mappedType is the type columnType maps the column to, it reports false with the text type taking
+// the place of a type which cannot be mapped.
+func (w *dialectWriter) mappedType(col *Column) (string, bool) {
+	oracleType := toSqlType(col.DataType)
+	if name, ok := w.types[oracleType]; ok {
+		return name, true
+	}
+	if name, ok := w.types[sizePattern.ReplaceAllString(oracleType, "")]; ok {
+		return name, true
+	}
+	switch w.dialect {
+	case DialectMySQL:
+		if name, ok := toMySQLType(col.DataType); ok {
+			return name, true
+		}
+		return "longtext", false
+	case DialectSQLite:
+		if name := toSQLiteTypeName(col.DataType); name != "" {
+			return name, true
+		}
+	default:
+		if name, ok := toPostgresType(col.DataType); ok {
+			return name, true
+		}
+	}
+	return "text", false
+}
+
+// keyColumnNames prints the columns of a key or an index of the table. MySQL only indexes a prefix of TEXT and
+// BLOB columns, the prefix is as long as an index key of the older row formats takes in utf8mb4.
+func (w *dialectWriter) keyColumnNames(table *Table, columns []*Column, key string) string {
+	names := []string{}
+	for _, col := range columns {
+		name := w.identifier(col.Name)
+		if columnType, _ := w.mappedType(col); w.dialect == DialectMySQL && isMySQLTextType(columnType) {
+			name += fmt.Sprintf("(%v)", mysqlIndexPrefix)
+			w.diags.warnModel(col.Span, "%v indexes a prefix of %v characters or bytes of %v.%v, MySQL cannot index whole TEXT and BLOB columns", key, mysqlIndexPrefix, table.Table, col.Name)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// defaultValue translates the default of the column, current time functions to their counterpart in the dialect
+// and NEXTVAL to nextval() for PostgreSQL. It reports false for NEXTVAL in the other dialects, which number
+// the column by auto increment if at all. Other expressions are kept as written.
+func (w *dialectWriter) defaultValue(table *Table, col *Column) (string, bool) {
+	value := col.DefaultValue
+	switch value.Kind {
+	case DefaultString:
+		return w.quote(value.Value), true
+	case DefaultDate, DefaultTimestamp:
+		if w.dialect == DialectPostgres {
+			return value.OracleSQL(), true
+		}
+		return w.quote(value.Value), true
+	case DefaultCurrentTime:
+		name, precision, _ := strings.Cut(value.Value, "(")
+		if precision != "" {
+			precision = "(" + precision
+		}
+		switch w.dialect {
+		case DialectMySQL:
+			// the precision must be the one of the column
+			if digits := mysqlFractionalSeconds(col.DataType); digits > 0 {
+				return fmt.Sprintf("CURRENT_TIMESTAMP(%v)", digits), true
+			}
+			return "CURRENT_TIMESTAMP", true
+		case DialectSQLite:
+			return "CURRENT_TIMESTAMP", true
+		}
+		switch name {
+		case "SYSDATE", "CURRENT_DATE":
+			return "LOCALTIMESTAMP(0)", true
+		case "SYSTIMESTAMP":
+			return "CURRENT_TIMESTAMP" + precision, true
+		}
+		return value.Value, true
+	case DefaultNextval:
+		if w.dialect != DialectPostgres {
+			return "", false
+		}
+		return fmt.Sprintf("nextval(%v)", quoteString(w.objectName(value.Schema, value.Value))), true
+	case DefaultExpression:
+		w.checkExpression(value.Value, col.Span, "default of %v.%v", table.Table, col.Name)
+		if w.dialect != DialectPostgres {
+			return "(" + value.Value + ")", true
+		}
+	}
+	return value.OracleSQL(), true
+}
+
+// sequenceOptions prints the options PostgreSQL takes with a leading space, ORDER and bounds beyond bigint
+// are left out.
+func (w *dialectWriter) sequenceOptions(opts SequenceOptions) string {
+	if exceedsBigint(opts.MinValue) {
+		opts.MinValue = ""
+	}
+	if exceedsBigint(opts.MaxValue) {
+		opts.MaxValue = ""
+	}
+	opts.Order = false
+	if options := sequenceOptionsText(opts); options != "" {
+		return " " + options
+	}
+	return ""
+}
+
+// createTable prints CREATE TABLE with the columns, the keys, the unique and check constraints and the given
+// foreign keys, MySQL tables also get their engine, their start value and their comment.
+func (w *dialectWriter) createTable(table *Table, foreignKeys []*ForeignKey) string {
+	autoIncrement := autoIncrementColumn(table, w.dialect)
+	elements := []string{}
+	for _, col := range table.Columns {
+		elements = append(elements, w.column(table, col, autoIncrement))
+	}
+	if pk := primaryKeyColumns(table); len(pk) > 0 && (autoIncrement == nil || w.dialect != DialectSQLite) {
+		columns := w.keyColumnNames(table, pk, "primary key of "+table.Table)
+		elements = append(elements, w.constraintName(table.PrimaryKeyName, fmt.Sprintf("PRIMARY KEY (%v)", columns)))
+	}
+	for _, uc := range table.UniqueConstraints {
+		columns := w.keyColumnNames(table, uc.Columns, "unique constraint of "+table.Table)
+		elements = append(elements, w.constraintName(uc.Name, fmt.Sprintf("UNIQUE (%v)", columns)))
+	}
+	for _, check := range table.CheckConstraints {
+		w.checkExpression(check.Expression, check.Span, "check constraint of %v", table.Table)
+		elements = append(elements, w.constraintName(check.Name, fmt.Sprintf("CHECK (%v)", check.Expression)))
+	}
+	for _, fk := range foreignKeys {
+		elements = append(elements, w.foreignKey(fk))
+	}
+
+	text := fmt.Sprintf("CREATE TABLE %v (\n%v%v\n)", w.tableName(table), w.indent, strings.Join(elements, ",\n"+w.indent))
+	if w.dialect == DialectMySQL {
+		if w.engine != "" {
+			text += " ENGINE=" + w.engine
+		}
+		if autoIncrement != nil && autoIncrement.Identity != nil && autoIncrement.Identity.StartWith != "" {
+			text += " AUTO_INCREMENT=" + autoIncrement.Identity.StartWith
+		}
+		if table.Comment != "" {
+			text += " COMMENT=" + w.quote(table.Comment)
+		}
+	}
+	return text
+}
+
+func (w *dialectWriter) foreignKey(fk *ForeignKey) string {
+	text := fmt.Sprintf("FOREIGN KEY (%v) REFERENCES %v (%v)", w.columnNames(fk.Columns), w.tableName(fk.RefTable), w.columnNames(fk.RefColumns))
+	if fk.OnDelete != "" {
+		text += " ON DELETE " + fk.OnDelete
+	}
+	return w.constraintName(fk.Name, text)
+}
+
+// createIndex prints CREATE INDEX, the index goes to the schema of its table and bitmap indexes become b-tree ones.
+func (w *dialectWriter) createIndex(index *Index) string {
+	kind := "INDEX"
+	if index.Unique {
+		kind = "UNIQUE INDEX"
+	}
+	columns := []string{}
+	for _, col := range index.Columns {
+		name := col.Expression
+		if col.Column != nil {
+			name = w.keyColumnNames(index.Table, []*Column{col.Column}, "index "+index.Name)
+		} else {
+			w.checkExpression(col.Expression, index.Span, "index %v", index.Name)
+			name = "(" + name + ")"
+		}
+		if col.Direction != "" {
+			name += " " + col.Direction
+		}
+		columns = append(columns, name)
+	}
+	return fmt.Sprintf("CREATE %v %v ON %v (%v)", kind, w.identifier(index.Name), w.tableName(index.Table), strings.Join(columns, ", "))
+}
@@ -0,0 +1,67 @@
+package ddlcode
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// diagnosticLines prints the diagnostics of a generator one per line.
+func diagnosticLines(diags []Diagnostic) string {
+	lines := strings.Builder{}
+	for _, d := range diags {
+		lines.WriteString(d.String() + "\n")
+	}
+	return lines.String()
+}
+
+func TestExpressionWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "portable expressions",
+			sql: `CREATE TABLE t (id NUMBER PRIMARY KEY, a NUMBER, name VARCHAR2(20) DEFAULT UPPER('x'),
+  CHECK (a >= 0 AND name IS NOT NULL), CHECK (LENGTH(TRIM(name)) BETWEEN 1 AND 10), CHECK (a IN (1, 2)));
+CREATE INDEX ix_t_name ON t (UPPER(name), ABS(a));
+CREATE VIEW v AS SELECT id, COALESCE(name, 'none') AS name, COUNT(*) OVER (PARTITION BY a) AS n
+FROM t WHERE EXISTS (SELECT id FROM t) AND a IN (SELECT CAST(id AS INTEGER) FROM t);`,
+			want: []string{},
+		},
+		{
+			name: "Oracle functions",
+			sql: `CREATE TABLE t (id NUMBER PRIMARY KEY, a NUMBER, d DATE, name VARCHAR2(20),
+  code VARCHAR2(10) DEFAULT DECODE(1, 1, 'X', 'Y'),
+  CHECK (NVL2(a, 1, 0) = 1 AND NVL(a, 0) >= 0));
+CREATE INDEX ix_t_year ON t (TO_CHAR(d, 'YYYY'));
+CREATE VIEW v AS SELECT id, SUBSTR(name, 1, 2) AS short FROM t;`,
+			want: []string{
+				"default of T.CODE uses DECODE, kept as written",
+				"check constraint of T uses NVL2, NVL, kept as written",
+				"index IX_T_YEAR uses TO_CHAR, kept as written",
+				"view V uses SUBSTR, kept as written",
+			},
+		},
+		{
+			name: "Oracle keywords and outer joins",
+			sql: `CREATE TABLE t (id NUMBER PRIMARY KEY, a NUMBER);
+CREATE VIEW v AS SELECT x.id, SYSDATE AS now FROM t x, t y WHERE x.id = y.a (+) AND ROWNUM < 10;`,
+			want: []string{"view V uses SYSDATE, (+), ROWNUM, kept as written"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags, err := GeneratePostgres(parseSchema(t, tt.sql), GetDefaultPostgresConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.want) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package ddlcode
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+// SchemaDiff is what changed from an old schema to a new one.
+type SchemaDiff struct {
+	// AddedTables follow the order of the new schema, DroppedTables that of the old one
+	AddedTables   []*Table
+	DroppedTables []*Table
+	// Tables are the tables of both schemas which are renamed or changed, in the order of the new schema
+	Tables []*TableDiff
+}
+
+// TableDiff is what changed in a table, Old and New have different names when the table is renamed.
+type TableDiff struct {
+	Old            *Table
+	New            *Table
+	AddedColumns   []*Column
+	DroppedColumns []*Column
+	AlteredColumns []*ColumnDiff
+	// Changes are the changes of the primary key and of the comment of the table
+	Changes            []Change
+	AddedForeignKeys   []*ForeignKey
+	DroppedForeignKeys []*ForeignKey
+	AddedIndexes       []*Index
+	DroppedIndexes     []*Index
+}
+
+// ColumnDiff is a column of both schemas whose definition changed.
+type ColumnDiff struct {
+	Old     *Column
+	New     *Column
+	Changes []Change
+}
+
+// Change is a property of a table or a column whose value changed, values are printed as Oracle SQL.
+type Change struct {
+	// Property is one of the Change constants
+	Property string
+	Old      string
+	New      string
+}
+
+const (
+	ChangeType       = "type"
+	ChangeLength     = "length"
+	ChangePrecision  = "precision"
+	ChangeNullable   = "nullable"
+	ChangeDefault    = "default"
+	ChangeComment    = "comment"
+	ChangePrimaryKey = "primary key"
+)
+
+// Diff compares the tables of two schemas. Tables and columns are matched by name, a table dropped and a table
+// added in the same schema with the same columns of the same types are taken as a renamed table. A foreign key
+// or an index whose definition changed is reported as dropped and added.
+func Diff(old, new Database) SchemaDiff {
+	diff := SchemaDiff{AddedTables: []*Table{}, DroppedTables: []*Table{}, Tables: []*TableDiff{}}
+	oldTables := map[string]*Table{}
+	for _, table := range old.Tables {
+		oldTables[table.QualifiedName()] = table
+	}
+	newTables := map[string]*Table{}
+	for _, table := range new.Tables {
+		newTables[table.QualifiedName()] = table
+	}
+
+	// renamed maps the tables of the old schema to those of the new one
+	renamed := map[*Table]*Table{}
+	for _, table := range old.Tables {
+		if matched := newTables[table.QualifiedName()]; matched != nil {
+			renamed[table] = matched
+		}
+	}
+	for _, table := range new.Tables {
+		if oldTables[table.QualifiedName()] != nil {
+			continue
+		}
+		i := slices.IndexFunc(old.Tables, func(t *Table) bool {
+			return newTables[t.QualifiedName()] == nil && renamed[t] == nil && isSameShape(t, table)
+		})
+		if i >= 0 {
+			renamed[old.Tables[i]] = table
+		}
+	}
+	oldName := func(t *Table) string {
+		if matched := renamed[t]; matched != nil {
+			return matched.QualifiedName()
+		}
+		return t.QualifiedName()
+	}
+
+	for _, table := range old.Tables {
+		if renamed[table] == nil {
+			diff.DroppedTables = append(diff.DroppedTables, table)
+		}
+	}
+	for _, table := range new.Tables {
+		var former *Table
+		for t, matched := range renamed {
+			if matched == table {
+				former = t
+			}
+		}
+		if former == nil {
+			diff.AddedTables = append(diff.AddedTables, table)
+			continue
+		}
+		if tableDiff := diffTable(former, table, oldName); tableDiff != nil {
+			diff.Tables = append(diff.Tables, tableDiff)
+		}
+	}
+	return diff
+}
+
+// isSameShape reports whether the tables have the same columns of the same types in the same order
+// and the same primary key.
+func isSameShape(x, y *Table) bool {
+	if x.Schema != y.Schema || len(x.Columns) != len(y.Columns) || primaryKeyText(x) != primaryKeyText(y) {
+		return false
+	}
+	for i, col := range x.Columns {
+		if col.Name != y.Columns[i].Name || toSqlType(col.DataType) != toSqlType(y.Columns[i].DataType) {
+			return false
+		}
+	}
+	return true
+}
+
+// diffTable compares the versions of a table, oldName names the tables of the old schema as in the new one.
+// It returns nil when nothing changed.
+func diffTable(old, new *Table, oldName func(*Table) string) *TableDiff {
+	diff := &TableDiff{Old: old, New: new}
+	for _, col := range new.Columns {
+		former := old.getColumn(col.Name)
+		if former == nil {
+			diff.AddedColumns = append(diff.AddedColumns, col)
+			continue
+		}
+		if changes := diffColumn(former, col); len(changes) > 0 {
+			diff.AlteredColumns = append(diff.AlteredColumns, &ColumnDiff{Old: former, New: col, Changes: changes})
+		}
+	}
+	for _, col := range old.Columns {
+		if new.getColumn(col.Name) == nil {
+			diff.DroppedColumns = append(diff.DroppedColumns, col)
+		}
+	}
+
+	if before, after := primaryKeyText(old), primaryKeyText(new); before != after {
+		diff.Changes = append(diff.Changes, Change{Property: ChangePrimaryKey, Old: before, New: after})
+	}
+	if old.Comment != new.Comment {
+		diff.Changes = append(diff.Changes, Change{Property: ChangeComment, Old: old.Comment, New: new.Comment})
+	}
+
+	newName := func(t *Table) string { return t.QualifiedName() }
+	diff.AddedForeignKeys, diff.DroppedForeignKeys = diffDefinitions(new.ForeignKeys, old.ForeignKeys,
+		func(fk *ForeignKey) string { return foreignKeyText(fk, newName) },
+		func(fk *ForeignKey) string { return foreignKeyText(fk, oldName) })
+	diff.AddedIndexes, diff.DroppedIndexes = diffDefinitions(new.Indexes, old.Indexes, indexText, indexText)
+
+	if old.QualifiedName() == new.QualifiedName() && len(diff.AddedColumns) == 0 && len(diff.DroppedColumns) == 0 &&
+		len(diff.AlteredColumns) == 0 && len(diff.Changes) == 0 && len(diff.AddedForeignKeys) == 0 &&
+		len(diff.DroppedForeignKeys) == 0 && len(diff.AddedIndexes) == 0 && len(diff.DroppedIndexes) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// diffDefinitions returns the definitions only found among the new ones and those only found among the old ones.
+func diffDefinitions[T any](news, olds []T, newText, oldText func(T) string) (added, dropped []T) {
+	for _, def := range news {
+		if !slices.ContainsFunc(olds, func(o T) bool { return oldText(o) == newText(def) }) {
+			added = append(added, def)
+		}
+	}
+	for _, def := range olds {
+		if !slices.ContainsFunc(news, func(n T) bool { return newText(n) == oldText(def) }) {
+			dropped = append(dropped, def)
+		}
+	}
+	return added, dropped
+}
+
+// sizePattern matches the size of a data type such as "(10 CHAR)" or "(10, 2)".
+var sizePattern = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// diffColumn compares the data type, the nullability, the default and the comment of the column.
+// A data type differing only by its size is a change of length or precision.
+func diffColumn(old, new *Column) []Change {
+	changes := []Change{}
+	before, after := toSqlType(old.DataType), toSqlType(new.DataType)
+	if before != after {
+		property := ChangeType
+		if sizePattern.ReplaceAllString(before, "") == sizePattern.ReplaceAllString(after, "") {
+			property = ChangePrecision
+			if hasLength(new.DataType) {
+				property = ChangeLength
+			}
+		}
+		changes = append(changes, Change{Property: property, Old: before, New: after})
+	}
+	if old.IsNullable() != new.IsNullable() {
+		changes = append(changes, Change{Property: ChangeNullable, Old: nullability(old), New: nullability(new)})
+	}
+	if old.Default != new.Default {
+		changes = append(changes, Change{Property: ChangeDefault, Old: old.Default, New: new.Default})
+	}
+	if old.Comment != new.Comment {
+		changes = append(changes, Change{Property: ChangeComment, Old: old.Comment, New: new.Comment})
+	}
+	return changes
+}
+
+// hasLength reports whether the size of the data type is a length rather than a precision.
+func hasLength(datatype element.Datatype) bool {
+	switch datatype.DataDef() {
+	case element.DataDefChar, element.DataDefVarchar2, element.DataDefNChar, element.DataDefNVarChar2, element.DataDefCharacter,
+		element.DataDefCharacterVarying, element.DataDefCharVarying, element.DataDefNCharVarying, element.DataDefVarchar,
+		element.DataDefNationalCharacter, element.DataDefNationalCharacterVarying, element.DataDefNationalChar,
+		element.DataDefNationalCharVarying, element.DataDefRaw:
+		return true
+	}
+	return false
+}
+
+func nullability(col *Column) string {
+	if col.IsNullable() {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// primaryKeyColumns returns the primary key columns in the order of the constraint, the tables of views
+// have no constraint and list the columns selecting a primary key column.
+func primaryKeyColumns(table *Table) []*Column {
+	if len(table.PrimaryKey) > 0 {
+		return table.PrimaryKey
+	}
+	columns := []*Column{}
+	for _, col := range table.Columns {
+		if col.Attribute.IsPrimaryKey() {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// primaryKeyText describes the primary key as "PK_ORDERS (ID)", empty for a table without one.
+func primaryKeyText(table *Table) string {
+	columns := primaryKeyColumns(table)
+	if len(columns) == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("(%v)", strings.Join(mapping(columns, getName), ", "))
+	if table.PrimaryKeyName != "" {
+		text = table.PrimaryKeyName + " " + text
+	}
+	return text
+}
+
+// foreignKeyText describes the foreign key as "FK_X (A) REFERENCES T (B) ON DELETE CASCADE",
+// tableName names the referenced table.
+func foreignKeyText(fk *ForeignKey, tableName func(*Table) string) string {
+	text := fmt.Sprintf("(%v) REFERENCES %v (%v)", strings.Join(mapping(fk.Columns, getName), ", "), tableName(fk.RefTable), strings.Join(mapping(fk.RefColumns, getName), ", "))
+	if fk.Name != "" {
+		text = fk.Name + " " + text
+	}
+	if fk.OnDelete != "" {
+		text += " ON DELETE " + fk.OnDelete
+	}
+	return text
+}
+
+// indexText describes the index as "UNIQUE IX_X (A, B DESC)".
+func indexText(index *Index) string {
+	columns := []string{}
+	for _, col := range index.Columns {
+		name := col.Expression
+		if col.Column != nil {
+			name = col.Column.Name
+		}
+		if col.Direction == "DESC" {
+			name += " DESC"
+		}
+		columns = append(columns, name)
+	}
+	text := fmt.Sprintf("%v (%v)", qualifiedName(index.Schema, index.Name), strings.Join(columns, ", "))
+	switch {
+	case index.Unique:
+		text = "UNIQUE " + text
+	case index.Bitmap:
+		text = "BITMAP " + text
+	}
+	return text
+}
+
+// IsRenamed reports whether the table has another name in the new schema.
+func (d *TableDiff) IsRenamed() bool {
+	return d.Old.QualifiedName() != d.New.QualifiedName()
+}
+
+// IsEmpty reports whether the schemas have the same tables.
+func (d SchemaDiff) IsEmpty() bool {
+	return len(d.AddedTables) == 0 && len(d.DroppedTables) == 0 && len(d.Tables) == 0
+}
+
+// diffEntry is a line of the reports.
+type diffEntry struct {
+	Change string
+	Object string
+	Old    string
+	New    string
+}
+
+// entries lists the changes of the table, renaming first.
+func (d *TableDiff) entries() []diffEntry {
+	entries := []diffEntry{}
+	if d.IsRenamed() {
+		entries = append(entries, diffEntry{"renamed table", d.New.QualifiedName(), d.Old.QualifiedName(), d.New.QualifiedName()})
+	}
+	for _, col := range d.AddedColumns {
+		entries = append(entries, diffEntry{"added column", col.Name, "", columnText(col)})
+	}
+	for _, col := range d.DroppedColumns {
+		entries = append(entries, diffEntry{"dropped column", col.Name, columnText(col), ""})
+	}
+	for _, col := range d.AlteredColumns {
+		for _, change := range col.Changes {
+			entries = append(entries, diffEntry{"altered column " + change.Property, col.New.Name, change.Old, change.New})
+		}
+	}
+	for _, change := range d.Changes {
+		entries = append(entries, diffEntry{"changed " + change.Property, d.New.QualifiedName(), change.Old, change.New})
+	}
+	name := func(t *Table) string { return t.QualifiedName() }
+	for _, fk := range d.DroppedForeignKeys {
+		entries = append(entries, diffEntry{"dropped foreign key", fk.Name, foreignKeyText(fk, name), ""})
+	}
+	for _, fk := range d.AddedForeignKeys {
+		entries = append(entries, diffEntry{"added foreign key", fk.Name, "", foreignKeyText(fk, name)})
+	}
+	for _, index := range d.DroppedIndexes {
+		entries = append(entries, diffEntry{"dropped index", index.Name, indexText(index), ""})
+	}
+	for _, index := range d.AddedIndexes {
+		entries = append(entries, diffEntry{"added index", index.Name, "", indexText(index)})
+	}
+	return entries
+}
+
+// columnText describes the column as "NAME VARCHAR2(10) NOT NULL".
+func columnText(col *Column) string {
+	text := col.Name + " " + toSqlType(col.DataType)
+	if col.Default != "" {
+		text += " DEFAULT " + col.Default
+	}
+	if !col.IsNullable() {
+		text += " NOT NULL"
+	}
+	return text
+}
+
+// String reports the changes as text, a line per change.
+func (d SchemaDiff) String() string {
+	if d.IsEmpty() {
+		return "no changes\n"
+	}
+	report := strings.Builder{}
+	for _, table := range d.AddedTables {
+		fmt.Fprintf(&report, "added table %v\n", table.QualifiedName())
+	}
+	for _, table := range d.DroppedTables {
+		fmt.Fprintf(&report, "dropped table %v\n", table.QualifiedName())
+	}
+	for _, table := range d.Tables {
+		fmt.Fprintf(&report, "table %v:\n", table.New.QualifiedName())
+		for _, entry := range table.entries() {
+			fmt.Fprintf(&report, "  %v %v", entry.Change, entry.Object)
+			switch {
+			case entry.Old != "" && entry.New != "":
+				fmt.Fprintf(&report, ": %v -> %v", entry.Old, entry.New)
+			case entry.Old != "":
+				fmt.Fprintf(&report, ": %v", entry.Old)
+			case entry.New != "":
+				fmt.Fprintf(&report, ": %v", entry.New)
+			}
+			report.WriteString("\n")
+		}
+	}
+	return report.String()
+}
+
+// Markdown reports the changes as lists of added and dropped tables and a table of changes per changed table.
+func (d SchemaDiff) Markdown() string {
+	if d.IsEmpty() {
+		return "No schema changes.\n"
+	}
+	report := strings.Builder{}
+	report.WriteString("## Schema changes\n")
+	if len(d.AddedTables) > 0 {
+		report.WriteString("\n### Added tables\n\n")
+		for _, table := range d.AddedTables {
+			fmt.Fprintf(&report, "- `%v` (%v columns)\n", table.QualifiedName(), len(table.Columns))
+		}
+	}
+	if len(d.DroppedTables) > 0 {
+		report.WriteString("\n### Dropped tables\n\n")
+		for _, table := range d.DroppedTables {
+			fmt.Fprintf(&report, "- `%v`\n", table.QualifiedName())
+		}
+	}
+	for _, table := range d.Tables {
+		fmt.Fprintf(&report, "\n### `%v`\n\n| Change | Object | Old | New |\n| --- | --- | --- | --- |\n", table.New.QualifiedName())
+		for _, entry := range table.entries() {
+			fmt.Fprintf(&report, "| %v | %v | %v | %v |\n", entry.Change, markdownCode(entry.Object), markdownCode(entry.Old), markdownCode(entry.New))
+		}
+	}
+	return report.String()
+}
+
+// markdownCode formats the text as code in a table cell, pipes escaped.
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(text, "|", `\|`) + "`"
+}
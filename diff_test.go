@@ -0,0 +1,102 @@
+package ddlcode
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "unchanged",
+			old:  `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(50));`,
+			new:  `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(50));`,
+			want: "no changes\n",
+		},
+		{
+			name: "columns, comment and index",
+			old: `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(50), old NUMBER);
+CREATE INDEX ix_t ON t (name);`,
+			new: `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(100) NOT NULL, created DATE);
+COMMENT ON TABLE t IS 'things';`,
+			want: `table T:
+  added column CREATED: CREATED DATE
+  dropped column OLD: OLD NUMBER
+  altered column length NAME: VARCHAR2(50) -> VARCHAR2(100)
+  altered column nullable NAME: NULL -> NOT NULL
+  changed comment T: things
+  dropped index IX_T: IX_T (NAME)
+`,
+		},
+		{
+			name: "types, defaults and keys",
+			old: `CREATE TABLE p (id NUMBER(10) PRIMARY KEY);
+CREATE TABLE t (id NUMBER(10), code CHAR(2), amount NUMBER(8, 2), p_id NUMBER(10),
+  CONSTRAINT pk_t PRIMARY KEY (id));`,
+			new: `CREATE TABLE p (id NUMBER(10) PRIMARY KEY);
+CREATE TABLE t (id NUMBER(10), code VARCHAR2(2) DEFAULT 'XX', amount NUMBER(10, 2), p_id NUMBER(10),
+  CONSTRAINT pk_t PRIMARY KEY (id, code), CONSTRAINT fk_t_p FOREIGN KEY (p_id) REFERENCES p (id));
+CREATE UNIQUE INDEX ux_t ON t (p_id);`,
+			want: `table T:
+  altered column type CODE: CHAR(2) -> VARCHAR2(2)
+  altered column nullable CODE: NULL -> NOT NULL
+  altered column default CODE: 'XX'
+  altered column precision AMOUNT: NUMBER(8, 2) -> NUMBER(10, 2)
+  changed primary key T: PK_T (ID) -> PK_T (ID, CODE)
+  added foreign key FK_T_P: FK_T_P (P_ID) REFERENCES P (ID)
+  added index UX_T: UNIQUE UX_T (P_ID)
+`,
+		},
+		{
+			name: "added and dropped tables",
+			old:  `CREATE TABLE gone (id NUMBER, name VARCHAR2(10));`,
+			new:  `CREATE TABLE added (id NUMBER);`,
+			want: `added table ADDED
+dropped table GONE
+`,
+		},
+		{
+			name: "renamed table",
+			old:  `CREATE TABLE gone (id NUMBER);`,
+			new:  `CREATE TABLE added (id NUMBER);`,
+			want: `table ADDED:
+  renamed table ADDED: GONE -> ADDED
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diff(parseSchema(t, tt.old), parseSchema(t, tt.new))
+			if d.IsEmpty() != (tt.want == "no changes\n") {
+				t.Errorf("IsEmpty is %v", d.IsEmpty())
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("got\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffMarkdown(t *testing.T) {
+	old := parseSchema(t, `CREATE TABLE t (id NUMBER PRIMARY KEY, flag CHAR(1) CHECK (flag IN ('Y', 'N')));
+CREATE TABLE gone (id NUMBER, name VARCHAR2(10));`)
+	new := parseSchema(t, `CREATE TABLE t (id NUMBER PRIMARY KEY, flag CHAR(1) DEFAULT 'Y');
+CREATE TABLE added (id NUMBER);`)
+
+	want := "## Schema changes\n" +
+		"\n### Added tables\n\n- `ADDED` (1 columns)\n" +
+		"\n### Dropped tables\n\n- `GONE`\n" +
+		"\n### `T`\n\n| Change | Object | Old | New |\n| --- | --- | --- | --- |\n" +
+		"| altered column default | `FLAG` |  | `'Y'` |\n"
+	if got := Diff(old, new).Markdown(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+	if got := Diff(old, old).Markdown(); got != "No schema changes.\n" {
+		t.Errorf("got %q for the same schema", got)
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/codeindex2937/ddlcode/drawio"
 	"github.com/codeindex2937/ddlcode/html"
 	"github.com/codeindex2937/ddlcode/toposort"
-	"github.com/codeindex2937/oracle-sql-parser/ast"
 	"github.com/codeindex2937/oracle-sql-parser/ast/element"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -23,17 +22,21 @@ var rowHeight = 18
 var titleHeight = 20
 
 type DrawioConfig struct {
-	ExportPath             string
-	CellId                 string
-	Width                  int
-	Height                 int
-	Tables                 []*Table
-	EntityStyle            map[string]string
-	TableStyle             map[string]string
-	HeaderStyle            map[string]string
-	RowStyle               map[string]string
-	CellStyle              map[string]string
-	LinkStyle              map[string]string
+	ExportPath  string
+	CellId      string
+	Width       int
+	Height      int
+	Tables      []*Table
+	EntityStyle map[string]string
+	// ViewStyle overrides EntityStyle for views
+	ViewStyle   map[string]string
+	TableStyle  map[string]string
+	HeaderStyle map[string]string
+	RowStyle    map[string]string
+	CellStyle   map[string]string
+	LinkStyle   map[string]string
+	// LineageStyle overrides LinkStyle for the edges from view columns to the columns they select
+	LineageStyle           map[string]string
 	EdgeLabelStyle         map[string]string
 	GetLinkTarget          func(table *Table, col *Column, tableId, columnId string) (targetId, verticalPosition string)
 	GetEntity              func(config DrawioConfig, table *Table, id string, x, y, width, height float64, style map[string]string) []*drawio.Shape
@@ -59,6 +62,11 @@ var InnerTableConfig = DrawioConfig{
 		"fontFamily":           "Verdana",
 		"fontSize":             "12",
 	},
+	ViewStyle: map[string]string{
+		"fillColor":   "#dae8fc",
+		"strokeColor": "#6c8ebf",
+		"fontStyle":   "3",
+	},
 	TableStyle: map[string]string{
 		"width":           "100%",
 		"font-size":       "1em",
@@ -90,6 +98,10 @@ var InnerTableConfig = DrawioConfig{
 		"entryDx":        "0",
 		"entryDy":        "0",
 	},
+	LineageStyle: map[string]string{
+		"dashed":   "1",
+		"endArrow": "open",
+	},
 	EdgeLabelStyle: map[string]string{
 		"edgeLabel":     "",
 		"html":          "1",
@@ -135,6 +147,11 @@ func GetDefaultDrawioConfig() DrawioConfig {
 			"align":       "center",
 			"resizeLast":  "1",
 		},
+		ViewStyle: map[string]string{
+			"fillColor":   "#dae8fc",
+			"strokeColor": "#6c8ebf",
+			"fontStyle":   "3",
+		},
 		TableStyle: map[string]string{
 			"shape":       "table",
 			"startSize":   "30",
@@ -199,6 +216,10 @@ func GetDefaultDrawioConfig() DrawioConfig {
 			"entryDx":        "0",
 			"entryDy":        "0",
 		},
+		LineageStyle: map[string]string{
+			"dashed":   "1",
+			"endArrow": "open",
+		},
 		EdgeLabelStyle: map[string]string{
 			"edgeLabel":     "",
 			"html":          "1",
@@ -216,7 +237,7 @@ func GetDefaultDrawioConfig() DrawioConfig {
 					Id:     id,
 					Vertex: "1",
 					Style:  join(style, "="),
-					Value:  table.Table,
+					Value:  getEntityTitle(table),
 					Geometry: &drawio.Geometry{
 						X:      strconv.FormatFloat(x, 'f', -1, 64),
 						Y:      strconv.FormatFloat(y, 'f', -1, 64),
@@ -254,24 +275,33 @@ func GenerateDrawio(config DrawioConfig) (map[string]string, error) {
 
 	positionMap := getPositions(config.Tables, func(rowNum int) int { return rowHeight*rowNum + titleHeight }, tableWidth+200)
 
+	tableMap := map[string]*Table{}
 	for i, table := range config.Tables {
+		key := table.QualifiedName()
 		tableId := fmt.Sprintf("%v-%v", config.CellId, i)
 		height := rowHeight*len(table.Columns) + titleHeight
-		position := positionMap[table.Table]
+		position := positionMap[key]
+		entityStyle := config.EntityStyle
+		if table.IsView() {
+			entityStyle = map[string]string{}
+			maps.Copy(entityStyle, config.EntityStyle)
+			maps.Copy(entityStyle, config.ViewStyle)
+		}
 		entities := config.GetEntity(
 			config, table, tableId,
 			float64(position.x), float64(position.y),
 			float64(tableWidth), float64(height),
-			config.EntityStyle)
+			entityStyle)
 
 		for _, entity := range entities {
 			f.Diagram.MxGraphModel.AddCells(entity)
 		}
 
-		tableIdMap[table.Table] = tableId
-		columnIdMap[table.Table] = map[string]string{}
+		tableMap[key] = table
+		tableIdMap[key] = tableId
+		columnIdMap[key] = map[string]string{}
 		for i, col := range table.Columns {
-			columnIdMap[table.Table][col.Name] = entities[config.GetColumnIndexInEntity(i)].Id
+			columnIdMap[key][col.Name] = entities[config.GetColumnIndexInEntity(i)].Id
 		}
 	}
 
@@ -279,28 +309,61 @@ func GenerateDrawio(config DrawioConfig) (map[string]string, error) {
 	maps.Copy(linkStyle, config.LinkStyle)
 
 	for _, table := range config.Tables {
-		for _, col := range table.Columns {
-			if col.ForeignTable == nil {
+		for _, fk := range table.ForeignKeys {
+			if _, ok := tableIdMap[fk.RefTable.QualifiedName()]; !ok {
 				continue
 			}
 
+			for i, col := range fk.Columns {
+				refCol := fk.RefColumns[i]
+				sourceId, sourceVerticalPos := config.GetLinkTarget(
+					table, col,
+					tableIdMap[table.QualifiedName()], columnIdMap[table.QualifiedName()][col.Name])
+				targetId, targetVerticalPos := config.GetLinkTarget(
+					fk.RefTable, refCol,
+					tableIdMap[fk.RefTable.QualifiedName()], columnIdMap[fk.RefTable.QualifiedName()][refCol.Name])
+
+				linkStyle["entryX"] = "0"
+				linkStyle["entryY"] = targetVerticalPos
+				linkStyle["exitX"] = "1"
+				linkStyle["exitY"] = sourceVerticalPos
+
+				link := drawio.NewLine(parent.Id, sourceId, targetId, linkStyle)
+				target, source := link.NewEdgeLabel(config.EdgeLabelStyle)
+				target.Value = col.Name
+				source.Value = refCol.Name
+				f.Diagram.MxGraphModel.AddCells(link, target, source)
+			}
+		}
+	}
+
+	lineageStyle := map[string]string{}
+	maps.Copy(lineageStyle, config.LinkStyle)
+	maps.Copy(lineageStyle, config.LineageStyle)
+
+	for _, table := range config.Tables {
+		for _, col := range table.Columns {
+			if col.Source == nil {
+				continue
+			}
+			sourceTable, ok := tableMap[qualifiedName(col.Source.Schema, col.Source.Table)]
+			if !ok {
+				continue
+			}
 			sourceId, sourceVerticalPos := config.GetLinkTarget(
 				table, col,
-				tableIdMap[table.Table], columnIdMap[table.Table][col.Name])
+				tableIdMap[table.QualifiedName()], columnIdMap[table.QualifiedName()][col.Name])
 			targetId, targetVerticalPos := config.GetLinkTarget(
-				col.ForeignTable, col.ForeignColumn,
-				tableIdMap[col.ForeignTable.Table], columnIdMap[col.ForeignTable.Table][col.ForeignColumn.Name])
+				sourceTable, col.Source,
+				tableIdMap[sourceTable.QualifiedName()], columnIdMap[sourceTable.QualifiedName()][col.Source.Name])
 
-			linkStyle["entryX"] = "0"
-			linkStyle["entryY"] = targetVerticalPos
-			linkStyle["exitX"] = "1"
-			linkStyle["exitY"] = sourceVerticalPos
+			lineageStyle["entryX"] = "0"
+			lineageStyle["entryY"] = targetVerticalPos
+			lineageStyle["exitX"] = "1"
+			lineageStyle["exitY"] = sourceVerticalPos
 
-			link := drawio.NewLine(parent.Id, sourceId, targetId, linkStyle)
-			target, source := link.NewEdgeLabel(config.EdgeLabelStyle)
-			target.Value = col.Name
-			source.Value = col.ForeignColumn.Name
-			f.Diagram.MxGraphModel.AddCells(link, target, source)
+			link := drawio.NewLine(parent.Id, sourceId, targetId, lineageStyle)
+			f.Diagram.MxGraphModel.AddCells(link)
 		}
 	}
 
@@ -321,7 +384,7 @@ func GenerateDrawio(config DrawioConfig) (map[string]string, error) {
 func getPositions(tables []*Table, h func(int) int, w int) map[string]position {
 	tableMap := map[string]*Table{}
 	for _, table := range tables {
-		tableMap[table.Table] = table
+		tableMap[table.QualifiedName()] = table
 	}
 
 	layers := sortIntoLayers(tableMap)
@@ -363,7 +426,8 @@ func getPositions(tables []*Table, h func(int) int, w int) map[string]position {
 	}
 
 	layerCurrentHeight := map[int]int{}
-	for key := range tableMap {
+	for _, table := range tables {
+		key := table.QualifiedName()
 		if slices.Contains(isolatedNoes, key) {
 			continue
 		}
@@ -388,11 +452,11 @@ func values[K comparable, V any](m map[K]V) []V {
 func sortIntoLayers(tables map[string]*Table) map[string]int {
 	g := toposort.NewGraph[string]()
 	for _, t := range tables {
-		for _, c := range t.Columns {
-			if c.ForeignTable == nil {
+		for _, fk := range t.ForeignKeys {
+			if _, ok := tables[fk.RefTable.QualifiedName()]; !ok || fk.RefTable == t {
 				continue
 			}
-			g.AddEdge(t.Table, c.ForeignTable.Table)
+			g.AddEdge(t.QualifiedName(), fk.RefTable.QualifiedName())
 		}
 	}
 
@@ -454,8 +518,10 @@ func getColumns(entityId string, config DrawioConfig, table *Table, width float6
 		if col.Attribute.IsAutoIncrement() {
 			autoIncrement = "AI"
 		}
-		if col.Attribute.IsUnique() {
+		if table.IsUnique(col) {
 			unique = "U"
+		} else if table.IsPartOfUnique(col) {
+			unique = "(U)"
 		}
 
 		colId := fmt.Sprintf("%v-col-%v", entityId, i)
@@ -477,7 +543,7 @@ func getColumns(entityId string, config DrawioConfig, table *Table, width float6
 			MxCellBase: drawio.MxCellBase{
 				Id:     fmt.Sprintf("%v-cell-%v", entityId, i),
 				Vertex: "1",
-				Value:  fmt.Sprintf("%v %v [%v][%v][%v][%v][%v]", col.Name, toSqlType(col.DataType), notNull, pk, autoIncrement, unique, getDefaultValueFromAttribute(col.Attribute)),
+				Value:  fmt.Sprintf("%v %v [%v][%v][%v][%v][%v]%v", col.Name, toSqlType(col.DataType), notNull, pk, autoIncrement, unique, col.Default, getCheckAnnotation(col)+getPartitionAnnotation(table, col)),
 				Style:  join(textStyle, "="),
 				Parent: colId,
 				Geometry: &drawio.Geometry{
@@ -528,8 +594,10 @@ func getInnerTable(config DrawioConfig, table *Table) string {
 		if col.Attribute.IsAutoIncrement() {
 			autoIncrement = "AI"
 		}
-		if col.Attribute.IsUnique() {
+		if table.IsUnique(col) {
 			unique = "U"
+		} else if table.IsPartOfUnique(col) {
+			unique = "(U)"
 		}
 
 		row := html.TableRow{
@@ -540,7 +608,8 @@ func getInnerTable(config DrawioConfig, table *Table) string {
 				{Data: pk},
 				{Data: autoIncrement},
 				{Data: unique},
-				{Data: getDefaultValueFromAttribute(col.Attribute)},
+				{Data: col.Default},
+				{Data: getCheckAnnotation(col) + getPartitionAnnotation(table, col)},
 			},
 		}
 		for i := range row.Data {
@@ -548,6 +617,11 @@ func getInnerTable(config DrawioConfig, table *Table) string {
 		}
 		entity.Table.Row = append(entity.Table.Row, row)
 	}
+	if properties := getTableProperties(table); properties != "" {
+		entity.Table.Row = append(entity.Table.Row, html.TableRow{
+			Data: []html.TableData{{Data: properties, Style: dataStyle}},
+		})
+	}
 
 	serialized, err := xml.Marshal(entity)
 	if err != nil {
@@ -590,15 +664,50 @@ func isCompositePrimaryKey(table *Table) bool {
 	return pKeyCount > 1
 }
 
-func getDefaultValueFromAttribute(attr AttributeMap) string {
-	if attr, ok := attr[ast.ConstraintTypeDefault]; ok {
-		return getDefaultValue(attr)
+// getCheckAnnotation shows the allowed values of the column, or the conditions of its checks.
+func getCheckAnnotation(col *Column) string {
+	if values := col.AllowedValues(); values != nil {
+		return fmt.Sprintf(" CHECK IN (%v)", strings.Join(values, ", "))
+	}
+	conditions := mapping(col.Checks, func(c *CheckConstraint) string { return c.Expression })
+	if len(conditions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" CHECK (%v)", strings.Join(conditions, ") AND ("))
+}
+
+// getPartitionAnnotation marks the columns of the partition and subpartition keys.
+func getPartitionAnnotation(table *Table, col *Column) string {
+	if table.IsPartitionKey(col) {
+		return " PARTITION KEY"
+	}
+	if table.IsSubpartitionKey(col) {
+		return " SUBPARTITION KEY"
 	}
 	return ""
 }
 
-func getDefaultValue(expr *ast.ColumnDefault) (value string) {
-	return fmt.Sprintf("%v", expr)
+// getTableProperties describes the partitioning, the tablespace and the triggers of the table.
+func getTableProperties(table *Table) string {
+	properties := []string{}
+	if table.Partitioning != nil {
+		properties = append(properties, "PARTITION BY "+table.Partitioning.String())
+	}
+	if table.Tablespace != "" {
+		properties = append(properties, "TABLESPACE "+table.Tablespace)
+	}
+	if len(table.Triggers) > 0 {
+		properties = append(properties, "TRIGGERS "+strings.Join(mapping(table.Triggers, func(t *Trigger) string { return t.Name }), ", "))
+	}
+	return strings.Join(properties, ", ")
+}
+
+// getEntityTitle is the table name followed by its properties.
+func getEntityTitle(table *Table) string {
+	if properties := getTableProperties(table); properties != "" {
+		return fmt.Sprintf("%v (%v)", table.Table, properties)
+	}
+	return table.Table
 }
 
 func join(style map[string]string, assignChar string) string {
@@ -780,6 +889,8 @@ func toSqlType(datatype element.Datatype) (name string) {
 		}
 	case element.DataDefXMLType:
 		name = "XMLTYPE"
+	case DataDefUserType:
+		name = datatype.(*UserDatatype).String()
 	default:
 		name = "UnSupport"
 	}
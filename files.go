@@ -0,0 +1,148 @@
+package ddlcode
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type FileOrder int
+
+const (
+	// FileOrderGiven parses the files in the order they are given, the matches of each glob in name order
+	FileOrderGiven FileOrder = iota
+	// FileOrderNatural sorts the file paths with numbers compared by value, so "2.sql" comes before "10.sql"
+	FileOrderNatural
+	// FileOrderVersion sorts Flyway migrations by version, V1__ before V1.1__ before V2__,
+	// the other files, repeatable R__ migrations included, follow in natural order
+	FileOrderVersion
+)
+
+// ParseFiles parses the DDL of several files as one script.
+func ParseFiles(paths ...string) (Database, []Diagnostic, error) {
+	return ParseFilesWithOptions(ParseOptions{}, paths...)
+}
+
+// ParseFilesWithOptions parses the files in the order of opts.FileOrder, statements are applied file by file
+// and foreign keys are resolved once all files are loaded.
+func ParseFilesWithOptions(opts ParseOptions, paths ...string) (Database, []Diagnostic, error) {
+	sources := []source{}
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return Database{}, nil, err
+		}
+		sources = append(sources, source{Name: p, SQL: string(content)})
+	}
+	return parseSources(sortSources(sources, opts.FileOrder), opts)
+}
+
+// ParseFS parses the files of fsys matching the glob patterns as one script.
+func ParseFS(fsys fs.FS, patterns ...string) (Database, []Diagnostic, error) {
+	return ParseFSWithOptions(fsys, ParseOptions{}, patterns...)
+}
+
+// ParseFSWithOptions parses the files matching the patterns, the matches of a pattern come after those of the
+// patterns before it unless opts.FileOrder sorts all files. A file matched twice is parsed once.
+func ParseFSWithOptions(fsys fs.FS, opts ParseOptions, patterns ...string) (Database, []Diagnostic, error) {
+	sources := []source{}
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return Database{}, nil, err
+		}
+		if len(matches) == 0 {
+			return Database{}, nil, fmt.Errorf("no file matches %v", pattern)
+		}
+		for _, name := range matches {
+			if slices.ContainsFunc(sources, func(s source) bool { return s.Name == name }) {
+				continue
+			}
+			content, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return Database{}, nil, err
+			}
+			sources = append(sources, source{Name: name, SQL: string(content)})
+		}
+	}
+	return parseSources(sortSources(sources, opts.FileOrder), opts)
+}
+
+func sortSources(sources []source, order FileOrder) []source {
+	switch order {
+	case FileOrderNatural:
+		slices.SortStableFunc(sources, func(a, b source) int { return compareNatural(a.Name, b.Name) })
+	case FileOrderVersion:
+		slices.SortStableFunc(sources, func(a, b source) int { return compareVersioned(a.Name, b.Name) })
+	}
+	return sources
+}
+
+var flywayVersionPattern = regexp.MustCompile(`^[Vv](\d+(?:[._]\d+)*)__`)
+
+// migrationVersion returns the version of a Flyway versioned migration, nil for any other file.
+func migrationVersion(name string) []int {
+	m := flywayVersionPattern.FindStringSubmatch(path.Base(name))
+	if m == nil {
+		return nil
+	}
+	version := []int{}
+	for _, part := range strings.FieldsFunc(m[1], func(r rune) bool { return r == '.' || r == '_' }) {
+		n, _ := strconv.Atoi(part)
+		version = append(version, n)
+	}
+	return version
+}
+
+func compareVersioned(a, b string) int {
+	va, vb := migrationVersion(a), migrationVersion(b)
+	switch {
+	case va == nil && vb == nil:
+		return compareNatural(a, b)
+	case va == nil:
+		return 1
+	case vb == nil:
+		return -1
+	}
+	if c := slices.Compare(va, vb); c != 0 {
+		return c
+	}
+	return compareNatural(a, b)
+}
+
+// compareNatural compares the strings with runs of digits compared by value.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da == "" || db == "" {
+			if a[0] != b[0] {
+				return int(a[0]) - int(b[0])
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+		if len(na) != len(nb) {
+			return len(na) - len(nb)
+		}
+		if c := strings.Compare(na, nb); c != 0 {
+			return c
+		}
+		a, b = a[len(da):], b[len(db):]
+	}
+	return len(a) - len(b)
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i += 1
+	}
+	return s[:i]
+}
@@ -3,12 +3,15 @@ package ddlcode
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"github.com/codeindex2937/oracle-sql-parser/ast"
 	"github.com/codeindex2937/oracle-sql-parser/ast/element"
 	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -22,13 +25,20 @@ type GormConfig struct {
 	Package   string
 	Table     *Table
 	Template  *template.Template
+	// SourceComments adds the file and line each struct and field is generated from
+	SourceComments bool
 }
 
 var GormFuncMap = template.FuncMap{
-	"ToCamel":      strcase.ToCamel,
-	"ToLowerCamel": strcase.ToLowerCamel,
-	"ToTypeName":   toGoType,
-	"ToTags":       toTags,
+	"ToCamel":        strcase.ToCamel,
+	"ToLowerCamel":   strcase.ToLowerCamel,
+	"ToTypeName":     toGoType,
+	"ToTags":         toTags,
+	"ToRelationTags": toRelationTags,
+	"ToFieldType":    toGoFieldType,
+	"ToEnumType":     toGoEnumType,
+	"ToUserTypes":    toGoUserTypes,
+	"ToFieldNote":    toGoFieldNote,
 }
 
 var modelStructTmpl, _ = template.New("goFile").Funcs(GormFuncMap).Parse(`package {{.Package}}
@@ -38,12 +48,22 @@ import (
 	"gorm.io/gorm"
 	"database/sql"
 )
-
-type {{ToCamel .Table.Name}} struct {
+{{if .Table.IsView}}
+// {{ToCamel .Table.Table}} is read from the {{.Table.Type}} {{.Table.Table}}, its fields are read-only.
+{{- end}}{{if and .SourceComments .Table.Span.Line}}
+// generated from {{.Table.Span}}{{end}}
+type {{ToCamel .Table.Table}} struct {
 {{- range .Table.Columns}}
-	{{ToLowerCamel .Name}} {{ToTypeName .Type .Attribute}} ` + "`{{ToTags .}}`" + `
+	{{- if and $.SourceComments .Span.Line}}
+	// generated from {{.Span}}
+	{{- end}}{{ToFieldNote .}}
+	{{ToCamel .Name}} {{ToFieldType $.Table .}} ` + "`{{ToTags $.Table .}}`" + `
+{{- end}}
+{{- range .Table.ForeignKeys}}
+	{{ToCamel .RelationName}} *{{ToCamel .RefTable.Table}} ` + "`{{ToRelationTags .}}`" + `
 {{- end}}
-}`)
+}
+{{- range .Table.Columns}}{{ToEnumType $.Table .}}{{end}}{{ToUserTypes .Table}}`)
 
 func GetDefaultGormConfig() GormConfig {
 	config := GormConfig{
@@ -67,19 +87,26 @@ func GenerateGorm(config GormConfig) (map[string]string, error) {
 	return files, nil
 }
 
-func toTags(col Column) string {
+func toTags(table *Table, col *Column) string {
 	gormTag := strings.Builder{}
 	gormTag.WriteString("column:")
 	gormTag.WriteString(strcase.ToLowerCamel(col.Name))
 
 	gormTag.WriteString(";type:")
-	gormTag.WriteString(toGoType(col.DataType, col.Attribute))
+	if datatype, ok := col.DataType.(*UserDatatype); ok {
+		gormTag.WriteString(datatype.String())
+		// gorm maps neither the placeholders nor the structs and slices of user-defined types,
+		// which implement no sql.Scanner, the field is ignored
+		gormTag.WriteString(";-")
+	} else {
+		gormTag.WriteString(toGoType(col.DataType, col.Attribute))
+	}
 
 	if col.Attribute.IsPrimaryKey() {
 		gormTag.WriteString(";primary_key")
 	}
 	isNotNull := false
-	for o, expr := range col.Attribute {
+	for o := range col.Attribute {
 		switch o {
 		case ast.ConstraintTypePK:
 			if !col.Attribute.IsPrimaryKey() {
@@ -87,15 +114,8 @@ func toTags(col Column) string {
 			}
 		case ast.ConstraintTypeNotNull:
 			isNotNull = true
-		// case ast.ColumnOptionAutoIncrement:
-		// 	gormTag.WriteString(";AUTO_INCREMENT")
-		case ast.ConstraintTypeDefault:
-			if value := getDefaultValue(expr); value != "" {
-				gormTag.WriteString(";default:")
-				gormTag.WriteString(value)
-			}
-		case ast.ConstraintTypeUnique:
-			gormTag.WriteString(";unique")
+		case ConstraintTypeAutoIncrement:
+			gormTag.WriteString(";autoIncrement")
 		case ast.ConstraintTypeNull:
 			// gormTag.WriteString(";NULL")
 			// canNull = true
@@ -110,11 +130,143 @@ func toTags(col Column) string {
 	if !col.Attribute.IsPrimaryKey() && isNotNull {
 		gormTag.WriteString(";NOT NULL")
 	}
+	if value := toGormDefault(col.DefaultValue); value != "" {
+		gormTag.WriteString(";default:")
+		gormTag.WriteString(value)
+	}
+	gormTag.WriteString(toIndexTags(table, col))
+	if table.IsView() {
+		gormTag.WriteString(";->")
+	} else {
+		gormTag.WriteString(toTriggerPermission(col))
+	}
 
 	return fmt.Sprintf(`gorm:"%v"`, gormTag.String())
 }
 
+// toTriggerPermission keeps gorm from writing columns a trigger assigns, columns filled from a sequence
+// are left to autoIncrement.
+func toTriggerPermission(col *Column) string {
+	if col.Sequence != nil {
+		return ""
+	}
+	onInsert, onUpdate := col.IsSetByTrigger("INSERT"), col.IsSetByTrigger("UPDATE")
+	switch {
+	case onInsert && onUpdate:
+		return ";<-:false"
+	case onInsert:
+		return ";<-:update"
+	case onUpdate:
+		return ";<-:create"
+	}
+	return ""
+}
+
+// toGormDefault prints the default for the default tag, sequences are left to autoIncrement.
+// Quotes and backslashes are escaped for the struct tag, semicolons for gorm.
+func toGormDefault(value *DefaultValue) string {
+	if value == nil || value.Kind == DefaultNextval {
+		return ""
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, ";", `\\;`)
+	return replacer.Replace(value.OracleSQL())
+}
+
+// toIndexTags describes the unique constraints and the indexes the column takes part in,
+// composite ones are shared by name and ordered by priority.
+func toIndexTags(table *Table, col *Column) string {
+	tags := strings.Builder{}
+	for n, uc := range table.UniqueConstraints {
+		i := slices.Index(uc.Columns, col)
+		if i < 0 {
+			continue
+		}
+		if len(uc.Columns) == 1 {
+			tags.WriteString(";unique")
+			continue
+		}
+		name := uc.Name
+		if name == "" {
+			name = fmt.Sprintf("UK_%v_%v", table.Table, n+1)
+		}
+		fmt.Fprintf(&tags, ";uniqueIndex:%v,priority:%v", name, i+1)
+	}
+
+	indexes, positions := table.GetIndexes(col)
+	for n, index := range indexes {
+		kind := "index"
+		if index.Unique {
+			kind = "uniqueIndex"
+		}
+		fmt.Fprintf(&tags, ";%v:%v", kind, index.Name)
+		if len(index.Columns) > 1 {
+			fmt.Fprintf(&tags, ",priority:%v", positions[n]+1)
+		}
+		if index.Columns[positions[n]].Direction == "DESC" {
+			tags.WriteString(",sort:desc")
+		}
+	}
+	return tags.String()
+}
+
+func goEnumTypeName(table *Table, col *Column) string {
+	return strcase.ToCamel(table.Table) + strcase.ToCamel(col.Name)
+}
+
+// toGoFieldType is the Go type of the column, columns restricted by a check to a list of
+// values get a typed string.
+func toGoFieldType(table *Table, col *Column) string {
+	if !isEnumColumn(col) {
+		return toGoType(col.DataType, col.Attribute)
+	}
+	if col.Attribute.IsAllowNull() {
+		return fmt.Sprintf("sql.Null[%v]", goEnumTypeName(table, col))
+	}
+	return goEnumTypeName(table, col)
+}
+
+// toGoEnumType declares the typed string of a column restricted to a list of values and a constant per value.
+func toGoEnumType(table *Table, col *Column) string {
+	if !isEnumColumn(col) {
+		return ""
+	}
+	typeName := goEnumTypeName(table, col)
+	decl := strings.Builder{}
+	fmt.Fprintf(&decl, "\n\ntype %v string\n\nconst (", typeName)
+	names := map[string]bool{}
+	for i, value := range col.AllowedValues() {
+		name := typeName + identifierPart(value)
+		if names[name] || name == typeName {
+			name = fmt.Sprintf("%v%v", name, i+1)
+		}
+		names[name] = true
+		fmt.Fprintf(&decl, "\n\t%v %v = %v", name, typeName, strconv.Quote(value))
+	}
+	decl.WriteString("\n)")
+	return decl.String()
+}
+
+// identifierPart turns a value into the camel case letters and digits usable in an identifier.
+func identifierPart(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, strcase.ToCamel(value))
+}
+
+func toRelationTags(fk *ForeignKey) string {
+	fieldName := func(c *Column) string { return strcase.ToCamel(c.Name) }
+	return fmt.Sprintf(`gorm:"foreignKey:%v;references:%v"`,
+		strings.Join(mapping(fk.Columns, fieldName), ","),
+		strings.Join(mapping(fk.RefColumns, fieldName), ","))
+}
+
 func toGoType(datatype element.Datatype, attrs AttributeMap) (name string) {
+	if userDatatype, ok := datatype.(*UserDatatype); ok {
+		return toGoUserType(userDatatype, attrs)
+	}
 	if attrs.IsAllowNull() {
 		switch datatype.DataDef() {
 		case element.DataDefInteger, element.DataDefInt, element.DataDefSmallInt:
@@ -159,3 +311,73 @@ func toGoType(datatype element.Datatype, attrs AttributeMap) (name string) {
 	}
 	return
 }
+
+// toGoUserType maps an object type to its struct and a collection to a slice of its element,
+// a type that cannot be mapped to the placeholder named after it.
+func toGoUserType(datatype *UserDatatype, attrs AttributeMap) string {
+	switch {
+	case datatype.IsPlaceholder():
+		return goPlaceholderName(datatype)
+	case datatype.Type.IsCollection():
+		return "[]" + toGoType(datatype.Type.Element.DataType, datatype.Type.Element.Attribute)
+	case attrs.IsAllowNull():
+		return fmt.Sprintf("sql.Null[%v]", strcase.ToCamel(datatype.Type.Name))
+	}
+	return strcase.ToCamel(datatype.Type.Name)
+}
+
+// toGoFieldNote warns that gorm ignores the field of a user-defined type, it is empty for other fields.
+func toGoFieldNote(col *Column) string {
+	datatype, ok := col.DataType.(*UserDatatype)
+	if !ok {
+		return ""
+	}
+	reason := "the object type %v implements no sql.Scanner, read it through godror"
+	switch {
+	case datatype.Ref:
+		reason = "%v is a reference gorm cannot map"
+	case datatype.Type == nil:
+		reason = "the type %v is not defined in the schema"
+	case datatype.Type.IsCollection():
+		reason = "the collection type %v implements no sql.Scanner, read it through godror"
+	}
+	return fmt.Sprintf("\n\t// %v is ignored by gorm: "+reason+".", strcase.ToCamel(col.Name), datatype)
+}
+
+func goPlaceholderName(datatype *UserDatatype) string {
+	if datatype.Ref {
+		return strcase.ToCamel(datatype.Name) + "Ref"
+	}
+	return strcase.ToCamel(datatype.Name)
+}
+
+// toGoUserTypes declares a struct per object type the columns of the table are made of, attributes of
+// object types become nested structs. Types that cannot be mapped are declared as placeholders.
+func toGoUserTypes(table *Table) string {
+	return goUserTypeDecls(table.Columns)
+}
+
+func goUserTypeDecls(columns []*Column) string {
+	decl := strings.Builder{}
+	for _, userType := range ObjectTypes(columns) {
+		typeName := strcase.ToCamel(userType.Name)
+		fmt.Fprintf(&decl, "\n\n// %v is the object type %v.\ntype %v struct {", typeName, userType.QualifiedName(), typeName)
+		for _, attribute := range userType.AllAttributes() {
+			fmt.Fprintf(&decl, "\n\t%v %v `godror:\"%v\"`", strcase.ToCamel(attribute.Name), toGoType(attribute.DataType, attribute.Attribute), attribute.Name)
+		}
+		decl.WriteString("\n}")
+	}
+	declared := map[string]bool{}
+	for _, datatype := range placeholderTypes(columns) {
+		if declared[goPlaceholderName(datatype)] {
+			continue
+		}
+		declared[goPlaceholderName(datatype)] = true
+		reason := "is not defined in the schema"
+		if datatype.Ref {
+			reason = "is a reference gorm cannot map"
+		}
+		fmt.Fprintf(&decl, "\n\n// %v stands for the Oracle type %v which %v.\ntype %v any", goPlaceholderName(datatype), datatype, reason, goPlaceholderName(datatype))
+	}
+	return decl.String()
+}
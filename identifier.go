@@ -0,0 +1,89 @@
+package ddlcode
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	parser "github.com/codeindex2937/oracle-sql-parser"
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+// reservedWords are the Oracle reserved words, they cannot be used as unquoted identifiers.
+var reservedWords = []string{
+	"ACCESS", "ADD", "ALL", "ALTER", "AND", "ANY", "AS", "ASC", "AUDIT", "BETWEEN", "BY", "CHAR", "CHECK", "CLUSTER",
+	"COLUMN", "COMMENT", "COMPRESS", "CONNECT", "CREATE", "CURRENT", "DATE", "DECIMAL", "DEFAULT", "DELETE", "DESC",
+	"DISTINCT", "DROP", "ELSE", "EXCLUSIVE", "EXISTS", "FILE", "FLOAT", "FOR", "FROM", "GRANT", "GROUP", "HAVING",
+	"IDENTIFIED", "IMMEDIATE", "IN", "INCREMENT", "INDEX", "INITIAL", "INSERT", "INTEGER", "INTERSECT", "INTO", "IS",
+	"LEVEL", "LIKE", "LOCK", "LONG", "MAXEXTENTS", "MINUS", "MLSLABEL", "MODE", "MODIFY", "NOAUDIT", "NOCOMPRESS",
+	"NOT", "NOWAIT", "NULL", "NUMBER", "OF", "OFFLINE", "ON", "ONLINE", "OPTION", "OR", "ORDER", "PCTFREE", "PRIOR",
+	"PUBLIC", "RAW", "RENAME", "RESOURCE", "REVOKE", "ROW", "ROWID", "ROWNUM", "ROWS", "SELECT", "SESSION", "SET",
+	"SHARE", "SIZE", "SMALLINT", "START", "SUCCESSFUL", "SYNONYM", "SYSDATE", "TABLE", "THEN", "TO", "TRIGGER", "UID",
+	"UNION", "UNIQUE", "UPDATE", "USER", "VALIDATE", "VALUES", "VARCHAR", "VARCHAR2", "VIEW", "WHENEVER", "WHERE", "WITH",
+}
+
+var unquotedIdentifierPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_$#]*$`)
+
+// needsQuoting reports whether Oracle only takes the name in double quotes,
+// that is when it is not upper case, contains other characters or is a reserved word.
+func needsQuoting(name string) bool {
+	return !unquotedIdentifierPattern.MatchString(name) || slices.Contains(reservedWords, name)
+}
+
+// quoteIdentifier quotes the name for Oracle when needed.
+func quoteIdentifier(name string) string {
+	if !needsQuoting(name) {
+		return name
+	}
+	return `"` + name + `"`
+}
+
+func isQuoted(id *element.Identifier) bool {
+	return id.Typ == element.IdentifierTypeQuoted
+}
+
+// parseOracle parses the source by the oracle parser and normalizes the identifiers of the nodes.
+func parseOracle(source string) ([]ast.Node, error) {
+	nodes, err := parser.Parser(source)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		normalizeIdentifiers(reflect.ValueOf(node))
+	}
+	return nodes, nil
+}
+
+// normalizeIdentifiers folds the unquoted identifiers found in the node to upper case as Oracle does,
+// quoted identifiers are kept as written.
+func normalizeIdentifiers(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if id, ok := v.Interface().(*element.Identifier); ok {
+			if id.Typ == element.IdentifierTypeNonQuoted {
+				id.Value = strings.ToUpper(id.Value)
+			}
+			return
+		}
+		normalizeIdentifiers(v.Elem())
+	case reflect.Interface:
+		if !v.IsNil() {
+			normalizeIdentifiers(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				normalizeIdentifiers(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizeIdentifiers(v.Index(i))
+		}
+	}
+}
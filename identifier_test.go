@@ -0,0 +1,89 @@
+package ddlcode
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestNeedsQuoting(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"ORDERS", false},
+		{"ORDER_LINES$2", false},
+		{"orders", true},
+		{"MixedCase", true},
+		{"2ND", true},
+		{"WITH SPACE", true},
+		{"SELECT", true},
+		{"DATE", true},
+	}
+	for _, tt := range tests {
+		if got := needsQuoting(tt.name); got != tt.want {
+			t.Errorf("needsQuoting(%q) is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIdentifierNormalization(t *testing.T) {
+	db, diags, err := Parse(`CREATE TABLE orders (id NUMBER PRIMARY KEY, "lowerCol" VARCHAR2(10));
+CREATE TABLE "MixedCase" ("Id" NUMBER PRIMARY KEY, order_id NUMBER REFERENCES ORDERS (ID));
+CREATE TABLE other (m_id NUMBER REFERENCES mixedcase (id));
+COMMENT ON TABLE Orders IS 'all orders';
+COMMENT ON COLUMN "MixedCase"."Id" IS 'key';`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := mapping(db.Columns, func(col *Column) string {
+		text := col.Table + "." + col.Name
+		if col.Quoted {
+			text += " quoted"
+		}
+		if col.Comment != "" {
+			text += " " + col.Comment
+		}
+		return text
+	})
+	want := []string{"ORDERS.ID", `ORDERS.lowerCol quoted`, "MixedCase.Id quoted key", "MixedCase.ORDER_ID", "OTHER.M_ID"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got columns %q, want %q", got, want)
+	}
+	if orders := db.Tables[0]; orders.Quoted || orders.Comment != "all orders" {
+		t.Errorf("got ORDERS quoted %v with comment %q", orders.Quoted, orders.Comment)
+	}
+	if mixed := db.Tables[1]; !mixed.Quoted || len(mixed.ForeignKeys) != 1 || mixed.ForeignKeys[0].RefTable != db.Tables[0] {
+		t.Errorf("foreign key of MixedCase not resolved to ORDERS")
+	}
+	// an unquoted name is folded to MIXEDCASE, which is not the quoted table
+	messages := mapping(diags, func(d Diagnostic) string { return d.Message })
+	if wantDiags := []string{"foreign key <unnamed> references unknown table MIXEDCASE"}; !slices.Equal(messages, wantDiags) {
+		t.Errorf("got diagnostics %q, want %q", messages, wantDiags)
+	}
+}
+
+func TestJavaQuotedNames(t *testing.T) {
+	db, _, err := Parse(`CREATE TABLE "MixedCase" ("Id" NUMBER PRIMARY KEY, "select" NUMBER, plain NUMBER);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := GetDefaultJavaConfig()
+	config.Package = "app"
+	config.Table = db.Tables[0]
+	files, err := GenerateJava(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entity, executor := files["jpa/MixedCaseEntity.java"], files["repository/MixedCaseSqlExecutor.java"]
+	for _, want := range []string{`@Table(name = "\"MixedCase\"")`, `@Column(name = "\"Id\"")`, `@Column(name = "\"select\"")`, `@Column(name = "PLAIN")`} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity has no %v", want)
+		}
+	}
+	if want := `insert into \"MixedCase\"(\"Id\",\"select\",PLAIN) values (:id,:select,:plain)`; !strings.Contains(executor, want) {
+		t.Errorf("executor has no %v", want)
+	}
+}
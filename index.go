@@ -0,0 +1,141 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+)
+
+type createIndexStmt struct {
+	sourceNode
+	Schema      string
+	Name        string
+	TableSchema string
+	Table       string
+	Unique      bool
+	Bitmap      bool
+	Columns     []indexColumnDef
+}
+
+type indexColumnDef struct {
+	// Column is empty for function-based index expressions
+	Column     string
+	Expression string
+	Direction  string
+}
+
+// parseCreateIndex reads CREATE [UNIQUE|BITMAP] INDEX name ON table (expr [ASC|DESC], ...),
+// the physical attributes which follow are skipped.
+func parseCreateIndex(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	index := &createIndexStmt{sourceNode: sourceNode{text: stmt.Source}}
+	switch {
+	case r.accept("UNIQUE"):
+		index.Unique = true
+	case r.accept("BITMAP"):
+		index.Bitmap = true
+	case r.accept("MULTIVALUE"):
+	}
+	if !r.accept("INDEX") {
+		return nil, syntaxError(stmt, r.peek(), "expected INDEX")
+	}
+	r.accept("IF", "NOT", "EXISTS")
+	index.Schema, index.Name = r.qualifiedName()
+	if !r.accept("ON") {
+		return nil, syntaxError(stmt, r.peek(), "expected ON")
+	}
+	if r.peek().Is("CLUSTER") {
+		return nil, fmt.Errorf("cluster index %v is not supported", index.Name)
+	}
+	index.TableSchema, index.Table = r.qualifiedName()
+	if !r.peek().Is("(") {
+		// table alias of a bitmap join index
+		r.next()
+	}
+	if !r.peek().Is("(") {
+		return nil, syntaxError(stmt, r.peek(), "expected column list")
+	}
+
+	for _, item := range splitTopLevel(r.group(), ",") {
+		if len(item) == 0 {
+			continue
+		}
+		def := indexColumnDef{}
+		if last := item[len(item)-1]; last.Is("ASC") || last.Is("DESC") {
+			def.Direction = strings.ToUpper(last.Text)
+			item = item[:len(item)-1]
+		}
+		if len(item) == 1 && (item[0].Kind == tokenWord || item[0].Kind == tokenQuotedIdent) {
+			def.Column = item[0].Value()
+		} else {
+			def.Expression = stmt.Source[item[0].Offset-stmt.Offset : item[len(item)-1].End()-stmt.Offset]
+		}
+		index.Columns = append(index.Columns, def)
+	}
+	return index, nil
+}
+
+func translateIndex(table *Table, stmt *createIndexStmt) (*Index, error) {
+	index := &Index{
+		Schema: stmt.Schema,
+		Name:   stmt.Name,
+		Table:  table,
+		Unique: stmt.Unique,
+		Bitmap: stmt.Bitmap,
+	}
+	for _, def := range stmt.Columns {
+		ic := IndexColumn{Expression: def.Expression, Direction: def.Direction}
+		if def.Column != "" {
+			ic.Column = table.getColumn(def.Column)
+			if ic.Column == nil {
+				return nil, fmt.Errorf("unknown column: %v.%v", table.Table, def.Column)
+			}
+		}
+		index.Columns = append(index.Columns, ic)
+	}
+	return index, nil
+}
+
+func translateUniqueConstraint(table *Table, spec *ast.OutOfLineConstraint) (*UniqueConstraint, error) {
+	uc := &UniqueConstraint{}
+	if spec.Name != nil {
+		uc.Name = spec.Name.Value
+	}
+	for _, k := range spec.Columns {
+		c := table.getColumn(k.Value)
+		if c == nil {
+			return nil, fmt.Errorf("unknown column: %v.%v", table.Table, k.Value)
+		}
+		uc.Columns = append(uc.Columns, c)
+	}
+	return uc, nil
+}
+
+// indexInfos flattens an index into one IndexInfo row per column.
+func indexInfos(index *Index) []IndexInfo {
+	infos := []IndexInfo{}
+	indexType := "B-TREE"
+	if index.Bitmap {
+		indexType = "BITMAP"
+	}
+	for _, ic := range index.Columns {
+		info := IndexInfo{
+			Schema:    index.Schema,
+			Table:     index.Table.Table,
+			Direction: ic.Direction,
+			IndexType: indexType,
+			Name:      index.Name,
+			Unique:    fmt.Sprintf("%v", index.Unique),
+		}
+		if ic.Column != nil {
+			info.Column = ic.Column.Name
+		} else {
+			info.Column = ic.Expression
+			info.IndexType = "FUNCTION-BASED " + indexType
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
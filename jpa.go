@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 
@@ -24,26 +25,57 @@ type JavaConfig struct {
 	DaoTemplate            *template.Template
 	RepositoryTemplate     *template.Template
 	RepositoryTestTemplate *template.Template
+	// EmbeddableTemplate generates an @Embeddable class per object type the columns are made of
+	EmbeddableTemplate *template.Template
+	// AppRole is the user or role the application connects as, the repositories of tables it cannot change
+	// are generated read-only as those of views
+	AppRole    string
+	Privileges Privileges
+	// SourceComments adds the file and line each entity and field is generated from
+	SourceComments bool
 }
 
 var JavaFuncMap = template.FuncMap{
-	"ToCamel":               strcase.ToCamel,
-	"ToLowerCamel":          strcase.ToLowerCamel,
-	"ToConstant":            func(s string) string { return strings.ToUpper(strcase.ToSnake(s)) },
-	"ToTypeName":            toJavaType,
-	"IsCompositePrimaryKey": isCompositePrimaryKey,
-	"GetAllFields":          getAllFields,
-	"CompareFields":         compareJavaFields,
-	"GetPkFields":           getPkFields,
-	"ComparePkFields":       compareJavaPkFields,
-	"GetImportPaths":        getJavaImportPaths,
-	"GetPkImportPaths":      getJavaPkImportPaths,
-	"GetPkCriteria":         getPkCriteria,
-	"GetNonPkAssignment":    getNonPkAssignment,
-	"GetAllColumn":          getAllColumn,
-	"GetAllPlaceholder":     getAllPlaceholder,
-	"GetPkTypeWithMember":   getPkTypeWithMember,
-	"GetAllTypeWithMember":  getAllTypeWithMember,
+	"ToCamel":                 strcase.ToCamel,
+	"ToLowerCamel":            strcase.ToLowerCamel,
+	"ToConstant":              func(s string) string { return strings.ToUpper(strcase.ToSnake(s)) },
+	"ToTypeName":              toJavaType,
+	"IsCompositePrimaryKey":   isCompositePrimaryKey,
+	"GetAllFields":            getAllFields,
+	"CompareFields":           compareJavaFields,
+	"GetPkFields":             getPkFields,
+	"ComparePkFields":         compareJavaPkFields,
+	"GetImportPaths":          getJavaImportPaths,
+	"GetPkImportPaths":        getJavaPkImportPaths,
+	"GetPkCriteria":           getPkCriteria,
+	"GetNonPkAssignment":      getNonPkAssignment,
+	"GetAllColumn":            getAllColumn,
+	"GetAllPlaceholder":       getAllPlaceholder,
+	"GetInsertColumn":         getInsertColumn,
+	"GetInsertPlaceholder":    getInsertPlaceholder,
+	"GetPkTypeWithMember":     getPkTypeWithMember,
+	"GetAllTypeWithMember":    getAllTypeWithMember,
+	"GetJoinColumns":          getJoinColumns,
+	"GetTableIndexes":         getTableIndexes,
+	"IsJavaEnum":              isJavaEnum,
+	"HasColumnDefault":        hasColumnDefault,
+	"ToColumnDefault":         toColumnDefault,
+	"ToFieldType":             toJavaFieldType,
+	"Join":                    strings.Join,
+	"QuoteName":               quoteJavaName,
+	"ToColumnPermissions":     toColumnPermissions,
+	"ToTypeAnnotations":       toJavaTypeAnnotations,
+	"ToColumnDefinition":      toColumnDefinition,
+	"HasCollectionColumn":     hasCollectionColumn,
+	"GetObjectTypes":          ObjectTypes,
+	"GetColumnImportPaths":    func(cols []*Column) string { return strings.Join(getImportPaths(cols), "\n") },
+	"GetParameterImportPaths": getParameterImportPaths,
+	"ToSqlParameters":         toSqlParameters,
+	"ToParameterList":         toJavaParameterList,
+	"ToParameterName":         toJavaParameterName,
+	"ToReturnType":            toJavaReturnType,
+	"ToReturnClass":           toJavaReturnClass,
+	"HasOutParameter":         hasOutParameter,
 	"GetPkType": func(table *Table) string {
 		if isCompositePrimaryKey(table) {
 			return strcase.ToCamel(table.Table) + "PK"
@@ -61,32 +93,82 @@ var JavaEntityTemplate = `package {{.Package}}.jpa;
 
 import jakarta.persistence.*;
 import java.util.Objects;
+{{- if HasColumnDefault .Table}}
+import org.hibernate.annotations.ColumnDefault;
+{{- end}}
+{{- if .Table.IsView}}
+import org.hibernate.annotations.Immutable;
+{{- end}}
+{{- if HasCollectionColumn .Table.Columns}}
+import org.hibernate.annotations.JdbcTypeCode;
+import org.hibernate.type.SqlTypes;
+{{- end}}
 {{ GetImportPaths .Table }}
-
+{{if and .SourceComments .Table.Span.Line}}
+// generated from {{.Table.Span}}{{end}}
 @Entity
-@Table(name = "{{.Table.Table}}"{{if gt (len .Schema) 0}}, schema = "{{.Schema}}"{{end}})
+{{- if .Table.IsView}}
+@Immutable
+{{- end}}
+@Table(name = "{{QuoteName .Table.Table}}"{{if gt (len .Schema) 0}}, schema = "{{QuoteName .Schema}}"{{end}}{{GetTableIndexes .Table}})
 {{- if IsCompositePrimaryKey .Table}}
 @IdClass({{ToCamel .Table.Table}}PK.class)
 {{- end}}
 public class {{ToCamel .Table.Table}}Entity {
 {{ $table := .Table}}
 {{- range .Table.Columns}}
+    {{- if and $.SourceComments .Span.Line}}
+    // generated from {{.Span}}
+    {{- end}}
     {{- if (.Attribute.IsPrimaryKey) }}
     @Id
+    {{- if .Identity }}
+    @GeneratedValue(strategy = GenerationType.IDENTITY)
+    {{- else if .Sequence }}
+    @SequenceGenerator(name = "{{.Sequence.Name}}", sequenceName = "{{.Sequence.Name}}"{{if .Sequence.Schema}}, schema = "{{.Sequence.Schema}}"{{end}}, allocationSize = {{or .Sequence.IncrementBy "1"}})
+    @GeneratedValue(strategy = GenerationType.SEQUENCE, generator = "{{.Sequence.Name}}")
     {{- end}}
-    @Column(name = "{{.Name}}")
-    private {{ToTypeName .DataType }} {{ToLowerCamel .Name}};
+    {{- end}}
+    {{- if IsJavaEnum .}}
+    @Enumerated(EnumType.STRING)
+    {{- end}}
+    {{- with ToColumnDefault .}}
+    @ColumnDefault("{{.}}")
+    {{- end}}
+    {{- with ToTypeAnnotations .}}
+    {{.}}
+    {{- end}}
+    @Column(name = "{{QuoteName .Name}}"{{ToColumnPermissions .}}{{ToColumnDefinition .}})
+    private {{ToFieldType .}} {{ToLowerCamel .Name}};
+{{ end }}
+{{- range .Table.ForeignKeys}}
+    @ManyToOne(fetch = FetchType.LAZY)
+    {{GetJoinColumns .}}
+    private {{ToCamel .RefTable.Table}}Entity {{ToLowerCamel .RelationName}};
 {{ end }}
 
 {{- range .Table.Columns}}
-    public {{ToTypeName .DataType }} get{{ToCamel .Name}}() {
+    {{- if IsJavaEnum .}}
+    public enum {{ToCamel .Name}} {
+        {{Join .AllowedValues ", "}}
+    }
+{{ end }}
+{{- end}}
+
+{{- range .Table.Columns}}
+    public {{ToFieldType .}} get{{ToCamel .Name}}() {
         return this.{{ToLowerCamel .Name}};
     }
 
-    public void set{{ToCamel .Name}}({{ToTypeName .DataType }} {{ToLowerCamel .Name}}) {
+    public void set{{ToCamel .Name}}({{ToFieldType .}} {{ToLowerCamel .Name}}) {
         this.{{ToLowerCamel .Name}} = {{ToLowerCamel .Name}};
     }
 {{end}}
+{{- range .Table.ForeignKeys}}
+    public {{ToCamel .RefTable.Table}}Entity get{{ToCamel .RelationName}}() {
+        return this.{{ToLowerCamel .RelationName}};
+    }
+{{end}}
 
     public boolean equals(Object o) {
         if (this == o) {
@@ -118,7 +200,7 @@ public class {{ToCamel .Table.Table}}PK implements Serializable {
 {{range .Table.Columns}}
 {{- if .Attribute.IsPrimaryKey}}
     @Id
-    @Column(name = "{{.Name}}")
+    @Column(name = "{{QuoteName .Name}}")
     private {{ToTypeName .DataType }} {{ToLowerCamel .Name}};
 {{end -}}
 {{end}}
@@ -154,15 +236,33 @@ public class {{ToCamel .Table.Table}}PK implements Serializable {
 
 var JavaDaoTemplate = `package {{.Package}}.dao;
 
+{{- if .IsReadOnly}}
+
+import java.util.List;
+import java.util.Optional;
+import org.springframework.data.repository.Repository;
+{{- else}}
+
 import org.springframework.data.repository.CrudRepository;
+{{- end}}
 import {{.Package}}.jpa.{{ToCamel .Table.Table}}Entity;
 {{- if IsCompositePrimaryKey .Table }}
 import {{.Package}}.jpa.{{ToCamel .Table.Table}}PK;
 {{- end}}
 {{- $pkType := GetPkType .Table }}
-
 {{- if IsCompositePrimaryKey .Table }}
-public interface {{ToCamel .Table.Table}}Dao extends CrudRepository<{{ToCamel .Table.Table}}Entity, {{ToCamel .Table.Table}}PK> {
+{{- $pkType = print (ToCamel .Table.Table) "PK" }}
+{{- end}}
+
+{{- if .IsReadOnly}}
+{{- if not .Table.IsView}}
+
+// {{ToCamel .Table.Table}}Dao is read-only, {{.AppRole}} is granted no INSERT, UPDATE or DELETE on {{.Table.QualifiedName}}.
+{{- end}}
+public interface {{ToCamel .Table.Table}}Dao extends Repository<{{ToCamel .Table.Table}}Entity, {{$pkType}}> {
+  List<{{ToCamel .Table.Table}}Entity> findAll();
+
+  Optional<{{ToCamel .Table.Table}}Entity> findById({{$pkType}} id);
 {{- else}}
 public interface {{ToCamel .Table.Table}}Dao extends CrudRepository<{{ToCamel .Table.Table}}Entity, {{$pkType}}> {
 {{- end}}
@@ -185,14 +285,19 @@ import {{.Package}}.jpa.{{ToCamel .Table.Table}}Entity;
 {{- if IsCompositePrimaryKey .Table }}
 import {{.Package}}.jpa.{{ToCamel .Table.Table}}PK;
 {{- end}}
+{{- range GetObjectTypes .Table.Columns}}
+import {{$.Package}}.jpa.{{ToCamel .Name}};
+{{- end}}
 {{- $pkType := GetPkType .Table }}
 
 @Component
 public class {{ToCamel .Table.Table}}SqlExecutor {
-  private static final String SQL_QUERY_{{ToConstant .Table.Table}} = "select {{GetAllColumn .Table}} from {{.Table.Table}} where {{GetPkCriteria .Table}}";
-  private static final String SQL_DELETE_{{ToConstant .Table.Table}} = "delete from {{.Table.Table}} where {{GetPkCriteria .Table}}";
-  private static final String SQL_INSERT_{{ToConstant .Table.Table}} = "insert into {{.Table.Table}}({{GetAllColumn .Table}}) values ({{GetAllPlaceholder .Table}})";
-  private static final String SQL_UPDATE_{{ToConstant .Table.Table}} = "update {{.Table.Table}} set {{GetNonPkAssignment .Table}} where {{GetPkCriteria .Table}}";
+  private static final String SQL_QUERY_{{ToConstant .Table.Table}} = "select {{GetAllColumn .Table}} from {{QuoteName .Table.Table}} where {{GetPkCriteria .Table}}";
+{{- if not .IsReadOnly}}
+  private static final String SQL_DELETE_{{ToConstant .Table.Table}} = "delete from {{QuoteName .Table.Table}} where {{GetPkCriteria .Table}}";
+  private static final String SQL_INSERT_{{ToConstant .Table.Table}} = "insert into {{QuoteName .Table.Table}}({{GetInsertColumn .Table}}) values ({{GetInsertPlaceholder .Table}})";
+  private static final String SQL_UPDATE_{{ToConstant .Table.Table}} = "update {{QuoteName .Table.Table}} set {{GetNonPkAssignment .Table}} where {{GetPkCriteria .Table}}";
+{{- end}}
 
   @Qualifier("primary")
   private final NamedParameterJdbcTemplate datasource;
@@ -236,6 +341,7 @@ public class {{ToCamel .Table.Table}}SqlExecutor {
 		});
   }
 	{{- end}}
+	{{- if not .IsReadOnly}}
   public int insert{{ToCamel .Table.Table}}({{GetAllTypeWithMember .Table}}) {
     MapSqlParameterSource params = new MapSqlParameterSource();
     {{- range .Table.Columns}}
@@ -259,6 +365,7 @@ public class {{ToCamel .Table.Table}}SqlExecutor {
     {{end}}
     return datasource.update(SQL_DELETE_{{ToConstant .Table.Table}}, params);
   }
+	{{- end}}
 }
 `
 
@@ -298,6 +405,8 @@ public class {{ToCamel .Table.Table}}SqlExecutorTest {
   }
 	{{- end}}
 
+	{{- if not .IsReadOnly}}
+
 	@Test
   public void testInsert{{ToCamel .Table.Table}}() {
     {{ToLowerCamel .Table.Table}}SqlExecutor.insert{{ToCamel .Table.Table}}();
@@ -312,9 +421,50 @@ public class {{ToCamel .Table.Table}}SqlExecutorTest {
   public void testDelete{{ToCamel .Table.Table}}() {
     {{ToLowerCamel .Table.Table}}SqlExecutor.delete{{ToCamel .Table.Table}}();
   }
+	{{- end}}
+}
+`
+
+var JavaEmbeddableTemplate = `package {{.Package}}.jpa;
+
+import jakarta.persistence.*;
+import org.hibernate.annotations.Struct;
+{{- if HasCollectionColumn .Type.AllAttributes}}
+import org.hibernate.annotations.JdbcTypeCode;
+import org.hibernate.type.SqlTypes;
+{{- end}}
+{{ GetColumnImportPaths .Type.AllAttributes }}
+{{if and .SourceComments .Type.Span.Line}}
+// generated from {{.Type.Span}}{{end}}
+@Embeddable
+@Struct(name = "{{with .Type.Schema}}{{QuoteName .}}.{{end}}{{QuoteName .Type.Name}}")
+public class {{ToCamel .Type.Name}} {
+{{- range .Type.AllAttributes}}
+    {{- with ToTypeAnnotations .}}
+    {{.}}
+    {{- end}}
+    @Column(name = "{{QuoteName .Name}}"{{ToColumnDefinition .}})
+    private {{ToTypeName .DataType}} {{ToLowerCamel .Name}};
+{{ end }}
+{{- range .Type.AllAttributes}}
+    public {{ToTypeName .DataType}} get{{ToCamel .Name}}() {
+        return this.{{ToLowerCamel .Name}};
+    }
+
+    public void set{{ToCamel .Name}}({{ToTypeName .DataType}} {{ToLowerCamel .Name}}) {
+        this.{{ToLowerCamel .Name}} = {{ToLowerCamel .Name}};
+    }
+{{end -}}
 }
 `
 
+// JavaEmbeddableConfig is the data of JavaEmbeddableTemplate.
+type JavaEmbeddableConfig struct {
+	Package        string
+	Type           *UserType
+	SourceComments bool
+}
+
 func GetDefaultJavaConfig() JavaConfig {
 	var err error
 	config := JavaConfig{
@@ -346,9 +496,23 @@ func GetDefaultJavaConfig() JavaConfig {
 		log.Fatal(err)
 	}
 
+	config.EmbeddableTemplate, err = template.New("javaEmbeddable").Funcs(JavaFuncMap).Parse(JavaEmbeddableTemplate)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	return config
 }
 
+// IsReadOnly reports whether the repositories of the table get no write methods, that is for views and,
+// when AppRole is set, for tables AppRole is granted no INSERT, UPDATE or DELETE on.
+func (c JavaConfig) IsReadOnly() bool {
+	if c.Table.IsView() {
+		return true
+	}
+	return c.AppRole != "" && c.Privileges.IsReadOnly(c.AppRole, c.Table)
+}
+
 func GenerateJava(config JavaConfig) (map[string]string, error) {
 	files := map[string]string{}
 	entityName := strcase.ToCamel(config.Table.Table)
@@ -386,6 +550,17 @@ func GenerateJava(config JavaConfig) (map[string]string, error) {
 		files[path] = content
 	}
 
+	if config.EmbeddableTemplate != nil {
+		for _, userType := range ObjectTypes(config.Table.Columns) {
+			path := filepath.Join(config.ExportDir, "jpa", strcase.ToCamel(userType.Name)+".java")
+			content, err := generateFile(config.EmbeddableTemplate, JavaEmbeddableConfig{Package: config.Package, Type: userType, SourceComments: config.SourceComments})
+			if err != nil {
+				return nil, err
+			}
+			files[path] = content
+		}
+	}
+
 	return files, nil
 }
 
@@ -452,21 +627,7 @@ func getJavaImportPaths(table *Table) (name string) {
 func getImportPaths(cs []*Column) []string {
 	importPaths := map[string]struct{}{}
 	for _, c := range cs {
-		javaType := toJavaType(c.DataType)
-		switch javaType {
-		case "RowId":
-			importPaths["import java.sql.RowId;"] = struct{}{}
-		case "Blob":
-			importPaths["import java.sql.Blob;"] = struct{}{}
-		case "Clob":
-			importPaths["import java.sql.Clob;"] = struct{}{}
-		case "Timestamp":
-			importPaths["import java.sql.Timestamp;"] = struct{}{}
-		case "Date":
-			importPaths["import java.util.Date;"] = struct{}{}
-		case "BigDecimal":
-			importPaths["import java.math.BigDecimal;"] = struct{}{}
-		}
+		addJavaImportPaths(importPaths, c.DataType)
 	}
 
 	paths := maps.Keys(importPaths)
@@ -474,7 +635,35 @@ func getImportPaths(cs []*Column) []string {
 	return paths
 }
 
+// addJavaImportPaths adds the imports of the Java type of the data type, a collection needs those of its element.
+func addJavaImportPaths(importPaths map[string]struct{}, datatype element.Datatype) {
+	if userDatatype, ok := datatype.(*UserDatatype); ok {
+		if !userDatatype.IsPlaceholder() && userDatatype.Type.IsCollection() {
+			importPaths["import java.util.List;"] = struct{}{}
+			addJavaImportPaths(importPaths, userDatatype.Type.Element.DataType)
+		}
+		return
+	}
+	switch toJavaType(datatype) {
+	case "RowId":
+		importPaths["import java.sql.RowId;"] = struct{}{}
+	case "Blob":
+		importPaths["import java.sql.Blob;"] = struct{}{}
+	case "Clob":
+		importPaths["import java.sql.Clob;"] = struct{}{}
+	case "Timestamp":
+		importPaths["import java.sql.Timestamp;"] = struct{}{}
+	case "Date":
+		importPaths["import java.util.Date;"] = struct{}{}
+	case "BigDecimal":
+		importPaths["import java.math.BigDecimal;"] = struct{}{}
+	}
+}
+
 func toJavaType(datatype element.Datatype) (name string) {
+	if userDatatype, ok := datatype.(*UserDatatype); ok {
+		return toJavaUserType(userDatatype)
+	}
 	switch datatype.DataDef() {
 	case element.DataDefChar, element.DataDefVarchar2, element.DataDefNChar, element.DataDefNVarChar2, element.DataDefCharacter, element.DataDefCharacterVarying, element.DataDefCharVarying, element.DataDefNCharVarying, element.DataDefVarchar, element.DataDefNationalCharacter, element.DataDefNationalCharacterVarying, element.DataDefNationalChar, element.DataDefNationalCharVarying, element.DataDefXMLType:
 		name = "String"
@@ -538,7 +727,7 @@ func getPkCriteria(table *Table) string {
 			continue
 		}
 		entityName := strcase.ToLowerCamel(c.Name)
-		columnNames = append(columnNames, fmt.Sprintf("%v=:%v", c.Name, entityName))
+		columnNames = append(columnNames, fmt.Sprintf("%v=:%v", quoteJavaName(c.Name), entityName))
 	}
 	return strings.Join(columnNames, " AND ")
 }
@@ -550,7 +739,7 @@ func getNonPkAssignment(table *Table) string {
 			continue
 		}
 		entityName := strcase.ToLowerCamel(c.Name)
-		columnNames = append(columnNames, fmt.Sprintf("%v=:%v", c.Name, entityName))
+		columnNames = append(columnNames, fmt.Sprintf("%v=:%v", quoteJavaName(c.Name), entityName))
 	}
 	return strings.Join(columnNames, ",")
 }
@@ -558,7 +747,7 @@ func getNonPkAssignment(table *Table) string {
 func getAllColumn(table *Table) string {
 	columnNames := []string{}
 	for _, c := range table.Columns {
-		columnNames = append(columnNames, c.Name)
+		columnNames = append(columnNames, quoteJavaName(c.Name))
 	}
 	return strings.Join(columnNames, ",")
 }
@@ -572,6 +761,129 @@ func getAllPlaceholder(table *Table) string {
 	return strings.Join(columnNames, ",")
 }
 
+// getInsertColumn skips identity columns which are always generated by the database.
+func getInsertColumn(table *Table) string {
+	columnNames := []string{}
+	for _, c := range table.Columns {
+		if c.Identity != nil && c.Identity.Generation == "ALWAYS" {
+			continue
+		}
+		columnNames = append(columnNames, quoteJavaName(c.Name))
+	}
+	return strings.Join(columnNames, ",")
+}
+
+// getInsertPlaceholder takes the next value of the sequence for columns filled from a sequence.
+func getInsertPlaceholder(table *Table) string {
+	columnNames := []string{}
+	for _, c := range table.Columns {
+		if c.Identity != nil && c.Identity.Generation == "ALWAYS" {
+			continue
+		}
+		if c.Sequence != nil {
+			if c.Sequence.Schema != "" {
+				columnNames = append(columnNames, fmt.Sprintf("%v.%v.NEXTVAL", c.Sequence.Schema, c.Sequence.Name))
+			} else {
+				columnNames = append(columnNames, fmt.Sprintf("%v.NEXTVAL", c.Sequence.Name))
+			}
+			continue
+		}
+		entityName := strcase.ToLowerCamel(c.Name)
+		columnNames = append(columnNames, fmt.Sprintf(":%v", entityName))
+	}
+	return strings.Join(columnNames, ",")
+}
+
+// getJoinColumns maps the foreign key read-only, the columns themselves are written through their own fields.
+func getJoinColumns(fk *ForeignKey) string {
+	joinColumns := []string{}
+	for i, c := range fk.Columns {
+		joinColumns = append(joinColumns, fmt.Sprintf(`@JoinColumn(name = "%v", referencedColumnName = "%v", insertable = false, updatable = false)`, quoteJavaName(c.Name), quoteJavaName(fk.RefColumns[i].Name)))
+	}
+	if len(joinColumns) == 1 {
+		return joinColumns[0]
+	}
+	return fmt.Sprintf("@JoinColumns({\n        %v\n    })", strings.Join(joinColumns, ",\n        "))
+}
+
+// getTableIndexes lists the indexes and unique constraints as @Table attributes,
+// function-based indexes cannot be expressed by @Index and are left out.
+func getTableIndexes(table *Table) string {
+	indexes := []string{}
+	for _, index := range table.Indexes {
+		columnList := []string{}
+		for _, ic := range index.Columns {
+			if ic.Column == nil {
+				columnList = nil
+				break
+			}
+			columnList = append(columnList, strings.TrimSpace(quoteJavaName(ic.Column.Name)+" "+ic.Direction))
+		}
+		if columnList == nil {
+			continue
+		}
+		unique := ""
+		if index.Unique {
+			unique = ", unique = true"
+		}
+		indexes = append(indexes, fmt.Sprintf(`@Index(name = "%v", columnList = "%v"%v)`, index.Name, strings.Join(columnList, ", "), unique))
+	}
+	uniqueConstraints := []string{}
+	for _, uc := range table.UniqueConstraints {
+		columnNames := mapping(uc.Columns, func(c *Column) string { return `"` + quoteJavaName(c.Name) + `"` })
+		name := ""
+		if uc.Name != "" {
+			name = fmt.Sprintf(`name = "%v", `, uc.Name)
+		}
+		uniqueConstraints = append(uniqueConstraints, fmt.Sprintf("@UniqueConstraint(%vcolumnNames = {%v})", name, strings.Join(columnNames, ", ")))
+	}
+
+	attrs := strings.Builder{}
+	if len(indexes) > 0 {
+		fmt.Fprintf(&attrs, ",\n    indexes = {\n        %v\n    }", strings.Join(indexes, ",\n        "))
+	}
+	if len(uniqueConstraints) > 0 {
+		fmt.Fprintf(&attrs, ",\n    uniqueConstraints = {\n        %v\n    }", strings.Join(uniqueConstraints, ",\n        "))
+	}
+	return attrs.String()
+}
+
+var javaIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// isJavaEnum reports whether the column becomes an enum mapped with EnumType.STRING,
+// which requires every allowed value to be a Java identifier.
+func isJavaEnum(col *Column) bool {
+	if !isEnumColumn(col) {
+		return false
+	}
+	for _, value := range col.AllowedValues() {
+		if !javaIdentifierPattern.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasColumnDefault(table *Table) bool {
+	return slices.ContainsFunc(table.Columns, func(col *Column) bool { return toColumnDefault(col) != "" })
+}
+
+// toColumnDefault prints the default of the column as a Java string for @ColumnDefault,
+// sequences are left to @SequenceGenerator.
+func toColumnDefault(col *Column) string {
+	if col.DefaultValue == nil || col.DefaultValue.Kind == DefaultNextval {
+		return ""
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(col.DefaultValue.OracleSQL())
+}
+
+func toJavaFieldType(col *Column) string {
+	if isJavaEnum(col) {
+		return strcase.ToCamel(col.Name)
+	}
+	return toJavaType(col.DataType)
+}
+
 func getAllTypeWithMember(table *Table) string {
 	columnNames := []string{}
 	for _, c := range table.Columns {
@@ -592,3 +904,73 @@ func getPkTypeWithMember(table *Table) string {
 	}
 	return strings.Join(columnNames, ", ")
 }
+
+// quoteJavaName quotes a name Oracle takes only in double quotes, escaped for a Java string literal.
+func quoteJavaName(name string) string {
+	if !needsQuoting(name) {
+		return name
+	}
+	return `\"` + name + `\"`
+}
+
+// toColumnPermissions leaves the columns a trigger assigns to the trigger, columns filled from a sequence
+// are left to the generator.
+func toColumnPermissions(col *Column) string {
+	if col.Sequence != nil {
+		return ""
+	}
+	permissions := ""
+	if col.IsSetByTrigger("INSERT") {
+		permissions += ", insertable = false"
+	}
+	if col.IsSetByTrigger("UPDATE") {
+		permissions += ", updatable = false"
+	}
+	return permissions
+}
+
+// toJavaUserType maps an object type to its @Embeddable class and a collection to a List of its element,
+// a type that cannot be mapped to Object.
+func toJavaUserType(datatype *UserDatatype) string {
+	switch {
+	case datatype.IsPlaceholder():
+		return "Object"
+	case datatype.Type.IsCollection():
+		return fmt.Sprintf("List<%v>", toJavaType(datatype.Type.Element.DataType))
+	}
+	return strcase.ToCamel(datatype.Type.Name)
+}
+
+// toJavaTypeAnnotations maps object types as @Embedded structs and collections as arrays,
+// a type that cannot be mapped leaves a @Transient placeholder.
+func toJavaTypeAnnotations(col *Column) string {
+	datatype, ok := col.DataType.(*UserDatatype)
+	switch {
+	case !ok:
+		return ""
+	case datatype.Ref:
+		return fmt.Sprintf("// placeholder: %v cannot be mapped\n    @Transient", datatype)
+	case datatype.Type == nil:
+		return fmt.Sprintf("// placeholder: type %v is not defined in the schema\n    @Transient", datatype)
+	case datatype.Type.IsCollection():
+		return "@JdbcTypeCode(SqlTypes.ARRAY)"
+	}
+	return "@Embedded"
+}
+
+// toColumnDefinition names the SQL type of a collection column, Oracle arrays are named types.
+func toColumnDefinition(col *Column) string {
+	datatype, ok := col.DataType.(*UserDatatype)
+	if !ok || datatype.IsPlaceholder() || !datatype.Type.IsCollection() {
+		return ""
+	}
+	name := quoteJavaName(datatype.Type.Name)
+	if datatype.Type.Schema != "" {
+		name = quoteJavaName(datatype.Type.Schema) + "." + name
+	}
+	return fmt.Sprintf(`, columnDefinition = "%v"`, name)
+}
+
+func hasCollectionColumn(cols []*Column) bool {
+	return slices.ContainsFunc(cols, func(col *Column) bool { return toColumnDefinition(col) != "" })
+}
@@ -0,0 +1,93 @@
+package ddlcode
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	PrivilegeMatrixMarkdown = "markdown"
+	PrivilegeMatrixCSV      = "csv"
+)
+
+type PrivilegeMatrixConfig struct {
+	ExportDir string
+	// Format is PrivilegeMatrixMarkdown or PrivilegeMatrixCSV
+	Format     string
+	Tables     []*Table
+	Privileges Privileges
+	// Grantees are the columns of the matrix, all grantees of Privileges when empty
+	Grantees []string
+	// Effective adds the privileges held through PUBLIC and the granted roles
+	Effective bool
+}
+
+// matrixPrivileges are the privileges shown in the matrix, in order.
+var matrixPrivileges = []string{"SELECT", "READ", "INSERT", "UPDATE", "DELETE"}
+
+func GetDefaultPrivilegeMatrixConfig() PrivilegeMatrixConfig {
+	return PrivilegeMatrixConfig{
+		ExportDir: ".",
+		Format:    PrivilegeMatrixMarkdown,
+	}
+}
+
+// GeneratePrivilegeMatrix tells for each table which grantees may select, insert, update or delete its rows.
+func GeneratePrivilegeMatrix(config PrivilegeMatrixConfig) (map[string]string, error) {
+	grantees := config.Grantees
+	if len(grantees) == 0 {
+		grantees = config.Privileges.Grantees()
+	}
+	rows := [][]string{append([]string{"Table"}, grantees...)}
+	for _, table := range config.Tables {
+		row := []string{table.QualifiedName()}
+		for _, grantee := range grantees {
+			row = append(row, privilegeCell(config.Privileges.Granted(grantee, table, config.Effective)))
+		}
+		rows = append(rows, row)
+	}
+
+	files := map[string]string{}
+	switch config.Format {
+	case PrivilegeMatrixMarkdown:
+		content := strings.Builder{}
+		for i, row := range rows {
+			fmt.Fprintf(&content, "| %v |\n", strings.Join(row, " | "))
+			if i == 0 {
+				fmt.Fprintf(&content, "|%v\n", strings.Repeat(" --- |", len(row)))
+			}
+		}
+		files[filepath.Join(config.ExportDir, "privileges.md")] = content.String()
+	case PrivilegeMatrixCSV:
+		buf := bytes.NewBuffer([]byte{})
+		w := csv.NewWriter(buf)
+		if err := w.WriteAll(rows); err != nil {
+			return nil, err
+		}
+		files[filepath.Join(config.ExportDir, "privileges.csv")] = buf.String()
+	default:
+		return nil, fmt.Errorf("unknown privilege matrix format %v", config.Format)
+	}
+	return files, nil
+}
+
+// privilegeCell lists the privileges of the matrix among the granted ones, a privilege granted on the whole
+// table through one role and on some columns through another shows without columns.
+func privilegeCell(granted []*ObjectPrivilege) string {
+	cell := []string{}
+	for _, name := range matrixPrivileges {
+		var shown *ObjectPrivilege
+		for _, privilege := range granted {
+			if privilege.Privilege == name && (shown == nil || len(privilege.Columns) == 0) {
+				shown = privilege
+			}
+		}
+		if shown != nil {
+			cell = append(cell, shown.String())
+		}
+	}
+	return strings.Join(cell, ", ")
+}
@@ -0,0 +1,348 @@
+package ddlcode
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// MigrationStep is a statement of a migration script.
+type MigrationStep struct {
+	SQL string
+	// Destructive is set for the steps which may lose data: dropping a table or a column,
+	// changing the type of a column or narrowing it
+	Destructive bool
+	// Manual is set for the steps ddlcode cannot write, SQL is then empty and Note tells what to do
+	Manual bool
+	Note   string
+}
+
+type MigrationConfig struct {
+	ExportDir string
+	// Name names the scripts, <Name>.sql migrates and <Name>_rollback.sql rolls back
+	Name string
+	Diff SchemaDiff
+}
+
+func GetDefaultMigrationConfig() MigrationConfig {
+	return MigrationConfig{
+		ExportDir: ".",
+		Name:      "migration",
+	}
+}
+
+// GenerateMigration writes the script turning the old schema of the diff into the new one and the rollback script.
+func GenerateMigration(config MigrationConfig) (map[string]string, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("migration name is empty")
+	}
+	files := map[string]string{}
+	files[filepath.Join(config.ExportDir, config.Name+".sql")] = migrationScript(config.Diff.Migration())
+	files[filepath.Join(config.ExportDir, config.Name+"_rollback.sql")] = migrationScript(config.Diff.Rollback())
+	return files, nil
+}
+
+// migrationScript prints the steps, destructive and manual steps are preceded by a comment.
+func migrationScript(steps []MigrationStep) string {
+	script := strings.Builder{}
+	for _, step := range steps {
+		switch {
+		case step.Manual:
+			fmt.Fprintf(&script, "-- MANUAL: %v\n", step.Note)
+			continue
+		case step.Destructive:
+			fmt.Fprintf(&script, "-- DESTRUCTIVE: %v\n", step.Note)
+		}
+		fmt.Fprintf(&script, "%v;\n", step.SQL)
+	}
+	return script.String()
+}
+
+// Rollback returns the steps turning the new schema of the diff back into the old one.
+// Tables dropped by the migration are created again empty.
+func (d SchemaDiff) Rollback() []MigrationStep {
+	return d.Reverse().Migration()
+}
+
+// Reverse returns the diff from the new schema to the old one.
+func (d SchemaDiff) Reverse() SchemaDiff {
+	reverse := SchemaDiff{AddedTables: d.DroppedTables, DroppedTables: d.AddedTables, Tables: []*TableDiff{}}
+	reverseChanges := func(changes []Change) []Change {
+		reversed := []Change{}
+		for _, change := range changes {
+			reversed = append(reversed, Change{Property: change.Property, Old: change.New, New: change.Old})
+		}
+		return reversed
+	}
+	for _, table := range d.Tables {
+		reversed := &TableDiff{
+			Old:                table.New,
+			New:                table.Old,
+			AddedColumns:       table.DroppedColumns,
+			DroppedColumns:     table.AddedColumns,
+			Changes:            reverseChanges(table.Changes),
+			AddedForeignKeys:   table.DroppedForeignKeys,
+			DroppedForeignKeys: table.AddedForeignKeys,
+			AddedIndexes:       table.DroppedIndexes,
+			DroppedIndexes:     table.AddedIndexes,
+		}
+		for _, col := range table.AlteredColumns {
+			reversed.AlteredColumns = append(reversed.AlteredColumns, &ColumnDiff{Old: col.New, New: col.Old, Changes: reverseChanges(col.Changes)})
+		}
+		reverse.Tables = append(reverse.Tables, reversed)
+	}
+	return reverse
+}
+
+// Migration returns the steps turning the old schema of the diff into the new one. Foreign keys, indexes and
+// primary keys are dropped first, then tables are renamed and altered, created tables come referenced tables
+// first, and foreign keys are added once the keys they reference exist. Unchanged foreign keys referencing a
+// changed primary key are dropped before it and added back. Columns and tables are dropped last, referencing
+// tables first.
+func (d SchemaDiff) Migration() []MigrationStep {
+	w := oracleWriter{GetDefaultOracleDDLConfig()}
+	steps := []MigrationStep{}
+	add := func(destructive bool, note, format string, args ...any) {
+		steps = append(steps, MigrationStep{SQL: fmt.Sprintf(format, args...), Destructive: destructive, Note: note})
+	}
+
+	dropForeignKey := func(fk *ForeignKey) {
+		if fk.Name == "" {
+			steps = append(steps, MigrationStep{Manual: true, Note: fmt.Sprintf("drop the foreign key %v of %v by the name Oracle generated",
+				foreignKeyText(fk, (*Table).QualifiedName), fk.Table.QualifiedName())})
+			return
+		}
+		add(false, "", "ALTER TABLE %v DROP CONSTRAINT %v", w.tableName(fk.Table), w.identifier(fk.Name, false))
+	}
+	for _, table := range d.Tables {
+		for _, fk := range table.DroppedForeignKeys {
+			dropForeignKey(fk)
+		}
+	}
+	unbound, rebound := d.foreignKeysOnChangedKeys()
+	for _, fk := range unbound {
+		if fk.Name != "" {
+			dropForeignKey(fk)
+		}
+	}
+	for _, table := range d.Tables {
+		for _, index := range table.DroppedIndexes {
+			add(false, "", "DROP INDEX %v", w.objectName(index.Schema, index.Name))
+		}
+	}
+	for _, table := range d.Tables {
+		if i := slices.IndexFunc(table.Changes, isPrimaryKeyChange); i >= 0 && table.Changes[i].Old != "" {
+			// the foreign keys named by Oracle cannot be dropped by name, CASCADE drops them with the key
+			if slices.ContainsFunc(unbound, func(fk *ForeignKey) bool { return fk.RefTable == table.Old && fk.Name == "" }) {
+				add(false, "drops the unnamed foreign keys referencing it", "ALTER TABLE %v DROP PRIMARY KEY CASCADE", w.tableName(table.Old))
+				continue
+			}
+			add(false, "", "ALTER TABLE %v DROP PRIMARY KEY", w.tableName(table.Old))
+		}
+	}
+	for _, table := range d.Tables {
+		if table.IsRenamed() {
+			add(false, "", "ALTER TABLE %v RENAME TO %v", w.tableName(table.Old), w.identifier(table.New.Table, table.New.Quoted))
+		}
+	}
+	for _, table := range d.Tables {
+		for _, col := range table.AddedColumns {
+			add(false, "", "ALTER TABLE %v ADD (%v)", w.tableName(table.New), w.column(col))
+		}
+		for _, col := range table.AlteredColumns {
+			if step, ok := w.modifyColumn(table.New, col); ok {
+				steps = append(steps, step)
+			}
+		}
+	}
+	for _, table := range d.Tables {
+		if i := slices.IndexFunc(table.Changes, isPrimaryKeyChange); i >= 0 && table.Changes[i].New != "" {
+			add(false, "", "ALTER TABLE %v ADD %v", w.tableName(table.New), w.primaryKey(table.New))
+		}
+	}
+
+	// tables are created referenced tables first, a foreign key to a table created later is added afterwards
+	created := sortByForeignKeys(slices.Clone(d.AddedTables))
+	inline, deferred := foreignKeysAtCreation(created, false)
+	for _, table := range created {
+		add(false, "", "%v", w.createTable(table, inline[table]))
+	}
+	for _, table := range created {
+		for _, index := range table.Indexes {
+			add(false, "", "%v", w.createIndex(index))
+		}
+	}
+	for _, table := range d.Tables {
+		for _, index := range table.AddedIndexes {
+			add(false, "", "%v", w.createIndex(index))
+		}
+	}
+	for _, table := range d.Tables {
+		deferred = append(deferred, table.AddedForeignKeys...)
+	}
+	deferred = append(deferred, rebound...)
+	for _, fk := range deferred {
+		add(false, "", "ALTER TABLE %v ADD %v", w.tableName(fk.Table), w.foreignKey(fk))
+	}
+
+	for _, table := range created {
+		columns := slices.DeleteFunc(slices.Clone(table.Columns), func(c *Column) bool { return c.Comment == "" })
+		for _, comment := range w.comments(table, table.Comment != "", columns) {
+			add(false, "", "%v", comment)
+		}
+	}
+	for _, table := range d.Tables {
+		columns := slices.DeleteFunc(slices.Clone(table.AddedColumns), func(c *Column) bool { return c.Comment == "" })
+		for _, col := range table.AlteredColumns {
+			if slices.ContainsFunc(col.Changes, isCommentChange) {
+				columns = append(columns, col.New)
+			}
+		}
+		for _, comment := range w.comments(table.New, slices.ContainsFunc(table.Changes, isCommentChange), columns) {
+			add(false, "", "%v", comment)
+		}
+	}
+
+	for _, table := range d.Tables {
+		for _, col := range table.DroppedColumns {
+			add(true, fmt.Sprintf("drops column %v.%v and its data", table.New.Table, col.Name),
+				"ALTER TABLE %v DROP COLUMN %v", w.tableName(table.New), w.identifier(col.Name, col.Quoted))
+		}
+	}
+	dropped := sortByForeignKeys(slices.Clone(d.DroppedTables))
+	slices.Reverse(dropped)
+	for i, table := range dropped {
+		// a table still referenced by one dropped later is in a cycle of foreign keys
+		referenced := slices.ContainsFunc(table.ReferencedBy, func(fk *ForeignKey) bool {
+			return fk.Table != table && slices.Index(dropped, fk.Table) > i
+		})
+		statement := "DROP TABLE " + w.tableName(table)
+		if referenced {
+			statement += " CASCADE CONSTRAINTS"
+		}
+		add(true, fmt.Sprintf("drops table %v and its data", table.QualifiedName()), "%v", statement)
+	}
+	return steps
+}
+
+// foreignKeysOnChangedKeys returns the unchanged foreign keys of the old schema referencing a primary key which
+// is dropped, Oracle refuses to drop a referenced key, and their counterparts in the new schema which are added
+// back once the new key exists. Those of dropped tables are not added back.
+func (d SchemaDiff) foreignKeysOnChangedKeys() (unbound, rebound []*ForeignKey) {
+	dropped := []*ForeignKey{}
+	for _, table := range d.Tables {
+		dropped = append(dropped, table.DroppedForeignKeys...)
+	}
+	newTable := func(t *Table) *Table {
+		i := slices.IndexFunc(d.Tables, func(table *TableDiff) bool { return table.Old == t })
+		if i >= 0 {
+			return d.Tables[i].New
+		}
+		return t
+	}
+	for _, table := range d.Tables {
+		i := slices.IndexFunc(table.Changes, isPrimaryKeyChange)
+		if i < 0 || table.Changes[i].Old == "" {
+			continue
+		}
+		key := primaryKeyColumns(table.Old)
+		for _, fk := range table.Old.ReferencedBy {
+			if slices.Contains(dropped, fk) || !isSameColumnSet(fk.RefColumns, key) {
+				continue
+			}
+			unbound = append(unbound, fk)
+			if slices.Contains(d.DroppedTables, fk.Table) {
+				continue
+			}
+			referencing := newTable(fk.Table)
+			j := slices.IndexFunc(table.New.ReferencedBy, func(other *ForeignKey) bool {
+				return other.Table.QualifiedName() == referencing.QualifiedName() && other.Name == fk.Name &&
+					slices.Equal(mapping(other.Columns, getName), mapping(fk.Columns, getName))
+			})
+			if j >= 0 {
+				rebound = append(rebound, table.New.ReferencedBy[j])
+			}
+		}
+	}
+	return unbound, rebound
+}
+
+// isSameColumnSet reports whether the columns are the same in any order.
+func isSameColumnSet(x, y []*Column) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for _, col := range x {
+		if !slices.Contains(y, col) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrimaryKeyChange(change Change) bool {
+	return change.Property == ChangePrimaryKey
+}
+
+func isCommentChange(change Change) bool {
+	return change.Property == ChangeComment
+}
+
+// modifyColumn changes the type, the nullability and the default of the column in one MODIFY clause,
+// it reports false when only the comment changed. The nullability of primary key columns follows the key.
+func (w oracleWriter) modifyColumn(table *Table, col *ColumnDiff) (MigrationStep, bool) {
+	clause := []string{w.identifier(col.New.Name, col.New.Quoted)}
+	step := MigrationStep{}
+	for _, change := range col.Changes {
+		switch change.Property {
+		case ChangeType, ChangeLength, ChangePrecision:
+			clause = append(clause, change.New)
+			if change.Property == ChangeType || isNarrowing(change.Old, change.New) {
+				step.Destructive = true
+				step.Note = fmt.Sprintf("changes %v.%v from %v to %v, values may not fit", table.Table, col.New.Name, change.Old, change.New)
+			}
+		case ChangeDefault:
+			if change.New == "" {
+				clause = append(clause, "DEFAULT NULL")
+			} else {
+				clause = append(clause, "DEFAULT "+change.New)
+			}
+		case ChangeNullable:
+			if col.Old.Attribute.IsPrimaryKey() || col.New.Attribute.IsPrimaryKey() {
+				continue
+			}
+			clause = append(clause, change.New)
+		}
+	}
+	if len(clause) == 1 {
+		return step, false
+	}
+	step.SQL = fmt.Sprintf("ALTER TABLE %v MODIFY (%v)", w.tableName(table), strings.Join(clause, " "))
+	return step, true
+}
+
+var sizeNumberPattern = regexp.MustCompile(`\d+`)
+
+// isNarrowing reports whether a size such as the length or the precision and scale is smaller in the new type.
+func isNarrowing(old, new string) bool {
+	before := sizeNumberPattern.FindAllString(sizePattern.FindString(old), -1)
+	after := sizeNumberPattern.FindAllString(sizePattern.FindString(new), -1)
+	if len(after) == 0 {
+		return false
+	}
+	if len(before) == 0 {
+		return true
+	}
+	for i := 0; i < len(before) && i < len(after); i++ {
+		x, _ := strconv.Atoi(before[i])
+		y, _ := strconv.Atoi(after[i])
+		if y < x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package ddlcode
+
+import (
+	"testing"
+)
+
+// parseSchema parses a script which is expected to parse without diagnostics.
+func parseSchema(t *testing.T, sql string) Database {
+	t.Helper()
+	db, diags, err := ParseWithOptions(sql, ParseOptions{})
+	if err != nil || len(diags) > 0 {
+		t.Fatalf("parse: %v %v", err, diags)
+	}
+	return db
+}
+
+func TestGenerateMigration(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		want     string
+		rollback string
+	}{
+		{
+			name: "add columns and tables",
+			old:  `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(50));`,
+			new: `CREATE TABLE t (id NUMBER(10) PRIMARY KEY, name VARCHAR2(100) NOT NULL, created DATE DEFAULT SYSDATE);
+CREATE TABLE u (id NUMBER(10) PRIMARY KEY, t_id NUMBER(10), CONSTRAINT fk_u_t FOREIGN KEY (t_id) REFERENCES t (id));
+CREATE INDEX ix_u_t ON u (t_id);`,
+			want: `ALTER TABLE T ADD (CREATED DATE DEFAULT SYSDATE);
+ALTER TABLE T MODIFY (NAME VARCHAR2(100) NOT NULL);
+CREATE TABLE U (
+  ID NUMBER(10),
+  T_ID NUMBER(10),
+  PRIMARY KEY (ID),
+  CONSTRAINT FK_U_T FOREIGN KEY (T_ID) REFERENCES T (ID)
+);
+CREATE INDEX IX_U_T ON U (T_ID);
+`,
+			rollback: `-- DESTRUCTIVE: changes T.NAME from VARCHAR2(100) to VARCHAR2(50), values may not fit
+ALTER TABLE T MODIFY (NAME VARCHAR2(50) NULL);
+-- DESTRUCTIVE: drops column T.CREATED and its data
+ALTER TABLE T DROP COLUMN CREATED;
+-- DESTRUCTIVE: drops table U and its data
+DROP TABLE U;
+`,
+		},
+		{
+			name: "drop columns and tables",
+			old: `CREATE TABLE a (id NUMBER PRIMARY KEY, old_col NUMBER);
+CREATE TABLE gone (code VARCHAR2(5));`,
+			new: `CREATE TABLE a (id NUMBER PRIMARY KEY);`,
+			want: `-- DESTRUCTIVE: drops column A.OLD_COL and its data
+ALTER TABLE A DROP COLUMN OLD_COL;
+-- DESTRUCTIVE: drops table GONE and its data
+DROP TABLE GONE;
+`,
+			rollback: `ALTER TABLE A ADD (OLD_COL NUMBER);
+CREATE TABLE GONE (
+  CODE VARCHAR2(5)
+);
+`,
+		},
+		{
+			name: "foreign keys on a changed primary key",
+			old: `CREATE TABLE dept (id NUMBER(10), CONSTRAINT pk_dept PRIMARY KEY (id));
+CREATE TABLE emp (id NUMBER(10) PRIMARY KEY, dept_id NUMBER(10),
+  CONSTRAINT fk_emp_dept FOREIGN KEY (dept_id) REFERENCES dept (id));
+CREATE TABLE loc (dept_id NUMBER(10) REFERENCES dept (id));`,
+			new: `CREATE TABLE dept (id NUMBER(10), CONSTRAINT pk_department PRIMARY KEY (id));
+CREATE TABLE emp (id NUMBER(10) PRIMARY KEY, dept_id NUMBER(10),
+  CONSTRAINT fk_emp_dept FOREIGN KEY (dept_id) REFERENCES dept (id));
+CREATE TABLE loc (dept_id NUMBER(10) REFERENCES dept (id));`,
+			want: `ALTER TABLE EMP DROP CONSTRAINT FK_EMP_DEPT;
+ALTER TABLE DEPT DROP PRIMARY KEY CASCADE;
+ALTER TABLE DEPT ADD CONSTRAINT PK_DEPARTMENT PRIMARY KEY (ID);
+ALTER TABLE EMP ADD CONSTRAINT FK_EMP_DEPT FOREIGN KEY (DEPT_ID) REFERENCES DEPT (ID);
+ALTER TABLE LOC ADD FOREIGN KEY (DEPT_ID) REFERENCES DEPT (ID);
+`,
+			rollback: `ALTER TABLE EMP DROP CONSTRAINT FK_EMP_DEPT;
+ALTER TABLE DEPT DROP PRIMARY KEY CASCADE;
+ALTER TABLE DEPT ADD CONSTRAINT PK_DEPT PRIMARY KEY (ID);
+ALTER TABLE EMP ADD CONSTRAINT FK_EMP_DEPT FOREIGN KEY (DEPT_ID) REFERENCES DEPT (ID);
+ALTER TABLE LOC ADD FOREIGN KEY (DEPT_ID) REFERENCES DEPT (ID);
+`,
+		},
+		{
+			name:     "unchanged",
+			old:      `CREATE TABLE t (id NUMBER PRIMARY KEY);`,
+			new:      `CREATE TABLE t (id NUMBER PRIMARY KEY);`,
+			want:     "",
+			rollback: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultMigrationConfig()
+			config.Diff = Diff(parseSchema(t, tt.old), parseSchema(t, tt.new))
+			files, err := GenerateMigration(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := files["migration.sql"]; got != tt.want {
+				t.Errorf("migration got\n%v\nwant\n%v", got, tt.want)
+			}
+			if got := files["migration_rollback.sql"]; got != tt.rollback {
+				t.Errorf("rollback got\n%v\nwant\n%v", got, tt.rollback)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package ddlcode
 
 import (
+	"fmt"
+
 	"github.com/codeindex2937/oracle-sql-parser/ast"
 	"github.com/codeindex2937/oracle-sql-parser/ast/element"
 	"golang.org/x/exp/slices"
@@ -10,32 +12,281 @@ type AttributeMap map[ast.ConstraintType]*ast.ColumnDefault
 type NullStyle int
 
 type Column struct {
-	CharacterMaximumLength string           `json:"character_maximum_length"`
-	Collation              string           `json:"collation"`
-	Default                string           `json:"default"`
-	Name                   string           `json:"name"`
-	Nullable               string           `json:"nullable"`
-	OrdinalPosition        int              `json:"ordinal_position"`
-	Precision              string           `json:"precision"`
-	Schema                 string           `json:"schema"`
-	Table                  string           `json:"table"`
-	Type                   string           `json:"type"`
-	DataType               element.Datatype `json:"-"`
-	Attribute              AttributeMap     `json:"-"`
-	ForeignColumn          *Column          `json:"-"`
-	ForeignTable           *Table           `json:"-"`
-	Comment                string           `json:"comment"`
+	CharacterMaximumLength string `json:"character_maximum_length"`
+	Collation              string `json:"collation"`
+	// Default is DefaultValue printed as Oracle SQL
+	Default         string           `json:"default"`
+	Name            string           `json:"name"`
+	Nullable        string           `json:"nullable"`
+	OrdinalPosition int              `json:"ordinal_position"`
+	Precision       string           `json:"precision"`
+	Schema          string           `json:"schema"`
+	Table           string           `json:"table"`
+	Type            string           `json:"type"`
+	DataType        element.Datatype `json:"-"`
+	Attribute       AttributeMap     `json:"-"`
+	// Deprecated: use Table.ForeignKeys, only the first foreign key of the column is kept here.
+	ForeignColumn *Column `json:"-"`
+	// Deprecated: use Table.ForeignKeys, only the first foreign key of the column is kept here.
+	ForeignTable *Table    `json:"-"`
+	Comment      string    `json:"comment"`
+	Identity     *Identity `json:"identity,omitempty"`
+	Sequence     *Sequence `json:"-"`
+	// Checks are the check constraints of the table referring to the column
+	Checks       []*CheckConstraint `json:"-"`
+	DefaultValue *DefaultValue      `json:"-"`
+	Span         SourceSpan         `json:"-"`
+	// Quoted is set when the name is written in double quotes, it is then case sensitive
+	Quoted bool `json:"-"`
+	// Triggers are the triggers assigning the column through :NEW
+	Triggers []*Trigger `json:"-"`
+	// Source is the base column a view column selects, nil for expressions and for table columns
+	Source *Column `json:"-"`
 }
 
 type Table struct {
-	Collation string    `json:"collation"`
-	Engine    string    `json:"engine"`
-	Rows      int       `json:"rows"`
-	Schema    string    `json:"schema"`
-	Table     string    `json:"table"`
-	Type      string    `json:"type"`
-	Columns   []*Column `json:"-"`
-	Comment   string    `json:"comment"`
+	Collation    string        `json:"collation"`
+	Engine       string        `json:"engine"`
+	Rows         int           `json:"rows"`
+	Schema       string        `json:"schema"`
+	Table        string        `json:"table"`
+	Type         string        `json:"type"`
+	Columns      []*Column     `json:"-"`
+	Comment      string        `json:"comment"`
+	ForeignKeys  []*ForeignKey `json:"-"`
+	ReferencedBy []*ForeignKey `json:"-"`
+	Indexes      []*Index      `json:"-"`
+	// UniqueConstraints holds inline and out-of-line UNIQUE constraints
+	UniqueConstraints []*UniqueConstraint `json:"-"`
+	CheckConstraints  []*CheckConstraint  `json:"-"`
+	// PrimaryKey are the primary key columns in the order of the constraint
+	PrimaryKey     []*Column `json:"-"`
+	PrimaryKeyName string    `json:"-"`
+	// Span locates the CREATE statement
+	Span SourceSpan `json:"-"`
+	// Quoted is set when the name is written in double quotes, it is then case sensitive
+	Quoted bool `json:"-"`
+	// Tablespace is the TABLESPACE of the table, partitions may name their own
+	Tablespace string `json:"tablespace,omitempty"`
+	// Storage holds the physical attributes such as PCTFREE, COMPRESS or the parameters of STORAGE (...)
+	Storage      map[string]string `json:"storage,omitempty"`
+	Partitioning *Partitioning     `json:"partitioning,omitempty"`
+	Triggers     []*Trigger        `json:"-"`
+	// Aliases are the synonyms standing for the table
+	Aliases []*Synonym `json:"-"`
+}
+
+// Trigger is a DML trigger on a table or on a view, its body is kept as written.
+type Trigger struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Timing is BEFORE, AFTER, INSTEAD OF, or FOR for compound triggers
+	Timing string `json:"timing"`
+	// Events are INSERT, UPDATE and DELETE
+	Events     []string `json:"events"`
+	Table      *Table   `json:"-"`
+	ForEachRow bool     `json:"for_each_row"`
+	// When is the condition of the WHEN clause
+	When string `json:"when,omitempty"`
+	Body string `json:"body"`
+	// Columns are the columns the body assigns through :NEW
+	Columns []*Column `json:"-"`
+	// Conditional are the columns of Columns only assigned under a condition, such as IF :NEW.col IS NULL,
+	// the value written by the application may be kept
+	Conditional []*Column  `json:"-"`
+	Span        SourceSpan `json:"-"`
+}
+
+// Synonym is a private or public synonym, references through it resolve to its table.
+type Synonym struct {
+	// Schema is PUBLIC for a public synonym
+	Schema       string `json:"schema"`
+	Name         string `json:"name"`
+	Public       bool   `json:"public"`
+	TargetSchema string `json:"target_schema"`
+	TargetName   string `json:"target_name"`
+	// DBLink is the database link of a synonym for a remote object
+	DBLink string `json:"db_link,omitempty"`
+	// Table is the table or the view the synonym finally stands for, nil when it is not in the schema
+	Table *Table     `json:"-"`
+	Span  SourceSpan `json:"-"`
+}
+
+// Privileges are the object privileges and the roles granted by GRANT statements and not revoked since.
+type Privileges struct {
+	Objects []*ObjectPrivilege `json:"objects"`
+	Roles   []*RoleGrant       `json:"roles"`
+}
+
+// ObjectPrivilege is a privilege such as SELECT or UPDATE on an object granted to a user or a role.
+type ObjectPrivilege struct {
+	Grantee   string `json:"grantee"`
+	Privilege string `json:"privilege"`
+	Schema    string `json:"schema"`
+	Object    string `json:"object"`
+	// Table is the table or the view the privilege is on, nil for other objects
+	Table *Table `json:"-"`
+	// Columns restrict INSERT, UPDATE and REFERENCES to some columns
+	Columns     []string   `json:"columns,omitempty"`
+	GrantOption bool       `json:"grant_option"`
+	Span        SourceSpan `json:"-"`
+}
+
+// RoleGrant is a role granted to a user or to another role.
+type RoleGrant struct {
+	Role    string `json:"role"`
+	Grantee string `json:"grantee"`
+}
+
+// UserType is an object type or a collection type created by CREATE TYPE.
+type UserType struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Kind is OBJECT, VARRAY or TABLE
+	Kind string `json:"kind"`
+	// Attributes are the attributes of an object type without those of its supertype
+	Attributes []*Column `json:"-"`
+	Supertype  *UserType `json:"-"`
+	// Element is the element of a collection type, a column named COLUMN_VALUE
+	Element *Column `json:"-"`
+	// Limit is the size limit of a VARRAY
+	Limit int `json:"limit,omitempty"`
+	// Final is unset for NOT FINAL object types which may have subtypes
+	Final bool       `json:"final"`
+	Span  SourceSpan `json:"-"`
+}
+
+// Package is a PL/SQL package specification with its procedures and functions, the body is not part of the model.
+type Package struct {
+	Schema   string     `json:"schema"`
+	Name     string     `json:"name"`
+	Routines []*Routine `json:"routines"`
+	Span     SourceSpan `json:"-"`
+}
+
+// Routine is a procedure or a function declared by a package specification or created standalone.
+type Routine struct {
+	Schema string `json:"schema"`
+	// Package is the package declaring the routine, empty for a standalone one
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name"`
+	// Kind is PROCEDURE or FUNCTION
+	Kind       string       `json:"kind"`
+	Parameters []*Parameter `json:"parameters"`
+	// Return is the result of a function, nil for a procedure
+	Return *Parameter `json:"return,omitempty"`
+	Span   SourceSpan `json:"-"`
+}
+
+// Parameter is a parameter of a routine or the result of a function, which has no name.
+type Parameter struct {
+	Name string `json:"name"`
+	// Mode is IN, OUT or IN OUT
+	Mode string `json:"mode"`
+	// Type is the type as written, e.g. ORDERS.ID%TYPE
+	Type string `json:"type"`
+	// DataType is the column type the parameter maps as, nil for BOOLEAN, SYS_REFCURSOR, %ROWTYPE
+	// and the types declared by packages
+	DataType element.Datatype `json:"-"`
+	// HasDefault is set when callers may leave the parameter out
+	HasDefault bool `json:"has_default"`
+}
+
+// Partitioning is the PARTITION BY clause of a table or its SUBPARTITION BY clause.
+type Partitioning struct {
+	// Method is RANGE, LIST, HASH, REFERENCE or SYSTEM
+	Method string `json:"method"`
+	// Columns are the partition key
+	Columns []*Column `json:"-"`
+	// Interval is the expression of INTERVAL partitioning
+	Interval string `json:"interval,omitempty"`
+	// Count is the number of partitions given by PARTITIONS n or SUBPARTITIONS n
+	Count int `json:"count,omitempty"`
+	// Partitions are the partitions listed, or the SUBPARTITION TEMPLATE of a subpartitioning
+	Partitions      []*Partition  `json:"partitions,omitempty"`
+	Subpartitioning *Partitioning `json:"subpartitioning,omitempty"`
+}
+
+type Partition struct {
+	Name string `json:"name"`
+	// Values is the bound as written, e.g. "LESS THAN (MAXVALUE)" or "('A', 'B')"
+	Values        string       `json:"values,omitempty"`
+	Tablespace    string       `json:"tablespace,omitempty"`
+	Subpartitions []*Partition `json:"subpartitions,omitempty"`
+}
+
+// SourceSpan locates a definition in the DDL, lines and columns start at 1 and the end is exclusive.
+type SourceSpan struct {
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+}
+
+// View is a view or a materialized view, generators take it as a read-only table by AsTable.
+type View struct {
+	Schema       string    `json:"schema"`
+	Name         string    `json:"name"`
+	Materialized bool      `json:"materialized"`
+	Columns      []*Column `json:"-"`
+	// Query is the defining query as written in the DDL
+	Query string `json:"query"`
+	// BaseTables are the tables and views the query selects from, as far as they are known
+	BaseTables []*Table `json:"-"`
+	// PrimaryKey are the columns of a primary key declared on the view, the columns do not take
+	// the keys of the columns they select
+	PrimaryKey []*Column  `json:"-"`
+	Span       SourceSpan `json:"-"`
+	table      *Table
+}
+
+type ForeignKey struct {
+	Name string
+	// Table holds Columns, RefTable holds RefColumns, both in constraint order
+	Table      *Table
+	Columns    []*Column
+	RefTable   *Table
+	RefColumns []*Column
+	// OnDelete and OnUpdate are the referential actions such as "CASCADE", empty when not given
+	OnDelete string
+	OnUpdate string
+	Span     SourceSpan
+}
+
+type Index struct {
+	Schema  string
+	Name    string
+	Table   *Table
+	Columns []IndexColumn
+	Unique  bool
+	Bitmap  bool
+	Span    SourceSpan
+}
+
+// IndexColumn is either a column or, for function-based indexes, an expression.
+type IndexColumn struct {
+	Column     *Column
+	Expression string
+	// Direction is "ASC", "DESC" or empty
+	Direction string
+}
+
+type UniqueConstraint struct {
+	Name    string
+	Columns []*Column
+	Span    SourceSpan
+}
+
+type CheckConstraint struct {
+	Name string
+	// Expression is the condition as written in the DDL
+	Expression string
+	// Columns are the columns the condition refers to
+	Columns []*Column
+	// Values is the list of "col IN (...)" conditions, nil for any other condition
+	Values []string
+	Span   SourceSpan
 }
 
 type PkInfo struct {
@@ -72,11 +323,19 @@ type Database struct {
 	Columns      []*Column   `json:"columns"`
 	Tables       []*Table    `json:"tables"`
 	Version      string      `json:"version"`
-	Views        []any       `json:"views"`
+	Views        []*View     `json:"views"`
 	DatabaseName string      `json:"database_name"`
 	PkInfo       []PkInfo    `json:"pk_info"`
 	FkInfo       []FkInfo    `json:"fk_info"`
 	Indexes      []IndexInfo `json:"indexes"`
+	Sequences    []*Sequence `json:"sequences"`
+	Triggers     []*Trigger  `json:"triggers"`
+	Synonyms     []*Synonym  `json:"synonyms"`
+	UserTypes    []*UserType `json:"user_types"`
+	Privileges   Privileges  `json:"privileges"`
+	Packages     []*Package  `json:"packages"`
+	// Routines are the standalone procedures and functions
+	Routines []*Routine `json:"routines"`
 }
 
 func (t Table) getColumn(name string) *Column {
@@ -87,6 +346,228 @@ func (t Table) getColumn(name string) *Column {
 	return t.Columns[index]
 }
 
+// QualifiedName identifies the table by schema and name, it is "SCHEMA.NAME" or "NAME" without a schema.
+func (t Table) QualifiedName() string {
+	return qualifiedName(t.Schema, t.Table)
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+// IsView reports whether the table stands for a view, such tables are read-only.
+func (t Table) IsView() bool {
+	return t.Type == "view" || t.Type == "materialized view"
+}
+
+// AsTable returns the view as a table of type "view" or "materialized view" sharing the columns of the view.
+func (v *View) AsTable() *Table {
+	if v.table != nil {
+		return v.table
+	}
+	v.table = &Table{
+		Schema:     v.Schema,
+		Table:      v.Name,
+		Columns:    v.Columns,
+		PrimaryKey: v.PrimaryKey,
+		Rows:       -1,
+		Type:       "view",
+		Span:       v.Span,
+	}
+	if v.Materialized {
+		v.table.Type = "materialized view"
+	}
+	return v.table
+}
+
+// String returns "file:line:column", or "line:column" without a file name.
+func (s SourceSpan) String() string {
+	if s.File == "" {
+		return fmt.Sprintf("%v:%v", s.Line, s.Column)
+	}
+	return fmt.Sprintf("%v:%v:%v", s.File, s.Line, s.Column)
+}
+
+// IsSetByTrigger reports whether a row trigger firing before the event always assigns the column, overriding
+// the value written. Assignments under a condition or in a trigger with a WHEN clause work as a default.
+func (c *Column) IsSetByTrigger(event string) bool {
+	return slices.ContainsFunc(c.Triggers, func(t *Trigger) bool {
+		return (t.Timing == "BEFORE" || t.Timing == "FOR") && slices.Contains(t.Events, event) && t.When == "" &&
+			!slices.Contains(t.Conditional, c)
+	})
+}
+
+// IsPartitionKey reports whether the column is part of the partition key of the table.
+func (t *Table) IsPartitionKey(col *Column) bool {
+	return t.Partitioning != nil && slices.Contains(t.Partitioning.Columns, col)
+}
+
+// IsSubpartitionKey reports whether the column is part of the subpartition key of the table.
+func (t *Table) IsSubpartitionKey(col *Column) bool {
+	return t.Partitioning != nil && t.Partitioning.Subpartitioning != nil && slices.Contains(t.Partitioning.Subpartitioning.Columns, col)
+}
+
+// String describes the partitioning as "RANGE (COL) INTERVAL (...) SUBPARTITION BY HASH (COL)".
+func (p *Partitioning) String() string {
+	text := p.Method
+	if len(p.Columns) > 0 {
+		text += fmt.Sprintf(" (%v)", joinStr(mapping(p.Columns, getName)))
+	}
+	if p.Interval != "" {
+		text += fmt.Sprintf(" INTERVAL (%v)", p.Interval)
+	}
+	if p.Subpartitioning != nil {
+		text += " SUBPARTITION BY " + p.Subpartitioning.String()
+	}
+	return text
+}
+
+func (t *Table) dropPrimaryKey() {
+	for _, col := range t.Columns {
+		delete(col.Attribute, ast.ConstraintTypePK)
+	}
+	t.PrimaryKey = nil
+	t.PrimaryKeyName = ""
+}
+
+// renameConstraint renames the primary key, a foreign key, a unique or a check constraint.
+func (t *Table) renameConstraint(name, newName string) bool {
+	if t.PrimaryKeyName == name {
+		t.PrimaryKeyName = newName
+		return true
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			fk.Name = newName
+			return true
+		}
+	}
+	for _, uc := range t.UniqueConstraints {
+		if uc.Name == name {
+			uc.Name = newName
+			return true
+		}
+	}
+	for _, check := range t.CheckConstraints {
+		if check.Name == name {
+			check.Name = newName
+			return true
+		}
+	}
+	return false
+}
+
+// GetForeignKeys returns the foreign keys the column takes part in.
+func (t Table) GetForeignKeys(col *Column) []*ForeignKey {
+	fks := []*ForeignKey{}
+	for _, fk := range t.ForeignKeys {
+		if slices.Contains(fk.Columns, col) {
+			fks = append(fks, fk)
+		}
+	}
+	return fks
+}
+
+// RelationName names the association of the foreign key in generated code, it is the referenced table
+// unless the table references it, or a same-named table of another schema, more than once or a column
+// already has that name.
+func (fk ForeignKey) RelationName() string {
+	name := fk.RefTable.Table
+	count := 0
+	for _, other := range fk.Table.ForeignKeys {
+		if other.RefTable.Table == fk.RefTable.Table {
+			count += 1
+		}
+	}
+	if count > 1 {
+		if fk.Name != "" {
+			name = fk.Name
+		} else {
+			name = joinStr(mapping(fk.Columns, getName))
+		}
+	}
+	if fk.Table.getColumn(name) != nil {
+		name += "_REF"
+	}
+	return name
+}
+
+// IsUnique reports whether the column alone is unique, by a unique constraint or a unique index.
+func (t Table) IsUnique(col *Column) bool {
+	for _, uc := range t.UniqueConstraints {
+		if len(uc.Columns) == 1 && uc.Columns[0] == col {
+			return true
+		}
+	}
+	for _, index := range t.Indexes {
+		if index.Unique && len(index.Columns) == 1 && index.Columns[0].Column == col {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPartOfUnique reports whether the column takes part in a composite unique constraint or unique index.
+func (t Table) IsPartOfUnique(col *Column) bool {
+	for _, uc := range t.UniqueConstraints {
+		if len(uc.Columns) > 1 && slices.Contains(uc.Columns, col) {
+			return true
+		}
+	}
+	for _, index := range t.Indexes {
+		if !index.Unique || len(index.Columns) < 2 {
+			continue
+		}
+		for _, ic := range index.Columns {
+			if ic.Column == col {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetIndexes returns the indexes on the column and the position of the column within each of them.
+func (t Table) GetIndexes(col *Column) ([]*Index, []int) {
+	indexes := []*Index{}
+	positions := []int{}
+	for _, index := range t.Indexes {
+		for i, ic := range index.Columns {
+			if ic.Column == col {
+				indexes = append(indexes, index)
+				positions = append(positions, i)
+			}
+		}
+	}
+	return indexes, positions
+}
+
+func (c *Column) setDefault(value *DefaultValue) {
+	c.DefaultValue = value
+	c.Default = ""
+	if value != nil {
+		c.Default = value.OracleSQL()
+	}
+}
+
+// AllowedValues returns the values of a "col IN (...)" check on the column alone, or nil.
+func (c Column) AllowedValues() []string {
+	for _, check := range c.Checks {
+		if len(check.Columns) == 1 && check.Values != nil {
+			return check.Values
+		}
+	}
+	return nil
+}
+
+// IsNullable reports whether the column takes NULL, that is unless it is NOT NULL or part of the primary key.
+func (c Column) IsNullable() bool {
+	return !c.Attribute.IsNotNull() && !c.Attribute.IsPrimaryKey()
+}
+
 func (attr AttributeMap) IsPrimaryKey() bool {
 	if _, ok := attr[ast.ConstraintTypePK]; ok {
 		return true
@@ -109,7 +590,9 @@ func (attr AttributeMap) IsAllowNull() bool {
 }
 
 func (attr AttributeMap) IsAutoIncrement() bool {
-	// FIXME
+	if _, ok := attr[ConstraintTypeAutoIncrement]; ok {
+		return true
+	}
 	return false
 }
 
@@ -0,0 +1,186 @@
+package ddlcode
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+type MySQLConfig struct {
+	ExportDir string
+	FileName  string
+	// Indent indents the columns and the constraints in CREATE TABLE
+	Indent string
+	// Types overrides the type mapping, see PostgresConfig.Types
+	Types map[string]string
+	// Engine is the storage engine of the tables, left out when empty
+	Engine string
+	// Version is the MySQL version checked for conformance, such as "5.7", the latest when empty
+	Version string
+}
+
+// mysqlIndexPrefix is the length of the prefix MySQL indexes of a TEXT or BLOB column, 767 bytes in utf8mb4.
+const mysqlIndexPrefix = 191
+
+// mysqlTypes maps the Oracle types taking no size to MySQL, the sized ones are mapped by toMySQLType.
+var mysqlTypes = map[element.DataDef]string{
+	element.DataDefInteger:         "int",
+	element.DataDefInt:             "int",
+	element.DataDefSmallInt:        "smallint",
+	element.DataDefReal:            "double",
+	element.DataDefBinaryFloat:     "float",
+	element.DataDefBinaryDouble:    "double",
+	element.DataDefDoublePrecision: "double",
+	element.DataDefDate:            "datetime",
+	element.DataDefLong:            "longtext",
+	element.DataDefClob:            "longtext",
+	element.DataDefNClob:           "longtext",
+	element.DataDefXMLType:         "longtext",
+	element.DataDefBlob:            "longblob",
+	element.DataDefLongRaw:         "longblob",
+}
+
+// mysqlReservedWords are the MySQL reserved words, they are quoted when used as names.
+var mysqlReservedWords = []string{
+	"accessible", "add", "all", "alter", "analyze", "and", "as", "asc", "before", "between", "bigint", "binary",
+	"blob", "both", "by", "call", "cascade", "case", "change", "char", "character", "check", "collate", "column",
+	"condition", "constraint", "continue", "convert", "create", "cross", "cube", "current_date", "current_time",
+	"current_timestamp", "current_user", "cursor", "database", "databases", "dec", "decimal", "declare", "default",
+	"delayed", "delete", "desc", "describe", "distinct", "div", "double", "drop", "dual", "each", "else", "elseif",
+	"enclosed", "escaped", "except", "exists", "exit", "explain", "false", "fetch", "float", "for", "force",
+	"foreign", "from", "fulltext", "function", "generated", "get", "grant", "group", "grouping", "groups", "having",
+	"if", "ignore", "in", "index", "infile", "inner", "insert", "int", "integer", "intersect", "interval", "into",
+	"is", "iterate", "join", "key", "keys", "kill", "lag", "lead", "leading", "leave", "left", "like", "limit",
+	"lines", "load", "localtime", "localtimestamp", "lock", "long", "loop", "match", "mod", "natural", "not", "null",
+	"numeric", "of", "on", "optimize", "option", "or", "order", "out", "outer", "over", "partition", "precision",
+	"primary", "procedure", "range", "rank", "read", "real", "recursive", "references", "regexp", "release",
+	"rename", "repeat", "replace", "require", "restrict", "return", "revoke", "right", "rlike", "row", "rows",
+	"schema", "schemas", "select", "separator", "set", "show", "smallint", "spatial", "sql", "starting", "stored",
+	"system", "table", "terminated", "then", "to", "trailing", "trigger", "true", "undo", "union", "unique",
+	"unlock", "unsigned", "update", "usage", "use", "using", "values", "varchar", "varying", "virtual", "when",
+	"where", "while", "window", "with", "write", "xor", "zerofill",
+}
+
+func GetDefaultMySQLConfig() MySQLConfig {
+	return MySQLConfig{
+		ExportDir: ".",
+		FileName:  "mysql.sql",
+		Indent:    "  ",
+		Types:     map[string]string{},
+		Engine:    "InnoDB",
+	}
+}
+
+// GenerateMySQL translates the schema to MySQL DDL: the schemas, the tables referenced tables first, the foreign
+// keys closing a cycle, the indexes and the views. Identity columns and primary keys filled from a sequence
+// become AUTO_INCREMENT, comments are kept with their table and column. The diagnostics are those of
+// CheckConformance for the version, the types which cannot be mapped and the expressions kept as written which
+// call other functions than the portable ones or use Oracle keywords.
+func GenerateMySQL(db Database, config MySQLConfig) (map[string]string, []Diagnostic, error) {
+	if config.FileName == "" {
+		return nil, nil, fmt.Errorf("file name is empty")
+	}
+	w := &dialectWriter{dialect: DialectMySQL, indent: config.Indent, types: config.Types, engine: config.Engine, version: config.Version}
+	content := w.script(db)
+	diags := append(CheckConformance(db, DialectMySQL, config.Version), w.diags...)
+	return map[string]string{filepath.Join(config.ExportDir, config.FileName): content}, diags, nil
+}
+
+// toMySQLType maps an Oracle type to MySQL, NUMBER becomes int, bigint or decimal by its precision and scale.
+// It reports false for the types MySQL has no equivalent of.
+func toMySQLType(datatype element.Datatype) (string, bool) {
+	if name, ok := mysqlTypes[datatype.DataDef()]; ok {
+		return name, true
+	}
+	switch datatype.DataDef() {
+	case element.DataDefNumber, element.DataDefDecimal, element.DataDefDec, element.DataDefNumeric:
+		realType := datatype.(*element.Number)
+		switch {
+		case realType.Precision == nil, realType.Precision.IsAsterisk && realType.Scale == nil:
+			// NUMBER takes any scale, decimal takes up to 30 digits
+			return "decimal(65, 30)", true
+		case realType.Precision.IsAsterisk:
+			return fmt.Sprintf("decimal(38, %v)", *realType.Scale), true
+		case realType.Scale != nil && *realType.Scale > 0:
+			return fmt.Sprintf("decimal(%v, %v)", realType.Precision.Number, *realType.Scale), true
+		case realType.Precision.Number <= 9:
+			return "int", true
+		case realType.Precision.Number <= 18:
+			return "bigint", true
+		}
+		return fmt.Sprintf("decimal(%v)", realType.Precision.Number), true
+	case element.DataDefFloat:
+		realType := datatype.(*element.Float)
+		if realType.Precision != nil && !realType.Precision.IsAsterisk && realType.Precision.Number <= 24 {
+			return "float", true
+		}
+		return "double", true
+	case element.DataDefChar, element.DataDefCharacter:
+		return mysqlCharType(datatype.(*element.Char).Size), true
+	case element.DataDefNChar, element.DataDefNationalCharacter, element.DataDefNationalChar:
+		return mysqlCharType(datatype.(*element.NChar).Size), true
+	case element.DataDefVarchar2, element.DataDefVarchar, element.DataDefCharacterVarying, element.DataDefCharVarying:
+		return mysqlVarcharType(datatype.(*element.Varchar2).Size), true
+	case element.DataDefNVarChar2, element.DataDefNCharVarying, element.DataDefNationalCharacterVarying, element.DataDefNationalCharVarying:
+		return mysqlVarcharType(datatype.(*element.NVarchar2).Size), true
+	case element.DataDefRaw:
+		if size := datatype.(*element.Raw).Size; size != nil {
+			return fmt.Sprintf("varbinary(%v)", *size), true
+		}
+		return "varbinary(2000)", true
+	case element.DataDefTimestamp:
+		return fmt.Sprintf("datetime(%v)", mysqlFractionalSeconds(datatype)), true
+	}
+	return "", false
+}
+
+// isMySQLIntegerType reports whether the type, as mapped or given by MySQLConfig.Types, is an integer type
+// such as "int" or "bigint unsigned".
+func isMySQLIntegerType(name string) bool {
+	name, _, _ = strings.Cut(strings.ToLower(name), " ")
+	name, _, _ = strings.Cut(name, "(")
+	return slices.Contains([]string{"tinyint", "smallint", "mediumint", "int", "integer", "bigint"}, name)
+}
+
+// isMySQLTextType reports whether the type is a TEXT or a BLOB type, which MySQL only indexes by a prefix
+// and only gives expression defaults.
+func isMySQLTextType(name string) bool {
+	name, _, _ = strings.Cut(strings.ToLower(name), " ")
+	name, _, _ = strings.Cut(name, "(")
+	return strings.HasSuffix(name, "text") || strings.HasSuffix(name, "blob")
+}
+
+// mysqlCharType maps CHAR, MySQL takes up to 255 characters in char.
+func mysqlCharType(size *int) string {
+	switch {
+	case size == nil:
+		return "char(1)"
+	case *size > 255:
+		return fmt.Sprintf("varchar(%v)", *size)
+	}
+	return fmt.Sprintf("char(%v)", *size)
+}
+
+// mysqlVarcharType maps VARCHAR2, MySQL requires a length.
+func mysqlVarcharType(size *int) string {
+	if size == nil {
+		return "varchar(4000)"
+	}
+	return fmt.Sprintf("varchar(%v)", *size)
+}
+
+// mysqlFractionalSeconds is the precision of the datetime a DATE or a TIMESTAMP maps to, MySQL keeps up to
+// 6 digits where a TIMESTAMP has 6 by default.
+func mysqlFractionalSeconds(datatype element.Datatype) int {
+	realType, ok := datatype.(*element.Timestamp)
+	switch {
+	case !ok:
+		return 0
+	case realType.FractionalSecondsPrecision == nil:
+		return 6
+	}
+	return min(*realType.FractionalSecondsPrecision, 6)
+}
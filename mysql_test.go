@@ -0,0 +1,97 @@
+package ddlcode
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestGenerateMySQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		version string
+		// want are parts of the output
+		want  []string
+		diags []string
+	}{
+		{
+			name: "numbered keys",
+			sql: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL PRIMARY KEY);
+CREATE TABLE b (id NUMBER(10) GENERATED AS IDENTITY (START WITH 5) PRIMARY KEY);
+CREATE TABLE c (id NUMBER(5) GENERATED AS IDENTITY, code VARCHAR2(5) PRIMARY KEY, CONSTRAINT uq_c UNIQUE (id));`,
+			want: []string{
+				"CREATE TABLE a (\n  id bigint AUTO_INCREMENT,\n  PRIMARY KEY (id)\n)",
+				"CREATE TABLE b (\n  id bigint AUTO_INCREMENT,\n  PRIMARY KEY (id)\n) ENGINE=InnoDB AUTO_INCREMENT=5;",
+				"  id int AUTO_INCREMENT,\n  code varchar(5),",
+			},
+			diags: []string{
+				"sequence S left out, MySQL has no sequences",
+				"type decimal(65, 30) of A.ID mapped to bigint, MySQL only numbers integer columns",
+			},
+		},
+		{
+			name: "TEXT and BLOB columns",
+			sql: `CREATE TABLE a (id NUMBER(10) PRIMARY KEY, doc CLOB DEFAULT 'none', img BLOB, x XMLTYPE, CONSTRAINT uq_a UNIQUE (x));
+CREATE INDEX ix_a ON a (id, doc DESC);`,
+			want: []string{
+				"  doc longtext DEFAULT ('none'),\n  img longblob,",
+				"CONSTRAINT uq_a UNIQUE (x(191))",
+				"CREATE INDEX ix_a ON a (id, doc(191) DESC);",
+			},
+			diags: []string{
+				"unique constraint of A indexes a prefix of 191 characters or bytes of A.X, MySQL cannot index whole TEXT and BLOB columns",
+				"index IX_A indexes a prefix of 191 characters or bytes of A.DOC, MySQL cannot index whole TEXT and BLOB columns",
+			},
+		},
+		{
+			name:    "TEXT default before 8.0.13",
+			sql:     `CREATE TABLE a (id NUMBER(10), doc CLOB DEFAULT 'none' NOT NULL, CONSTRAINT pk_a PRIMARY KEY (id));`,
+			version: "8.0.12",
+			want:    []string{"  doc longtext DEFAULT ('none') NOT NULL,"},
+			diags:   []string{"default of A.DOC needs MySQL 8.0.13, TEXT and BLOB columns only take expression defaults"},
+		},
+		{
+			name: "expressions",
+			sql: `CREATE TABLE a (id NUMBER(10) PRIMARY KEY, name VARCHAR2(20), CHECK (LENGTH(name) > 1));
+CREATE VIEW v AS SELECT id, name || '!' AS shout, UPPER(name) AS upper_name FROM a;`,
+			want: []string{"CHECK (LENGTH(name) > 1)", "SELECT id, name || '!' AS shout"},
+			diags: []string{
+				"check constraint of A uses LENGTH, kept as written",
+				"view V uses ||, kept as written",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultMySQLConfig()
+			config.Version = tt.version
+			files, diags, err := GenerateMySQL(parseSchema(t, tt.sql), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := files[config.FileName]
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("missing %q in\n%v", want, content)
+				}
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.diags)
+			}
+		})
+	}
+}
+
+func TestGenerateMySQLGolden(t *testing.T) {
+	config := GetDefaultMySQLConfig()
+	files, diags, err := GenerateMySQL(parseTestSchema(t), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "mysql.sql", files[config.FileName])
+	checkGolden(t, "mysql.diagnostics", diagnosticLines(diags))
+}
@@ -0,0 +1,419 @@
+package ddlcode
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/codeindex2937/ddlcode/toposort"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type IdentifierQuoting int
+
+const (
+	// QuoteAsNeeded quotes the names which are not valid unquoted identifiers or are reserved words
+	QuoteAsNeeded IdentifierQuoting = iota
+	// QuoteAsWritten also quotes the tables and the columns written in double quotes
+	QuoteAsWritten
+	// QuoteAlways quotes every name
+	QuoteAlways
+)
+
+type OracleDDLConfig struct {
+	ExportDir string
+	FileName  string
+	Quoting   IdentifierQuoting
+	// Indent indents the columns and the constraints in CREATE TABLE
+	Indent string
+	// ForeignKeysAfterTables adds every foreign key by ALTER TABLE once the tables are created,
+	// otherwise only the foreign keys closing a cycle are
+	ForeignKeysAfterTables bool
+	// SkipPhysicalAttributes leaves out the organization, the tablespace, the storage and the partitioning of the tables
+	SkipPhysicalAttributes bool
+}
+
+func GetDefaultOracleDDLConfig() OracleDDLConfig {
+	return OracleDDLConfig{
+		ExportDir: ".",
+		FileName:  "schema.sql",
+		Quoting:   QuoteAsNeeded,
+		Indent:    "  ",
+	}
+}
+
+// GenerateOracleDDL prints the schema back as Oracle DDL: the sequences, the tables referenced tables first,
+// the foreign keys closing a cycle, the indexes, the comments and the views, views they select from first.
+// Parsing a script and generating it again normalises it.
+func GenerateOracleDDL(db Database, config OracleDDLConfig) (map[string]string, error) {
+	if config.FileName == "" {
+		return nil, fmt.Errorf("file name is empty")
+	}
+	w := oracleWriter{config}
+	sections := [][]string{}
+
+	sequences := []string{}
+	for _, seq := range db.Sequences {
+		sequences = append(sequences, w.createSequence(seq))
+	}
+	sections = append(sections, sequences)
+
+	// sorting by name first keeps the foreign keys closing a cycle the same whatever the declaration order
+	tables := sortByForeignKeys(sortTables(slices.Clone(db.Tables), TableOrderAlphabetical))
+	inline, deferred := foreignKeysAtCreation(tables, config.ForeignKeysAfterTables)
+	created, added := []string{}, []string{}
+	for _, table := range tables {
+		created = append(created, w.createTable(table, inline[table]))
+	}
+	for _, fk := range deferred {
+		added = append(added, fmt.Sprintf("ALTER TABLE %v ADD %v", w.tableName(fk.Table), w.foreignKey(fk)))
+	}
+	sections = append(sections, created, added)
+
+	indexes, comments := []string{}, []string{}
+	for _, table := range tables {
+		for _, index := range table.Indexes {
+			indexes = append(indexes, w.createIndex(index))
+		}
+		columns := slices.DeleteFunc(slices.Clone(table.Columns), func(c *Column) bool { return c.Comment == "" })
+		comments = append(comments, w.comments(table, table.Comment != "", columns)...)
+	}
+	sections = append(sections, indexes, comments)
+
+	views := []string{}
+	for _, view := range sortViews(db.Views) {
+		views = append(views, w.createView(view))
+	}
+	sections = append(sections, views)
+
+	content := strings.Builder{}
+	for _, statements := range sections {
+		if len(statements) == 0 {
+			continue
+		}
+		if content.Len() > 0 {
+			content.WriteString("\n")
+		}
+		for _, statement := range statements {
+			fmt.Fprintf(&content, "%v;\n", statement)
+		}
+	}
+	return map[string]string{filepath.Join(config.ExportDir, config.FileName): content.String()}, nil
+}
+
+// foreignKeysAtCreation splits the foreign keys of the tables, given in creation order, into those declared with
+// their table and those added once the tables exist, which reference a table created later. afterTables adds
+// them all afterwards.
+func foreignKeysAtCreation(tables []*Table, afterTables bool) (map[*Table][]*ForeignKey, []*ForeignKey) {
+	inline, deferred := map[*Table][]*ForeignKey{}, []*ForeignKey{}
+	for i, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			if afterTables || slices.Index(tables, fk.RefTable) > i {
+				deferred = append(deferred, fk)
+			} else {
+				inline[table] = append(inline[table], fk)
+			}
+		}
+	}
+	return inline, deferred
+}
+
+// sortViews lists the views selecting from other views after them, views in a cycle keep their order.
+func sortViews(views []*View) []*View {
+	viewMap := map[string]*View{}
+	g := toposort.NewGraph[string]()
+	for _, view := range views {
+		viewMap[qualifiedName(view.Schema, view.Name)] = view
+		g.AddNode(qualifiedName(view.Schema, view.Name))
+	}
+	for _, view := range views {
+		to := qualifiedName(view.Schema, view.Name)
+		for _, base := range view.BaseTables {
+			from := base.QualifiedName()
+			if _, ok := viewMap[from]; !ok || !base.IsView() || from == to || reaches(g, to, from) {
+				continue
+			}
+			g.AddEdge(from, to)
+		}
+	}
+	keys, err := g.Sort()
+	if err != nil {
+		return views
+	}
+	sorted := []*View{}
+	for _, key := range keys {
+		sorted = append(sorted, viewMap[key])
+	}
+	return sorted
+}
+
+// oracleWriter prints the definitions of the model as Oracle DDL statements without the terminating semicolon.
+type oracleWriter struct {
+	config OracleDDLConfig
+}
+
+func (w oracleWriter) identifier(name string, quoted bool) string {
+	if w.config.Quoting == QuoteAlways || w.config.Quoting == QuoteAsWritten && quoted {
+		return `"` + name + `"`
+	}
+	return quoteIdentifier(name)
+}
+
+func (w oracleWriter) objectName(schema, name string) string {
+	if schema == "" {
+		return w.identifier(name, false)
+	}
+	return w.identifier(schema, false) + "." + w.identifier(name, false)
+}
+
+func (w oracleWriter) tableName(table *Table) string {
+	if table.Schema == "" {
+		return w.identifier(table.Table, table.Quoted)
+	}
+	return w.identifier(table.Schema, false) + "." + w.identifier(table.Table, table.Quoted)
+}
+
+func (w oracleWriter) columnNames(columns []*Column) string {
+	return strings.Join(mapping(columns, func(c *Column) string { return w.identifier(c.Name, c.Quoted) }), ", ")
+}
+
+func (w oracleWriter) constraintName(name, text string) string {
+	if name == "" {
+		return text
+	}
+	return "CONSTRAINT " + w.identifier(name, false) + " " + text
+}
+
+// column prints the column as in CREATE TABLE, e.g. "NAME VARCHAR2(10) DEFAULT 'x' NOT NULL",
+// primary key columns are left nullable as the key makes them NOT NULL.
+func (w oracleWriter) column(col *Column) string {
+	text := w.identifier(col.Name, col.Quoted) + " " + toSqlType(col.DataType)
+	if col.Identity != nil {
+		text += fmt.Sprintf(" GENERATED %v AS IDENTITY", col.Identity.Generation)
+		if options := sequenceOptionsText(col.Identity.SequenceOptions); options != "" {
+			text += " (" + options + ")"
+		}
+	} else if col.Default != "" {
+		text += " DEFAULT " + col.Default
+	}
+	if !col.IsNullable() && !col.Attribute.IsPrimaryKey() {
+		text += " NOT NULL"
+	}
+	return text
+}
+
+func (w oracleWriter) primaryKey(table *Table) string {
+	return w.constraintName(table.PrimaryKeyName, fmt.Sprintf("PRIMARY KEY (%v)", w.columnNames(primaryKeyColumns(table))))
+}
+
+func (w oracleWriter) foreignKey(fk *ForeignKey) string {
+	text := fmt.Sprintf("FOREIGN KEY (%v) REFERENCES %v (%v)", w.columnNames(fk.Columns), w.tableName(fk.RefTable), w.columnNames(fk.RefColumns))
+	if fk.OnDelete != "" {
+		text += " ON DELETE " + fk.OnDelete
+	}
+	return w.constraintName(fk.Name, text)
+}
+
+// createTable prints CREATE TABLE with the columns, the primary key, the unique and check constraints,
+// the given foreign keys and, unless skipped, the physical attributes.
+func (w oracleWriter) createTable(table *Table, foreignKeys []*ForeignKey) string {
+	elements := []string{}
+	for _, col := range table.Columns {
+		elements = append(elements, w.column(col))
+	}
+	if len(primaryKeyColumns(table)) > 0 {
+		elements = append(elements, w.primaryKey(table))
+	}
+	for _, uc := range table.UniqueConstraints {
+		elements = append(elements, w.constraintName(uc.Name, fmt.Sprintf("UNIQUE (%v)", w.columnNames(uc.Columns))))
+	}
+	for _, check := range table.CheckConstraints {
+		elements = append(elements, w.constraintName(check.Name, fmt.Sprintf("CHECK (%v)", check.Expression)))
+	}
+	for _, fk := range foreignKeys {
+		elements = append(elements, w.foreignKey(fk))
+	}
+
+	kind := "TABLE"
+	if strings.HasSuffix(table.Type, " temporary table") {
+		kind = strings.ToUpper(table.Type)
+	}
+	indent := w.config.Indent
+	text := fmt.Sprintf("CREATE %v %v (\n%v%v\n)", kind, w.tableName(table), indent, strings.Join(elements, ",\n"+indent))
+	if !w.config.SkipPhysicalAttributes {
+		for _, attribute := range w.physicalAttributes(table) {
+			text += "\n" + attribute
+		}
+	}
+	return text
+}
+
+// physicalAttributes prints the organization, the tablespace, the storage and the partitioning of the table.
+func (w oracleWriter) physicalAttributes(table *Table) []string {
+	attributes := []string{}
+	if table.Engine != "" && table.Engine != "HEAP" {
+		attributes = append(attributes, "ORGANIZATION "+table.Engine)
+	}
+	if table.Tablespace != "" {
+		attributes = append(attributes, "TABLESPACE "+w.identifier(table.Tablespace, false))
+	}
+	storage := []string{}
+	for _, name := range append(slices.Clone(storageKeywords), storageFlags...) {
+		if value, ok := table.Storage[name]; ok {
+			attributes = append(attributes, strings.TrimSpace(name+" "+value))
+		}
+	}
+	names := maps.Keys(table.Storage)
+	slices.Sort(names)
+	for _, name := range names {
+		if !slices.Contains(storageKeywords, name) && !slices.Contains(storageFlags, name) {
+			storage = append(storage, strings.TrimSpace(name+" "+table.Storage[name]))
+		}
+	}
+	if len(storage) > 0 {
+		attributes = append(attributes, fmt.Sprintf("STORAGE (%v)", strings.Join(storage, " ")))
+	}
+	if table.Partitioning != nil {
+		if partitioning, ok := w.partitioning(table, table.Partitioning, "PARTITION"); ok {
+			attributes = append(attributes, partitioning)
+		}
+	}
+	return attributes
+}
+
+// partitioning prints "PARTITION BY method (columns)" and the partitions, keyword is PARTITION or SUBPARTITION.
+// Reference partitioning is printed only when the table has a single named foreign key, the model does not keep
+// the constraint it names.
+func (w oracleWriter) partitioning(table *Table, p *Partitioning, keyword string) (string, bool) {
+	key := w.columnNames(p.Columns)
+	if p.Method == "REFERENCE" {
+		if len(table.ForeignKeys) != 1 || table.ForeignKeys[0].Name == "" {
+			return "", false
+		}
+		key = w.identifier(table.ForeignKeys[0].Name, false)
+	}
+	text := fmt.Sprintf("%v BY %v", keyword, p.Method)
+	if key != "" || p.Method != "SYSTEM" {
+		text += fmt.Sprintf(" (%v)", key)
+	}
+	if p.Interval != "" {
+		text += fmt.Sprintf(" INTERVAL (%v)", p.Interval)
+	}
+	if p.Subpartitioning != nil {
+		sub, _ := w.partitioning(table, p.Subpartitioning, "SUBPARTITION")
+		text += " " + sub
+	}
+	if keyword == "SUBPARTITION" {
+		switch {
+		case len(p.Partitions) > 0:
+			text += " SUBPARTITION TEMPLATE " + w.partitionList(p.Partitions, keyword)
+		case p.Count > 0:
+			text += fmt.Sprintf(" SUBPARTITIONS %v", p.Count)
+		}
+		return text, true
+	}
+	switch {
+	case len(p.Partitions) > 0:
+		text += " " + w.partitionList(p.Partitions, keyword)
+	case p.Count > 0:
+		text += fmt.Sprintf(" PARTITIONS %v", p.Count)
+	}
+	return text, true
+}
+
+func (w oracleWriter) partitionList(partitions []*Partition, keyword string) string {
+	items := []string{}
+	for _, partition := range partitions {
+		item := keyword
+		if partition.Name != "" {
+			item += " " + w.identifier(partition.Name, false)
+		}
+		if values := partition.Values; strings.HasPrefix(strings.ToUpper(values), "LESS THAN") {
+			item += " VALUES LESS THAN" + values[len("LESS THAN"):]
+		} else if values != "" {
+			item += " VALUES " + values
+		}
+		if partition.Tablespace != "" {
+			item += " TABLESPACE " + w.identifier(partition.Tablespace, false)
+		}
+		if len(partition.Subpartitions) > 0 {
+			item += " " + w.partitionList(partition.Subpartitions, "SUBPARTITION")
+		}
+		items = append(items, item)
+	}
+	indent := w.config.Indent
+	return fmt.Sprintf("(\n%v%v\n)", indent, strings.Join(items, ",\n"+indent))
+}
+
+func (w oracleWriter) createIndex(index *Index) string {
+	kind := "INDEX"
+	switch {
+	case index.Unique:
+		kind = "UNIQUE INDEX"
+	case index.Bitmap:
+		kind = "BITMAP INDEX"
+	}
+	columns := []string{}
+	for _, col := range index.Columns {
+		name := col.Expression
+		if col.Column != nil {
+			name = w.identifier(col.Column.Name, col.Column.Quoted)
+		}
+		if col.Direction != "" {
+			name += " " + col.Direction
+		}
+		columns = append(columns, name)
+	}
+	return fmt.Sprintf("CREATE %v %v ON %v (%v)", kind, w.objectName(index.Schema, index.Name), w.tableName(index.Table), strings.Join(columns, ", "))
+}
+
+// comments comments the given columns and the table when commentTable is set, an empty comment removes it.
+func (w oracleWriter) comments(table *Table, commentTable bool, columns []*Column) []string {
+	comments := []string{}
+	if commentTable {
+		comments = append(comments, fmt.Sprintf("COMMENT ON TABLE %v IS %v", w.tableName(table), quoteString(table.Comment)))
+	}
+	for _, col := range columns {
+		comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %v.%v IS %v", w.tableName(table), w.identifier(col.Name, col.Quoted), quoteString(col.Comment)))
+	}
+	return comments
+}
+
+func (w oracleWriter) createSequence(seq *Sequence) string {
+	text := "CREATE SEQUENCE " + w.objectName(seq.Schema, seq.Name)
+	if options := sequenceOptionsText(seq.SequenceOptions); options != "" {
+		text += " " + options
+	}
+	return text
+}
+
+func (w oracleWriter) createView(view *View) string {
+	kind := "VIEW"
+	if view.Materialized {
+		kind = "MATERIALIZED VIEW"
+	}
+	return fmt.Sprintf("CREATE %v %v AS\n%v", kind, w.objectName(view.Schema, view.Name), strings.TrimSpace(view.Query))
+}
+
+// sequenceOptionsText prints the options given, e.g. "START WITH 1 INCREMENT BY 1 CACHE 20".
+func sequenceOptionsText(opts SequenceOptions) string {
+	options := []string{}
+	add := func(name, value string) {
+		if value != "" {
+			options = append(options, name+" "+value)
+		}
+	}
+	add("START WITH", opts.StartWith)
+	add("INCREMENT BY", opts.IncrementBy)
+	add("MINVALUE", opts.MinValue)
+	add("MAXVALUE", opts.MaxValue)
+	add("CACHE", opts.Cache)
+	if opts.Cycle {
+		options = append(options, "CYCLE")
+	}
+	if opts.Order {
+		options = append(options, "ORDER")
+	}
+	return strings.Join(options, " ")
+}
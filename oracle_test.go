@@ -0,0 +1,106 @@
+package ddlcode
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the generated output to the golden files")
+
+// checkGolden compares the output with testdata/golden/<name>, -update writes it instead.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%v differs from the output, got\n%v", path, got)
+	}
+}
+
+// parseTestSchema parses testdata/schema.sql, which has no warnings.
+func parseTestSchema(t *testing.T) Database {
+	t.Helper()
+	sql, err := os.ReadFile(filepath.Join("testdata", "schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, diags, err := ParseWithOptions(string(sql), ParseOptions{WarningsAsErrors: true})
+	if err != nil {
+		t.Fatalf("parse: %v %v", err, diags)
+	}
+	return db
+}
+
+func TestGenerateOracleDDLGolden(t *testing.T) {
+	files, err := GenerateOracleDDL(parseTestSchema(t), GetDefaultOracleDDLConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "oracle.sql", files["schema.sql"])
+
+	// the output is canonical, generating it again from itself changes nothing
+	db := parseSchema(t, files["schema.sql"])
+	again, err := GenerateOracleDDL(db, GetDefaultOracleDDLConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again["schema.sql"] != files["schema.sql"] {
+		t.Errorf("got from the output\n%v\nwant\n%v", again["schema.sql"], files["schema.sql"])
+	}
+}
+
+func TestGenerateOracleDDLOptions(t *testing.T) {
+	sql := `CREATE TABLE "Parent" (id NUMBER PRIMARY KEY, "select" VARCHAR2(10));
+CREATE TABLE child (id NUMBER PRIMARY KEY, parent_id NUMBER REFERENCES "Parent" (id));`
+	tests := []struct {
+		name   string
+		config func(*OracleDDLConfig)
+		want   []string
+	}{
+		{
+			name:   "quote as needed",
+			config: func(config *OracleDDLConfig) {},
+			want:   []string{`CREATE TABLE "Parent" (`, `  "select" VARCHAR2(10),`, "CREATE TABLE CHILD ("},
+		},
+		{
+			name:   "quote always",
+			config: func(config *OracleDDLConfig) { config.Quoting = QuoteAlways },
+			want:   []string{`CREATE TABLE "CHILD" (`, `  "ID" NUMBER,`},
+		},
+		{
+			name: "foreign keys after tables",
+			config: func(config *OracleDDLConfig) {
+				config.ForeignKeysAfterTables = true
+				config.Indent = "\t"
+			},
+			want: []string{"\tPARENT_ID NUMBER,\n\tPRIMARY KEY (ID)\n);", `ALTER TABLE CHILD ADD FOREIGN KEY (PARENT_ID) REFERENCES "Parent" (ID);`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultOracleDDLConfig()
+			tt.config(&config)
+			files, err := GenerateOracleDDL(parseSchema(t, sql), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(files["schema.sql"], want) {
+					t.Errorf("missing %q in\n%v", want, files["schema.sql"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/ddlcode/toposort"
+	"golang.org/x/exp/slices"
+)
+
+type TableOrder int
+
+const (
+	// TableOrderDeclaration lists the tables in the order they are created
+	TableOrderDeclaration TableOrder = iota
+	// TableOrderAlphabetical lists the tables by schema and name
+	TableOrderAlphabetical
+	// TableOrderForeignKey lists referenced tables before the tables referencing them, otherwise alphabetically,
+	// a foreign key closing a cycle is left out of the order
+	TableOrderForeignKey
+)
+
+// sortTables orders tables given in declaration order.
+func sortTables(tables []*Table, order TableOrder) []*Table {
+	switch order {
+	case TableOrderAlphabetical:
+		slices.SortStableFunc(tables, func(a, b *Table) int { return strings.Compare(a.QualifiedName(), b.QualifiedName()) })
+	case TableOrderForeignKey:
+		return sortByForeignKeys(tables)
+	}
+	return tables
+}
+
+func sortByForeignKeys(tables []*Table) []*Table {
+	tableMap := map[string]*Table{}
+	g := toposort.NewGraph[string]()
+	for _, t := range tables {
+		tableMap[t.QualifiedName()] = t
+		g.AddNode(t.QualifiedName())
+	}
+	for _, t := range tables {
+		for _, fk := range t.ForeignKeys {
+			from, to := fk.RefTable.QualifiedName(), t.QualifiedName()
+			if _, ok := tableMap[from]; !ok || from == to || reaches(g, to, from) {
+				continue
+			}
+			g.AddEdge(from, to)
+		}
+	}
+
+	keys, err := g.Sort()
+	if err != nil {
+		return tables
+	}
+	sorted := []*Table{}
+	for _, key := range keys {
+		sorted = append(sorted, tableMap[key])
+	}
+	return sorted
+}
+
+// reaches reports whether there is a path of edges from one node to another.
+func reaches(g *toposort.Graph[string], from, to string) bool {
+	visited := map[string]bool{}
+	stack := []string{from}
+	for len(stack) > 0 {
+		key := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if key == to {
+			return true
+		}
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+		stack = append(stack, g.Neighbors(key)...)
+	}
+	return false
+}
@@ -2,158 +2,345 @@ package ddlcode
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
-	parser "github.com/codeindex2937/oracle-sql-parser"
 	"github.com/codeindex2937/oracle-sql-parser/ast"
 	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
 )
 
-func Parse(sql string) Database {
+type statement struct {
+	ast.Node
+	Index  int
+	Source string
+	Tokens []token
+	Offset int
+	Line   int
+	Column int
+	File   string
+	// Clauses holds what the oracle parser drops from a CREATE TABLE statement
+	Clauses *tableClauses
+}
+
+// Parse parses the script with the default options, the first statement the parser rejects is returned
+// as an error with its diagnostic. Set ParseOptions.Recover to parse the remaining statements.
+func Parse(sql string) (Database, []Diagnostic, error) {
+	return ParseWithOptions(sql, ParseOptions{})
+}
+
+func ParseWithOptions(sql string, opts ParseOptions) (Database, []Diagnostic, error) {
+	return parseSources([]source{{Name: opts.FileName, SQL: sql}}, opts)
+}
+
+// source is a script and the file it is read from.
+type source struct {
+	Name string
+	SQL  string
+}
+
+// parseSources applies the statements of all sources in order to one schema,
+// so references are resolved against everything loaded.
+func parseSources(sources []source, opts ParseOptions) (Database, []Diagnostic, error) {
+	var diags diagnostics
 	db := Database{
 		DatabaseName: "oracle",
 		Version:      "3.35.5",
-		Views:        []any{},
+		Views:        []*View{},
 		Columns:      []*Column{},
 		Tables:       []*Table{},
+		Sequences:    []*Sequence{},
+		Triggers:     []*Trigger{},
+		Synonyms:     []*Synonym{},
+		UserTypes:    []*UserType{},
+		Privileges:   Privileges{Objects: []*ObjectPrivilege{}, Roles: []*RoleGrant{}},
+		Packages:     []*Package{},
+		Routines:     []*Routine{},
 	}
 
-	stmts, err := parser.Parser(sql)
-	if err != nil {
-		log.Fatal(err)
+	stmts := []statement{}
+	for _, src := range sources {
+		for _, stmt := range splitStatements(src.SQL) {
+			stmt.Index = len(stmts)
+			stmt.File = src.Name
+			stmts = append(stmts, stmt)
+		}
+	}
+	for i, stmt := range stmts {
+		nodes, err := parseStatement(&stmts[i])
+		if err != nil {
+			diags = append(diags, syntaxErrorDiagnostic(stmt, err))
+			if !opts.Recover {
+				return db, diags, err
+			}
+			continue
+		}
+		if len(nodes) == 0 {
+			diags.warnf(stmt, "unsupported statement %v ignored", statementKeyword(stmt))
+			continue
+		}
+		stmts[i].Node = nodes[0]
 	}
 
-	tableMap := map[string]*Table{}
-	createStmts := cast(stmts, castCreateTableStmt)
-	for _, createStmt := range createStmts {
-		table, pkInfo := translateTable(createStmt)
-		tableMap[table.Table] = table
+	builder := newSchemaBuilder(&diags, opts.DefaultSchema)
+	for _, stmt := range stmts {
+		builder.apply(stmt)
+	}
+	builder.reportPending()
 
-		if pkInfo.FieldCount > 0 {
-			db.PkInfo = append(db.PkInfo, pkInfo)
+	for _, stmt := range stmts {
+		if seqStmt := castCreateSequenceStmt(stmt.Node); seqStmt != nil {
+			db.Sequences = append(db.Sequences, seqStmt.Sequence)
 		}
 	}
-	indexStmts := cast(stmts, castCreateIndexStmt)
-	for _, indexStmt := range indexStmts {
-		var indexSchema string
-		indexTable := indexStmt.Index.TableName.Table.Value
-		uniqueIndex := "false"
-		if indexStmt.Type == "unique" {
-			uniqueIndex = "true"
-		}
-		if indexStmt.IndexName.Schema != nil {
-			indexSchema = indexStmt.IndexName.Schema.Value
+	builder.finishSequences(opts.SkipSequenceNaming)
+
+	for _, stmt := range stmts {
+		switch node := stmt.Node.(type) {
+		case nil:
+		case *ast.CreateTableStmt, *createIndexStmt, *alterTableStmt, *ast.CommentStmt, *createSequenceStmt, *createTriggerStmt,
+			*renameStmt, *dropTableStmt, *dropIndexStmt, *createViewStmt, *dropViewStmt, *dropTriggerStmt,
+			*createSynonymStmt, *dropSynonymStmt, *createTypeStmt, *dropTypeStmt, *grantStmt,
+			*createPackageStmt, *createRoutineStmt, *dropRoutineStmt:
+		default:
+			diags.warnf(stmt, "unsupported statement %v ignored", nodeKind(node))
 		}
-		for _, expr := range indexStmt.Index.IndexExprs {
-			db.Indexes = append(db.Indexes, IndexInfo{
-				Schema:      indexSchema,
-				Table:       indexTable,
-				Column:      expr.Column.Value,
-				Cardinality: "",
-				Direction:   expr.Direction,
-				IndexType:   "B-TREE",
-				Name:        indexStmt.IndexName.Index.Value,
-				Size:        "",
-				Unique:      uniqueIndex,
-			})
+	}
+
+	builder.fill(&db, opts.TableOrder)
+
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int { return a.StatementIndex - b.StatementIndex })
+	if opts.WarningsAsErrors && diags.count(SeverityWarning) > 0 {
+		return db, diags, fmt.Errorf("%v warning(s) reported", diags.count(SeverityWarning))
+	}
+	return db, diags, nil
+}
+
+func (stmt statement) span() SourceSpan {
+	return spanOf(stmt.File, stmt.Tokens)
+}
+
+// parseStatement parses the statements ddlcode understands by itself and hands the others to the oracle parser.
+func parseStatement(stmt *statement) ([]ast.Node, error) {
+	var parse func(statement) (ast.Node, error)
+	switch statementKeyword(*stmt) {
+	case "CREATE SEQUENCE":
+		parse = parseCreateSequence
+	case "CREATE INDEX", "CREATE UNIQUE", "CREATE BITMAP", "CREATE MULTIVALUE":
+		parse = parseCreateIndex
+	case "ALTER TABLE":
+		parse = parseAlterTable
+	case "RENAME":
+		parse = parseRename
+	case "DROP TABLE":
+		parse = parseDropTable
+	case "DROP INDEX":
+		parse = parseDropIndex
+	case "DROP VIEW", "DROP MATERIALIZED":
+		parse = parseDropView
+	case "DROP TRIGGER":
+		parse = parseDropTrigger
+	case "DROP SYNONYM", "DROP PUBLIC":
+		parse = parseDropSynonym
+	case "DROP TYPE":
+		parse = parseDropType
+	case "GRANT", "REVOKE":
+		parse = parseGrant
+	case "DROP PACKAGE", "DROP PROCEDURE", "DROP FUNCTION":
+		parse = parseDropRoutine
+	}
+	if isCreateType(leadingWords(stmt.Tokens, 5)) {
+		parse = parseCreateType
+	}
+	if words := leadingWords(stmt.Tokens, 6); len(words) > 0 && words[0] == "CREATE" && slices.Contains(words, "SYNONYM") {
+		parse = parseCreateSynonym
+	}
+	if words := leadingWords(stmt.Tokens, 5); len(words) > 0 && words[0] == "CREATE" && slices.Contains(words, "TRIGGER") {
+		parse = parseCreateTrigger
+	}
+	if words := leadingWords(stmt.Tokens, 8); isCreateView(words) {
+		parse = parseCreateView
+	} else if len(words) > 0 && words[0] == "CREATE" && slices.Contains(words, "VIEW") {
+		// materialized view log
+		return nil, nil
+	}
+	switch createdRoutine(leadingWords(stmt.Tokens, 5)) {
+	case "PACKAGE":
+		parse = parseCreatePackage
+	case "PROCEDURE", "FUNCTION":
+		parse = parseCreateRoutine
+	}
+	if parse != nil {
+		node, err := parse(*stmt)
+		if err != nil {
+			return nil, err
 		}
+		return []ast.Node{node}, nil
 	}
 
-	for _, createStmt := range createStmts {
-		table := tableMap[createStmt.TableName.Table.Value]
-		for _, spec := range cast(createStmt.RelTable.TableStructs, castRefConstraint) {
-			switch spec.InlineConstraint.Type {
-			case ast.ConstraintTypeReferences:
-				if refTable, ok := tableMap[spec.Reference.Table.Table.Value]; ok {
-					db.FkInfo = append(db.FkInfo, assignRefColumns(table, refTable, spec)...)
-				}
+	if words := leadingWords(stmt.Tokens, 4); len(words) > 0 && words[0] == "CREATE" && slices.Contains(words, "TABLE") {
+		source, clauses := scanColumnClauses(*stmt)
+		source, clauses.Properties = scanTableProperties(*stmt, source)
+		stmt.Clauses = clauses
+		nodes, err := parseOracle(clauses.substituteUserTypes(source, stmt.Offset))
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) > 0 {
+			if ct := castCreateTableStmt(nodes[0]); ct != nil {
+				clauses.linkConstraints(ct)
 			}
 		}
+		return nodes, nil
 	}
+	return parseOracle(stmt.Source)
+}
 
-	for _, alterStmt := range cast(stmts, castAlterTableStmt) {
-		table := tableMap[alterStmt.TableName.Table.Value]
-		for _, clause := range cast(alterStmt.AlterTableClauses, castAddConstraintStmt) {
-			for _, spec := range clause.Constraints {
-				switch spec.InlineConstraint.Type {
-				case ast.ConstraintTypeReferences:
-					if refTable, ok := tableMap[spec.Reference.Table.Table.Value]; ok {
-						db.FkInfo = append(db.FkInfo, assignRefColumns(table, refTable, spec)...)
-					}
-				}
+// statementKeyword describes the kind of a statement by its leading keywords, e.g. "CREATE SEQUENCE".
+func statementKeyword(stmt statement) string {
+	words := leadingWords(stmt.Tokens, 4)
+	n := 1
+	if len(words) > 0 && (words[0] == "CREATE" || words[0] == "ALTER" || words[0] == "DROP") {
+		n = 2
+		if len(words) >= 3 && words[1] == "OR" && words[2] == "REPLACE" {
+			n = 4
+		}
+	}
+	if len(words) < n {
+		n = len(words)
+	}
+	return strings.Join(words[:n], " ")
+}
+
+func nodeKind(v any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*ast.")
+}
+
+func constraintName(spec *ast.OutOfLineConstraint) string {
+	if spec.Name == nil {
+		return "<unnamed>"
+	}
+	return spec.Name.Value
+}
+
+// tableName returns the schema, empty when not given, and the name of the table.
+func tableName(name *ast.TableName) (schema, table string) {
+	if name.Schema != nil {
+		schema = name.Schema.Value
+	}
+	return schema, name.Table.Value
+}
+
+// referenceName returns the schema and the name of the table a foreign key references.
+func referenceName(spec *ast.OutOfLineConstraint) (schema, table string) {
+	return tableName(spec.Reference.Table)
+}
+
+func translateForeignKey(table, refTable *Table, spec *ast.OutOfLineConstraint) (*ForeignKey, error) {
+	refColumnNames := mapping(spec.Reference.Columns, getColumnName)
+	if len(refColumnNames) == 0 {
+		// the primary key is referenced when no columns are given
+		for _, col := range refTable.Columns {
+			if col.Attribute.IsPrimaryKey() {
+				refColumnNames = append(refColumnNames, col.Name)
 			}
 		}
 	}
+	if len(spec.Columns) != len(refColumnNames) {
+		return nil, fmt.Errorf("%v column(s) reference %v column(s)", len(spec.Columns), len(refColumnNames))
+	}
 
-	for _, commentStmt := range cast(stmts, castCommentStmt) {
-		table, ok := tableMap[commentStmt.TableName.Table.Value]
-		if !ok {
-			continue
+	fk := &ForeignKey{
+		Table:    table,
+		RefTable: refTable,
+	}
+	if spec.Name != nil {
+		fk.Name = spec.Name.Value
+	}
+	if spec.DeleteAction != nil {
+		fk.OnDelete = ReferenceOptionString(spec.DeleteAction)
+	}
+	if spec.UpdateAction != nil {
+		fk.OnUpdate = ReferenceOptionString(spec.UpdateAction)
+	}
+
+	for i, k := range spec.Columns {
+		columnName := refColumnNames[i]
+		c := table.getColumn(k.Value)
+		if c == nil {
+			return nil, fmt.Errorf("unknown column: %v.%v", table.Table, k.Value)
 		}
-		if commentStmt.Type == ast.CommentOnTable {
-			table.Comment = commentStmt.Comment
-			continue
+		refColumn := refTable.getColumn(columnName)
+		if refColumn == nil {
+			return nil, fmt.Errorf("unknown ref. column: %v.%v => %v.%v", table.Table, k.Value, refTable.Table, columnName)
 		}
-		if commentStmt.Type == ast.CommentOnColumn {
-			for _, col := range table.Columns {
-				if col.Name == commentStmt.ColumnName.Value {
-					col.Comment = commentStmt.Comment
-				}
-			}
-			continue
+		fk.Columns = append(fk.Columns, c)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+	return fk, nil
+}
+
+// assignRefColumns registers the foreign key on both tables.
+func assignRefColumns(fk *ForeignKey) {
+	fk.Table.ForeignKeys = append(fk.Table.ForeignKeys, fk)
+	fk.RefTable.ReferencedBy = append(fk.RefTable.ReferencedBy, fk)
+	for i, c := range fk.Columns {
+		if c.ForeignTable == nil {
+			c.ForeignTable = fk.RefTable
+			c.ForeignColumn = fk.RefColumns[i]
 		}
 	}
+}
 
-	for _, t := range tableMap {
-		db.Tables = append(db.Tables, t)
-		db.Columns = append(db.Columns, t.Columns...)
+// unassignRefColumns removes the foreign key from both tables.
+func unassignRefColumns(fk *ForeignKey) {
+	isFk := func(other *ForeignKey) bool { return other == fk }
+	fk.Table.ForeignKeys = slices.DeleteFunc(fk.Table.ForeignKeys, isFk)
+	fk.RefTable.ReferencedBy = slices.DeleteFunc(fk.RefTable.ReferencedBy, isFk)
+	for _, c := range fk.Columns {
+		if c.ForeignTable != fk.RefTable {
+			continue
+		}
+		c.ForeignTable, c.ForeignColumn = nil, nil
+		for _, other := range fk.Table.GetForeignKeys(c) {
+			c.ForeignTable = other.RefTable
+			c.ForeignColumn = other.RefColumns[slices.Index(other.Columns, c)]
+			break
+		}
 	}
-	return db
 }
 
-func assignRefColumns(table, refTable *Table, spec *ast.OutOfLineConstraint) []FkInfo {
+// fkInfos flattens the foreign key into one FkInfo row per column.
+func fkInfos(fk *ForeignKey) []FkInfo {
 	fkInfos := []FkInfo{}
-
+	table, refTable := fk.Table, fk.RefTable
 	fkDef := fmt.Sprintf("FOREIGN KEY (%v) REFERENCES %v(%v)%v%v",
-		joinStr(mapping(spec.Columns, getColumnName)),
+		joinStr(mapping(fk.Columns, getName)),
 		refTable.Table,
-		joinStr(mapping(spec.Reference.Columns, getColumnName)),
-		refActionStr(spec.UpdateAction, " ON UPDATE"),
-		refActionStr(spec.DeleteAction, " ON DELETE"),
+		joinStr(mapping(fk.RefColumns, getName)),
+		refActionStr(fk.OnUpdate, " ON UPDATE"),
+		refActionStr(fk.OnDelete, " ON DELETE"),
 	)
 
-	for i, k := range spec.Columns {
-		columnName := spec.Reference.Columns[i].Value
-		c := table.getColumn(k.Value)
-		c.ForeignTable = refTable
-		c.ForeignColumn = refTable.getColumn(columnName)
-
-		refColumn := refTable.getColumn(columnName)
-		if refColumn == nil {
-			log.Fatalf("unknown ref. column: %v.%v => %v.%v", table.Table, k.Value, refTable.Table, columnName)
-		}
-
+	for i, c := range fk.Columns {
 		fkInfos = append(fkInfos, FkInfo{
 			Schema:          table.Schema,
 			Table:           table.Table,
 			Column:          c.Name,
 			FkDef:           fkDef,
-			ForeignKeyName:  spec.Name.Value,
+			ForeignKeyName:  fk.Name,
 			ReferenceTable:  refTable.Table,
-			ReferenceColumn: refTable.getColumn(columnName).Name,
+			ReferenceColumn: fk.RefColumns[i].Name,
 		})
 	}
-
 	return fkInfos
 }
 
-func refActionStr(v *ast.ReferenceOption, prefix string) string {
-	if v == nil {
+func refActionStr(action string, prefix string) string {
+	if action == "" {
 		return ""
 	}
-	return fmt.Sprintf("%v %v", prefix, ReferenceOptionString(v))
+	return fmt.Sprintf("%v %v", prefix, action)
 }
 
 func ReferenceOptionString(v *ast.ReferenceOption) string {
@@ -167,7 +354,7 @@ func ReferenceOptionString(v *ast.ReferenceOption) string {
 	case ast.RefOptSetNull:
 		return "SET NULL"
 	case ast.RefOptSetDefault:
-		return "DEFAULT"
+		return "SET DEFAULT"
 	}
 	return ""
 }
@@ -176,6 +363,10 @@ func getColumnName(c *element.Identifier) string {
 	return c.Value
 }
 
+func getName(c *Column) string {
+	return c.Name
+}
+
 func joinStr(segs []string) string {
 	b := strings.Builder{}
 	for i, s := range segs {
@@ -206,37 +397,10 @@ func cast[T any, U any](src []T, fn func(T) *U) []*U {
 	return result
 }
 
-func castCreateTableStmt(v ast.Node) *ast.CreateTableStmt { r, _ := v.(*ast.CreateTableStmt); return r }
-func castCreateIndexStmt(v ast.Node) *ast.CreateIndexStmt { r, _ := v.(*ast.CreateIndexStmt); return r }
-func castAlterTableStmt(v ast.Node) *ast.AlterTableStmt   { r, _ := v.(*ast.AlterTableStmt); return r }
-func castCommentStmt(v ast.Node) *ast.CommentStmt         { r, _ := v.(*ast.CommentStmt); return r }
-func castAddConstraintStmt(v ast.AlterTableClause) *ast.AddConstraintClause {
-	r, _ := v.(*ast.AddConstraintClause)
-	return r
-}
+func castCreateTableStmt(v ast.Node) *ast.CreateTableStmt     { r, _ := v.(*ast.CreateTableStmt); return r }
 func castColDefTableStmt(v ast.TableStructDef) *ast.ColumnDef { r, _ := v.(*ast.ColumnDef); return r }
-func castPkConstraint(v ast.TableStructDef) *ast.OutOfLineConstraint {
-	switch constraint := v.(type) {
-	case *ast.OutOfLineConstraint:
-		if constraint.Type == ast.ConstraintTypePK {
-			return constraint
-		}
-	}
-	return nil
-}
-func castRefConstraint(v ast.TableStructDef) *ast.OutOfLineConstraint {
-	switch constraint := v.(type) {
-	case *ast.OutOfLineConstraint:
-		if constraint.Type == ast.ConstraintTypeReferences {
-			return constraint
-		}
-	}
-	return nil
-}
 
-func translateTable(ct *ast.CreateTableStmt) (*Table, PkInfo) {
-	var pkInfo PkInfo
-	isPrimaryKey := make(map[string]ast.Node)
+func translateTable(ct *ast.CreateTableStmt, clauses map[string]*columnClauses) *Table {
 	var schema string
 
 	if ct.TableName.Schema != nil {
@@ -249,21 +413,7 @@ func translateTable(ct *ast.CreateTableStmt) (*Table, PkInfo) {
 		Columns: []*Column{},
 		Rows:    -1,
 		Type:    "table",
-	}
-
-	for _, constraint := range cast(ct.RelTable.TableStructs, castPkConstraint) {
-		colNames := joinStr(mapping(constraint.Columns, getColumnName))
-		pkInfo = PkInfo{
-			Schema:     schema,
-			Table:      ct.TableName.Table.Value,
-			FieldCount: len(constraint.Columns),
-			PkColumn:   colNames,
-			PkDef:      fmt.Sprintf("PRIMARY KEY (%v)", colNames),
-		}
-
-		for _, col := range constraint.Columns {
-			isPrimaryKey[col.Value] = nil
-		}
+		Quoted:  isQuoted(ct.TableName.Table),
 	}
 
 	for i, def := range cast(ct.RelTable.TableStructs, castColDefTableStmt) {
@@ -277,6 +427,7 @@ func translateTable(ct *ast.CreateTableStmt) (*Table, PkInfo) {
 		}
 		c := &Column{
 			Name:            def.ColumnName.Value,
+			Quoted:          isQuoted(def.ColumnName),
 			DataType:        def.Datatype,
 			Type:            typeStr(def.Datatype.DataDef()),
 			Attribute:       opts,
@@ -284,11 +435,8 @@ func translateTable(ct *ast.CreateTableStmt) (*Table, PkInfo) {
 			Schema:          schema,
 			Table:           table.Table,
 		}
-		if def.Collation != nil {
-			c.Collation = def.ColumnName.Value
-		}
-		if _, ok := opts[ast.ConstraintTypeDefault]; ok {
-			c.Default = def.ColumnName.Value
+		if def.Collation != nil && def.Collation.Name != nil {
+			c.Collation = def.Collation.Name.Value
 		}
 		if _, ok := opts[ast.ConstraintTypeNull]; ok {
 			c.Nullable = "true"
@@ -297,13 +445,33 @@ func translateTable(ct *ast.CreateTableStmt) (*Table, PkInfo) {
 		}
 		setCharacterMaximumLength(c, def.Datatype)
 		setPrecision(c, def.Datatype)
-		if _, ok := isPrimaryKey[c.Name]; ok {
-			c.Attribute[ast.ConstraintTypePK] = nil
+		if clause, ok := clauses[c.Name]; ok {
+			if clause.UserType != nil {
+				c.DataType, c.Type = clause.UserType, clause.UserType.String()
+			}
+			c.Span = clause.Span
+			c.Identity = clause.Identity
+			c.setDefault(clause.Default)
+		}
+		for _, con := range def.Constraints {
+			switch {
+			case con.Type == ast.ConstraintTypeUnique:
+				uc := &UniqueConstraint{Columns: []*Column{c}, Span: c.Span}
+				if con.Name != nil {
+					uc.Name = con.Name.Value
+				}
+				table.UniqueConstraints = append(table.UniqueConstraints, uc)
+			case con.Type == ast.ConstraintTypePK:
+				table.PrimaryKey = append(table.PrimaryKey, c)
+				if con.Name != nil {
+					table.PrimaryKeyName = con.Name.Value
+				}
+			}
 		}
 		table.Columns = append(table.Columns, c)
 	}
 
-	return table, pkInfo
+	return table
 }
 
 func setCharacterMaximumLength(c *Column, dataType element.Datatype) {
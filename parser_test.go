@@ -0,0 +1,94 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		tables  int
+		wantErr bool
+		diags   []string
+	}{
+		{
+			name:   "valid script",
+			sql:    `CREATE TABLE a (id NUMBER PRIMARY KEY); CREATE TABLE b (a_id NUMBER REFERENCES a (id));`,
+			tables: 2,
+		},
+		{
+			name:    "rejected statement",
+			sql:     `CREATE TABLE a (id NUMBER); CREATE TABLE (;`,
+			wantErr: true,
+			diags:   []string{"error"},
+		},
+		{
+			name:   "unresolved foreign key",
+			sql:    `CREATE TABLE b (a_id NUMBER REFERENCES a (id));`,
+			tables: 1,
+			diags:  []string{"warning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := Parse(tt.sql)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v", err)
+			}
+			if !tt.wantErr && len(db.Tables) != tt.tables {
+				t.Errorf("got %v tables, want %v", len(db.Tables), tt.tables)
+			}
+			severities := mapping(diags, func(d Diagnostic) string { return d.Severity.String() })
+			if len(severities) != len(tt.diags) {
+				t.Fatalf("got diagnostics %v, want severities %v", diags, tt.diags)
+			}
+			for i, severity := range severities {
+				if severity != tt.diags[i] {
+					t.Errorf("got diagnostic %v, want severity %v", diags[i], tt.diags[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseWithOptionsRecover(t *testing.T) {
+	sql := `CREATE TABLE a (id NUMBER PRIMARY KEY);
+CREATE TABLE broken (;
+BEGIN
+  DBMS_OUTPUT.PUT_LINE('x; y');
+END;
+/
+CREATE TABLE b (a_id NUMBER REFERENCES a (id));
+CREATE TABLE (id NUMBER);
+CREATE TABLE c (id NUMBER);`
+
+	db, diags, err := ParseWithOptions(sql, ParseOptions{Recover: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := mapping(db.Tables, func(table *Table) string { return table.Table })
+	if want := []string{"A", "B", "C"}; !slices.Equal(names, want) {
+		t.Errorf("got tables %v, want %v", names, want)
+	}
+	if len(db.Tables) == 3 && len(db.Tables[1].ForeignKeys) != 1 {
+		t.Errorf("foreign key of B not resolved")
+	}
+	// the anonymous block on line 3 is rejected as a whole, its semicolons do not split it
+	lines := []int{}
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			lines = append(lines, d.Line)
+		}
+	}
+	if want := []int{2, 3, 8}; !slices.Equal(lines, want) {
+		t.Errorf("got errors on lines %v, want %v: %v", lines, want, diags)
+	}
+
+	if _, _, err := ParseWithOptions(sql, ParseOptions{}); err == nil {
+		t.Errorf("got no error without Recover")
+	}
+}
@@ -0,0 +1,171 @@
+package ddlcode
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// tableProperties are the physical and table properties following the relational properties of CREATE TABLE.
+type tableProperties struct {
+	Tablespace   string
+	Organization string
+	Temporary    string
+	Storage      map[string]string
+	Partitioning *partitionDef
+}
+
+// partitionDef is a PARTITION BY or a SUBPARTITION BY clause as written.
+type partitionDef struct {
+	Method     string
+	Columns    []string
+	Interval   string
+	Count      int
+	Partitions []*Partition
+	Sub        *partitionDef
+}
+
+// storageKeywords are the physical attributes kept in Table.Storage with their value,
+// storageFlags are kept with an empty value unless followed by a degree or a compression type.
+var storageKeywords = []string{"PCTFREE", "PCTUSED", "INITRANS", "MAXTRANS"}
+var storageFlags = []string{"COMPRESS", "NOCOMPRESS", "LOGGING", "NOLOGGING", "CACHE", "NOCACHE", "PARALLEL", "NOPARALLEL"}
+
+// scanTableProperties reads the properties after the relational properties of a CREATE TABLE statement,
+// they are blanked in the returned source as the oracle parser rejects many of them.
+func scanTableProperties(stmt statement, source string) (string, tableProperties) {
+	props := tableProperties{Storage: map[string]string{}}
+	words := leadingWords(stmt.Tokens, 4)
+	if len(words) > 2 && (words[1] == "GLOBAL" || words[1] == "PRIVATE") && words[2] == "TEMPORARY" {
+		props.Temporary = words[1]
+	}
+
+	r := newTokenReader(stmt.Tokens)
+	for !r.done() && !r.peek().Is("(") {
+		r.next()
+	}
+	r.group()
+	source = blankTokens(source, stmt.Offset, r.tokens[r.pos:])
+	for !r.done() {
+		switch {
+		case r.accept("TABLESPACE"):
+			props.Tablespace = r.next().Value()
+		case r.accept("ORGANIZATION"):
+			props.Organization = r.next().Value()
+		case r.accept("STORAGE"):
+			storage := newTokenReader(r.group())
+			for !storage.done() {
+				name := storage.next().Value()
+				props.Storage[name] = sizeValue(storage)
+			}
+		case slices.ContainsFunc(storageKeywords, r.peek().Is):
+			name := r.next().Value()
+			props.Storage[name] = sizeValue(r)
+		case slices.ContainsFunc(storageFlags, r.peek().Is):
+			name := r.next().Value()
+			value := []string{}
+			switch {
+			case r.peek().Kind == tokenNumber, r.peek().Is("BASIC"):
+				value = append(value, r.next().Value())
+			case r.peek().Is("FOR"):
+				value = append(value, r.next().Value(), r.next().Value())
+				if r.peek().Is("HIGH") || r.peek().Is("LOW") || r.peek().Is("OPERATIONS") {
+					value = append(value, r.next().Value())
+				}
+			}
+			props.Storage[name] = strings.Join(value, " ")
+		case r.peek().Is("PARTITION") && r.pos+1 < len(r.tokens) && r.tokens[r.pos+1].Is("BY"):
+			r.next()
+			props.Partitioning = parsePartitionBy(stmt, r, false)
+		default:
+			if r.peek().Is("(") {
+				r.group()
+			} else {
+				r.next()
+			}
+		}
+	}
+	return source, props
+}
+
+// sizeValue reads a value such as 10, 64K or UNLIMITED, the tokenizer splits a number from its unit.
+func sizeValue(r *tokenReader) string {
+	value := r.next()
+	unit := r.peek()
+	if value.Kind == tokenNumber && unit.Kind == tokenWord && unit.Offset == value.End() && len(unit.Text) == 1 {
+		return value.Text + r.next().Value()
+	}
+	return value.Value()
+}
+
+// parsePartitionBy reads "BY method (columns)" and what follows up to the partition list included,
+// a subpartitioning stops before the partition list of the partitioning.
+func parsePartitionBy(stmt statement, r *tokenReader, sub bool) *partitionDef {
+	def := &partitionDef{}
+	r.accept("BY")
+	def.Method = r.next().Value()
+	if r.peek().Is("(") {
+		for _, item := range splitTopLevel(r.group(), ",") {
+			if len(item) > 0 {
+				def.Columns = append(def.Columns, item[0].Value())
+			}
+		}
+	}
+	for !r.done() {
+		switch {
+		case r.accept("STORE", "IN"):
+			r.group()
+		case sub && r.accept("SUBPARTITIONS"):
+			def.Count, _ = strconv.Atoi(r.next().Text)
+		case sub && r.accept("SUBPARTITION", "TEMPLATE"):
+			def.Partitions = parsePartitionList(stmt, r.group())
+		case sub:
+			return def
+		case r.accept("INTERVAL"):
+			def.Interval = clauseText(stmt, r.group())
+		case r.accept("SUBPARTITION", "BY"):
+			r.pos -= 1
+			def.Sub = parsePartitionBy(stmt, r, true)
+		case r.accept("PARTITIONS"):
+			def.Count, _ = strconv.Atoi(r.next().Text)
+		case r.peek().Is("("):
+			def.Partitions = parsePartitionList(stmt, r.group())
+			return def
+		default:
+			return def
+		}
+	}
+	return def
+}
+
+// parsePartitionList reads "[SUB]PARTITION name [VALUES ...] [TABLESPACE name] [(subpartitions)]" items.
+func parsePartitionList(stmt statement, toks []token) []*Partition {
+	partitions := []*Partition{}
+	for _, item := range splitTopLevel(toks, ",") {
+		r := newTokenReader(item)
+		if !r.accept("PARTITION") && !r.accept("SUBPARTITION") {
+			continue
+		}
+		partition := &Partition{}
+		if isIdentifier(r.peek()) && !r.peek().Is("VALUES") && !r.peek().Is("TABLESPACE") {
+			partition.Name = r.next().Value()
+		}
+		for !r.done() {
+			switch {
+			case r.accept("VALUES"):
+				start := r.pos
+				r.accept("LESS", "THAN")
+				r.group()
+				partition.Values = clauseText(stmt, item[start:r.pos])
+			case r.accept("TABLESPACE"):
+				partition.Tablespace = r.next().Value()
+			case r.peek().Is("("):
+				partition.Subpartitions = parsePartitionList(stmt, r.group())
+			default:
+				r.next()
+			}
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
@@ -0,0 +1,140 @@
+package ddlcode
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+)
+
+type PostgresConfig struct {
+	ExportDir string
+	FileName  string
+	// Indent indents the columns and the constraints in CREATE TABLE
+	Indent string
+	// Types overrides the type mapping, keys are Oracle types as printed by the model such as "NUMBER(1)",
+	// or their name without size such as "DATE", values are PostgreSQL types
+	Types map[string]string
+}
+
+// postgresTypes maps the Oracle types taking no size to PostgreSQL, the sized ones are mapped by toPostgresType.
+var postgresTypes = map[element.DataDef]string{
+	element.DataDefInteger:         "integer",
+	element.DataDefInt:             "integer",
+	element.DataDefSmallInt:        "smallint",
+	element.DataDefReal:            "double precision",
+	element.DataDefBinaryFloat:     "real",
+	element.DataDefBinaryDouble:    "double precision",
+	element.DataDefDoublePrecision: "double precision",
+	element.DataDefDate:            "timestamp(0)",
+	element.DataDefLong:            "text",
+	element.DataDefClob:            "text",
+	element.DataDefNClob:           "text",
+	element.DataDefBlob:            "bytea",
+	element.DataDefLongRaw:         "bytea",
+	element.DataDefXMLType:         "xml",
+	element.DataDefIntervalYear:    "interval year to month",
+	element.DataDefIntervalDay:     "interval day to second",
+}
+
+// postgresReservedWords are the PostgreSQL reserved words, they are quoted when used as names.
+var postgresReservedWords = []string{
+	"all", "analyse", "analyze", "and", "any", "array", "as", "asc", "asymmetric", "both", "case", "cast", "check",
+	"collate", "column", "constraint", "create", "current_catalog", "current_date", "current_role", "current_time",
+	"current_timestamp", "current_user", "default", "deferrable", "desc", "distinct", "do", "else", "end", "except",
+	"false", "fetch", "for", "foreign", "from", "grant", "group", "having", "in", "initially", "intersect", "into",
+	"lateral", "leading", "limit", "localtime", "localtimestamp", "not", "null", "offset", "on", "only", "or", "order",
+	"placing", "primary", "references", "returning", "select", "session_user", "some", "symmetric", "system_user",
+	"table", "then", "to", "trailing", "true", "union", "unique", "user", "using", "variadic", "when", "where",
+	"window", "with",
+}
+
+func GetDefaultPostgresConfig() PostgresConfig {
+	return PostgresConfig{
+		ExportDir: ".",
+		FileName:  "postgres.sql",
+		Indent:    "  ",
+		Types:     map[string]string{},
+	}
+}
+
+// GeneratePostgres translates the schema to PostgreSQL DDL: the schemas, the sequences, the tables referenced
+// tables first, the foreign keys closing a cycle, the indexes, the comments and the views. Unquoted names are
+// folded to lower case. The diagnostics are those of CheckConformance, the types which cannot be mapped and the
+// expressions kept as written which call other functions than the portable ones or use Oracle keywords.
+func GeneratePostgres(db Database, config PostgresConfig) (map[string]string, []Diagnostic, error) {
+	if config.FileName == "" {
+		return nil, nil, fmt.Errorf("file name is empty")
+	}
+	w := &dialectWriter{dialect: DialectPostgres, indent: config.Indent, types: config.Types}
+	content := w.script(db)
+	diags := append(CheckConformance(db, DialectPostgres, ""), w.diags...)
+	return map[string]string{filepath.Join(config.ExportDir, config.FileName): content}, diags, nil
+}
+
+// toPostgresType maps an Oracle type to PostgreSQL, NUMBER becomes integer, bigint or numeric by its precision
+// and scale. It reports false for the types PostgreSQL has no equivalent of.
+func toPostgresType(datatype element.Datatype) (string, bool) {
+	if name, ok := postgresTypes[datatype.DataDef()]; ok {
+		return name, true
+	}
+	switch datatype.DataDef() {
+	case element.DataDefNumber, element.DataDefDecimal, element.DataDefDec, element.DataDefNumeric:
+		realType := datatype.(*element.Number)
+		switch {
+		case realType.Precision == nil:
+			return "numeric", true
+		case realType.Precision.IsAsterisk && realType.Scale != nil:
+			return fmt.Sprintf("numeric(38, %v)", *realType.Scale), true
+		case realType.Precision.IsAsterisk:
+			return "numeric", true
+		case realType.Scale != nil && *realType.Scale != 0:
+			return fmt.Sprintf("numeric(%v, %v)", realType.Precision.Number, *realType.Scale), true
+		case realType.Precision.Number <= 9:
+			return "integer", true
+		case realType.Precision.Number <= 18:
+			return "bigint", true
+		}
+		return fmt.Sprintf("numeric(%v)", realType.Precision.Number), true
+	case element.DataDefFloat:
+		realType := datatype.(*element.Float)
+		// the precision of FLOAT is in binary digits
+		if realType.Precision != nil && !realType.Precision.IsAsterisk && realType.Precision.Number <= 24 {
+			return "real", true
+		}
+		return "double precision", true
+	case element.DataDefChar, element.DataDefCharacter:
+		if size := datatype.(*element.Char).Size; size != nil {
+			return fmt.Sprintf("char(%v)", *size), true
+		}
+		return "char(1)", true
+	case element.DataDefVarchar2, element.DataDefVarchar, element.DataDefCharacterVarying, element.DataDefCharVarying:
+		if size := datatype.(*element.Varchar2).Size; size != nil {
+			return fmt.Sprintf("varchar(%v)", *size), true
+		}
+		return "varchar", true
+	case element.DataDefNChar, element.DataDefNationalCharacter, element.DataDefNationalChar:
+		if size := datatype.(*element.NChar).Size; size != nil {
+			return fmt.Sprintf("char(%v)", *size), true
+		}
+		return "char(1)", true
+	case element.DataDefNVarChar2, element.DataDefNCharVarying, element.DataDefNationalCharacterVarying, element.DataDefNationalCharVarying:
+		if size := datatype.(*element.NVarchar2).Size; size != nil {
+			return fmt.Sprintf("varchar(%v)", *size), true
+		}
+		return "varchar", true
+	case element.DataDefRaw:
+		return "bytea", true
+	case element.DataDefTimestamp:
+		realType := datatype.(*element.Timestamp)
+		name := "timestamp"
+		if realType.FractionalSecondsPrecision != nil {
+			name = fmt.Sprintf("timestamp(%v)", *realType.FractionalSecondsPrecision)
+		}
+		if realType.WithTimeZone || realType.WithLocalTimeZone {
+			name += " with time zone"
+		}
+		return name, true
+	}
+	return "", false
+}
@@ -0,0 +1,15 @@
+package ddlcode
+
+import (
+	"testing"
+)
+
+func TestGeneratePostgresGolden(t *testing.T) {
+	config := GetDefaultPostgresConfig()
+	files, diags, err := GeneratePostgres(parseTestSchema(t), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "postgres.sql", files[config.FileName])
+	checkGolden(t, "postgres.diagnostics", diagnosticLines(diags))
+}
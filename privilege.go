@@ -0,0 +1,290 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/slices"
+)
+
+// grantStmt is GRANT or REVOKE of object privileges when Object is set, of roles otherwise.
+type grantStmt struct {
+	sourceNode
+	Revoke     bool
+	Privileges []grantedPrivilege
+	Schema     string
+	Object     string
+	Grantees   []string
+	// GrantOption is set by WITH GRANT OPTION
+	GrantOption bool
+}
+
+type grantedPrivilege struct {
+	Name    string
+	Columns []string
+}
+
+// tablePrivileges are the privileges ALL [PRIVILEGES] grants on a table or a view.
+var tablePrivileges = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "ALTER", "INDEX", "REFERENCES"}
+
+// writePrivileges are the privileges a role needs to change the rows of a table.
+var writePrivileges = []string{"INSERT", "UPDATE", "DELETE"}
+
+// objectKinds may precede the object name of ON, e.g. ON DIRECTORY data_dir.
+var objectKinds = [][]string{{"DIRECTORY"}, {"EDITION"}, {"MINING", "MODEL"}, {"JAVA", "SOURCE"}, {"JAVA", "RESOURCE"}, {"SQL", "TRANSLATION", "PROFILE"}}
+
+// parseGrant reads GRANT privileges ON [schema.]object TO grantees [WITH GRANT OPTION],
+// REVOKE privileges ON [schema.]object FROM grantees and the grants and revokes of roles.
+// System privileges are skipped.
+func parseGrant(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	node := &grantStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Revoke = r.accept("REVOKE")
+	if !node.Revoke && !r.accept("GRANT") {
+		return nil, syntaxError(stmt, r.peek(), "expected GRANT or REVOKE")
+	}
+	target := "TO"
+	if node.Revoke {
+		target = "FROM"
+	}
+
+	start := r.pos
+	isOn := func() bool { return r.peek().Is("ON") && (r.pos+1 >= len(r.tokens) || !r.tokens[r.pos+1].Is("COMMIT")) }
+	for !r.done() && !r.peek().Is(target) && !isOn() {
+		if r.peek().Is("(") {
+			r.group()
+		} else {
+			r.next()
+		}
+	}
+	for _, item := range splitTopLevel(r.tokens[start:r.pos], ",") {
+		privilege := grantedPrivilege{}
+		words := []string{}
+		for i := 0; i < len(item); i++ {
+			if item[i].Is("(") {
+				columns := newTokenReader(item[i:]).group()
+				for _, column := range splitTopLevel(columns, ",") {
+					if len(column) > 0 {
+						privilege.Columns = append(privilege.Columns, column[0].Value())
+					}
+				}
+				break
+			}
+			words = append(words, item[i].Value())
+		}
+		privilege.Name = strings.Join(words, " ")
+		if privilege.Name == "ALL PRIVILEGES" {
+			privilege.Name = "ALL"
+		}
+		if privilege.Name != "" {
+			node.Privileges = append(node.Privileges, privilege)
+		}
+	}
+	if len(node.Privileges) == 0 {
+		return nil, syntaxError(stmt, r.peek(), "expected privilege or role")
+	}
+
+	if r.accept("ON") {
+		for _, kind := range objectKinds {
+			if r.accept(kind...) {
+				break
+			}
+		}
+		if !isIdentifier(r.peek()) {
+			return nil, syntaxError(stmt, r.peek(), "expected object name")
+		}
+		node.Schema, node.Object = r.qualifiedName()
+	}
+	if !r.accept(target) {
+		return nil, syntaxError(stmt, r.peek(), "expected %v", target)
+	}
+	for isIdentifier(r.peek()) {
+		node.Grantees = append(node.Grantees, r.next().Value())
+		if !r.accept(",") {
+			break
+		}
+	}
+	if len(node.Grantees) == 0 {
+		return nil, syntaxError(stmt, r.peek(), "expected grantee")
+	}
+	node.GrantOption = r.accept("WITH", "GRANT", "OPTION")
+	return node, nil
+}
+
+// grant applies GRANT or REVOKE. Privileges on a synonym are those of the table it stands for.
+func (b *schemaBuilder) grant(stmt statement, node *grantStmt) {
+	if node.Object == "" {
+		b.grantRoles(node)
+		return
+	}
+	schema, object := node.Schema, node.Object
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	table, ok := b.relation(node.Schema, node.Object)
+	if ok {
+		schema, object = table.Schema, table.Table
+	}
+	// privileges on a table follow it when it is renamed
+	isOnObject := func(p *ObjectPrivilege) bool {
+		if table != nil {
+			return p.Table == table
+		}
+		return p.Table == nil && p.Schema == schema && p.Object == object
+	}
+
+	names := []grantedPrivilege{}
+	for _, privilege := range node.Privileges {
+		if privilege.Name != "ALL" {
+			names = append(names, privilege)
+			continue
+		}
+		if node.Revoke {
+			// REVOKE ALL revokes whatever is granted
+			for _, grantee := range node.Grantees {
+				b.privileges = slices.DeleteFunc(b.privileges, func(p *ObjectPrivilege) bool {
+					return p.Grantee == grantee && isOnObject(p)
+				})
+			}
+			continue
+		}
+		for _, name := range tablePrivileges {
+			names = append(names, grantedPrivilege{Name: name})
+		}
+	}
+	for _, grantee := range node.Grantees {
+		for _, privilege := range names {
+			i := slices.IndexFunc(b.privileges, func(p *ObjectPrivilege) bool {
+				return p.Grantee == grantee && p.Privilege == privilege.Name && isOnObject(p)
+			})
+			if node.Revoke {
+				if i < 0 {
+					b.diags.warnf(stmt, "revoke of %v on %v from %v never granted", privilege.Name, qualifiedName(schema, object), grantee)
+					continue
+				}
+				b.privileges = slices.Delete(b.privileges, i, i+1)
+				continue
+			}
+			if i >= 0 {
+				// a privilege granted again on other columns extends the columns, on the whole object it drops them
+				existing := b.privileges[i]
+				if len(existing.Columns) > 0 && len(privilege.Columns) > 0 {
+					for _, column := range privilege.Columns {
+						if !slices.Contains(existing.Columns, column) {
+							existing.Columns = append(existing.Columns, column)
+						}
+					}
+				} else {
+					existing.Columns = nil
+				}
+				existing.GrantOption = existing.GrantOption || node.GrantOption
+				continue
+			}
+			b.privileges = append(b.privileges, &ObjectPrivilege{
+				Grantee:     grantee,
+				Privilege:   privilege.Name,
+				Schema:      schema,
+				Object:      object,
+				Table:       table,
+				Columns:     privilege.Columns,
+				GrantOption: node.GrantOption,
+				Span:        stmt.span(),
+			})
+		}
+	}
+}
+
+// grantRoles grants or revokes the roles, privilege names of several words are system privileges and skipped.
+func (b *schemaBuilder) grantRoles(node *grantStmt) {
+	for _, grantee := range node.Grantees {
+		for _, privilege := range node.Privileges {
+			if strings.Contains(privilege.Name, " ") {
+				continue
+			}
+			isGrant := func(g *RoleGrant) bool { return g.Role == privilege.Name && g.Grantee == grantee }
+			if node.Revoke {
+				b.roleGrants = slices.DeleteFunc(b.roleGrants, isGrant)
+			} else if !slices.ContainsFunc(b.roleGrants, isGrant) {
+				b.roleGrants = append(b.roleGrants, &RoleGrant{Role: privilege.Name, Grantee: grantee})
+			}
+		}
+	}
+}
+
+// privilegesOnCurrentNames names the objects of the privileges on tables by the current name of the table.
+func (b *schemaBuilder) privilegesOnCurrentNames() []*ObjectPrivilege {
+	for _, privilege := range b.privileges {
+		if privilege.Table != nil {
+			privilege.Schema, privilege.Object = privilege.Table.Schema, privilege.Table.Table
+		}
+	}
+	return b.privileges
+}
+
+// dropPrivileges drops the privileges on a dropped table or view.
+func (b *schemaBuilder) dropPrivileges(table *Table) {
+	b.privileges = slices.DeleteFunc(b.privileges, func(p *ObjectPrivilege) bool { return p.Table == table })
+}
+
+// Grantees returns the users and roles holding object privileges, in the order of their first grant.
+func (p Privileges) Grantees() []string {
+	grantees := []string{}
+	for _, privilege := range p.Objects {
+		if !slices.Contains(grantees, privilege.Grantee) {
+			grantees = append(grantees, privilege.Grantee)
+		}
+	}
+	return grantees
+}
+
+// roles returns the grantee, PUBLIC and the roles granted to the grantee directly or through other roles.
+func (p Privileges) roles(grantee string) []string {
+	roles := []string{grantee, publicSchema}
+	for i := 0; i < len(roles); i++ {
+		for _, grant := range p.Roles {
+			if grant.Grantee == roles[i] && !slices.Contains(roles, grant.Role) {
+				roles = append(roles, grant.Role)
+			}
+		}
+	}
+	return roles
+}
+
+// Granted returns the privileges on the table held by the grantee, directly when effective is unset,
+// otherwise also through PUBLIC and the roles granted to the grantee.
+func (p Privileges) Granted(grantee string, table *Table, effective bool) []*ObjectPrivilege {
+	roles := []string{grantee}
+	if effective {
+		roles = p.roles(grantee)
+	}
+	granted := []*ObjectPrivilege{}
+	for _, privilege := range p.Objects {
+		if privilege.Table == table && slices.Contains(roles, privilege.Grantee) {
+			granted = append(granted, privilege)
+		}
+	}
+	return granted
+}
+
+// IsReadOnly reports whether the grantee cannot change the rows of the table, that is when the table
+// belongs to another schema and neither INSERT, UPDATE nor DELETE is granted to the grantee or its roles.
+// A table without a schema, unqualified and parsed without ParseOptions.DefaultSchema, is taken as the grantee's.
+func (p Privileges) IsReadOnly(grantee string, table *Table) bool {
+	if table.IsView() {
+		return true
+	}
+	if table.Schema == "" || table.Schema == grantee {
+		return false
+	}
+	return !slices.ContainsFunc(p.Granted(grantee, table, true), func(privilege *ObjectPrivilege) bool {
+		return slices.Contains(writePrivileges, privilege.Privilege)
+	})
+}
+
+// String describes the privilege as "UPDATE (A, B)".
+func (p *ObjectPrivilege) String() string {
+	if len(p.Columns) == 0 {
+		return p.Privilege
+	}
+	return p.Privilege + " (" + strings.Join(p.Columns, ", ") + ")"
+}
@@ -0,0 +1,205 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestGrants(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		// privileges lists the object privileges as "GRANTEE PRIVILEGE ON OBJECT"
+		privileges []string
+		// roles lists the role grants as "ROLE TO GRANTEE"
+		roles []string
+		diags []string
+	}{
+		{
+			name: "object privileges",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY, name VARCHAR2(30), salary NUMBER);
+GRANT SELECT, UPDATE (name, salary) ON hr.employees TO app, report WITH GRANT OPTION;
+GRANT UPDATE (id) ON hr.employees TO app;
+GRANT READ ON DIRECTORY data_dir TO app;`,
+			privileges: []string{
+				"APP SELECT ON HR.EMPLOYEES",
+				"APP UPDATE (NAME, SALARY, ID) ON HR.EMPLOYEES",
+				"REPORT SELECT ON HR.EMPLOYEES",
+				"REPORT UPDATE (NAME, SALARY) ON HR.EMPLOYEES",
+				"APP READ ON DATA_DIR",
+			},
+		},
+		{
+			name: "all privileges and revokes",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+GRANT ALL PRIVILEGES ON hr.employees TO app;
+GRANT SELECT ON hr.employees TO report;
+REVOKE ALL ON hr.employees FROM app;
+REVOKE INSERT ON hr.employees FROM report;
+GRANT ALL ON hr.employees TO audit;
+REVOKE ALTER, INDEX, REFERENCES ON hr.employees FROM audit;`,
+			privileges: []string{
+				"REPORT SELECT ON HR.EMPLOYEES",
+				"AUDIT SELECT ON HR.EMPLOYEES",
+				"AUDIT INSERT ON HR.EMPLOYEES",
+				"AUDIT UPDATE ON HR.EMPLOYEES",
+				"AUDIT DELETE ON HR.EMPLOYEES",
+			},
+			diags: []string{"revoke of INSERT on HR.EMPLOYEES from REPORT never granted"},
+		},
+		{
+			name: "synonyms, renamed and dropped tables",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+CREATE TABLE hr.jobs (id NUMBER PRIMARY KEY);
+CREATE PUBLIC SYNONYM emp FOR hr.employees;
+GRANT SELECT ON emp TO app;
+GRANT SELECT ON hr.jobs TO app;
+ALTER TABLE hr.employees RENAME TO staff;
+DROP TABLE hr.jobs;`,
+			privileges: []string{"APP SELECT ON HR.STAFF"},
+		},
+		{
+			name: "roles",
+			script: `GRANT CREATE SESSION, clerk TO app;
+GRANT manager TO clerk, app;
+REVOKE manager FROM app;`,
+			roles: []string{"CLERK TO APP", "MANAGER TO CLERK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := ParseWithOptions(tt.script, ParseOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			privileges := mapping(db.Privileges.Objects, func(p *ObjectPrivilege) string {
+				return p.Grantee + " " + p.String() + " ON " + qualifiedName(p.Schema, p.Object)
+			})
+			if !slices.Equal(privileges, tt.privileges) {
+				t.Errorf("got privileges %q, want %q", privileges, tt.privileges)
+			}
+			roles := mapping(db.Privileges.Roles, func(g *RoleGrant) string { return g.Role + " TO " + g.Grantee })
+			if !slices.Equal(roles, tt.roles) {
+				t.Errorf("got roles %q, want %q", roles, tt.roles)
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.diags)
+			}
+		})
+	}
+}
+
+func TestEffectivePrivileges(t *testing.T) {
+	script := `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+CREATE TABLE hr.jobs (id NUMBER PRIMARY KEY);
+CREATE TABLE hr.regions (id NUMBER PRIMARY KEY);
+CREATE TABLE local_notes (id NUMBER PRIMARY KEY);
+CREATE VIEW hr.staff AS SELECT id FROM hr.employees;
+GRANT clerk TO app;
+GRANT writer TO clerk;
+GRANT SELECT ON hr.employees TO app;
+GRANT UPDATE ON hr.employees TO writer;
+GRANT DELETE ON hr.jobs TO PUBLIC;
+GRANT SELECT ON hr.regions TO app;
+GRANT SELECT, UPDATE ON hr.staff TO app;`
+	db, diags, err := ParseWithOptions(script, ParseOptions{})
+	if err != nil || len(diags) > 0 {
+		t.Fatalf("parse: %v %v", err, diags)
+	}
+	table := func(name string) *Table {
+		return db.Tables[slices.IndexFunc(db.Tables, func(table *Table) bool { return table.QualifiedName() == name })]
+	}
+	view := db.Views[0].AsTable()
+
+	tests := []struct {
+		table     *Table
+		grantee   string
+		direct    []string
+		effective []string
+		readOnly  bool
+	}{
+		{table: table("HR.EMPLOYEES"), grantee: "APP", direct: []string{"SELECT"}, effective: []string{"SELECT", "UPDATE"}},
+		{table: table("HR.JOBS"), grantee: "APP", direct: []string{}, effective: []string{"DELETE"}},
+		{table: table("HR.REGIONS"), grantee: "APP", direct: []string{"SELECT"}, effective: []string{"SELECT"}, readOnly: true},
+		{table: table("HR.EMPLOYEES"), grantee: "HR", direct: []string{}, effective: []string{}},
+		{table: table("LOCAL_NOTES"), grantee: "APP", direct: []string{}, effective: []string{}},
+		{table: view, grantee: "APP", direct: []string{"SELECT", "UPDATE"}, effective: []string{"SELECT", "UPDATE"}, readOnly: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.grantee+" on "+tt.table.QualifiedName(), func(t *testing.T) {
+			direct := mapping(db.Privileges.Granted(tt.grantee, tt.table, false), (*ObjectPrivilege).String)
+			if !slices.Equal(direct, tt.direct) {
+				t.Errorf("got direct privileges %q, want %q", direct, tt.direct)
+			}
+			effective := mapping(db.Privileges.Granted(tt.grantee, tt.table, true), (*ObjectPrivilege).String)
+			if !slices.Equal(effective, tt.effective) {
+				t.Errorf("got effective privileges %q, want %q", effective, tt.effective)
+			}
+			if got := db.Privileges.IsReadOnly(tt.grantee, tt.table); got != tt.readOnly {
+				t.Errorf("got read only %v, want %v", got, tt.readOnly)
+			}
+		})
+	}
+}
+
+func TestGeneratePrivilegeMatrix(t *testing.T) {
+	db := parseSchema(t, `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY, name VARCHAR2(30));
+CREATE TABLE hr.jobs (id NUMBER PRIMARY KEY);
+GRANT clerk TO app;
+GRANT SELECT, UPDATE (name) ON hr.employees TO app;
+GRANT UPDATE ON hr.employees TO clerk;
+GRANT SELECT, INSERT ON hr.jobs TO clerk;`)
+
+	tests := []struct {
+		name      string
+		format    string
+		effective bool
+		file      string
+		want      string
+	}{
+		{
+			name:   "markdown",
+			format: PrivilegeMatrixMarkdown,
+			file:   "privileges.md",
+			want: `| Table | APP | CLERK |
+| --- | --- | --- |
+| HR.EMPLOYEES | SELECT, UPDATE (NAME) | UPDATE |
+| HR.JOBS |  | SELECT, INSERT |
+`,
+		},
+		{
+			name:      "effective csv",
+			format:    PrivilegeMatrixCSV,
+			effective: true,
+			file:      "privileges.csv",
+			want: `Table,APP,CLERK
+HR.EMPLOYEES,"SELECT, UPDATE",UPDATE
+HR.JOBS,"SELECT, INSERT","SELECT, INSERT"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultPrivilegeMatrixConfig()
+			config.Format = tt.format
+			config.Effective = tt.effective
+			config.Tables = db.Tables
+			config.Privileges = db.Privileges
+			files, err := GeneratePrivilegeMatrix(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := files[tt.file]; got != tt.want {
+				t.Errorf("got\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+
+	config := GetDefaultPrivilegeMatrixConfig()
+	config.Format = "html"
+	if _, err := GeneratePrivilegeMatrix(config); err == nil {
+		t.Errorf("got no error for an unknown format")
+	}
+}
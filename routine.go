@@ -0,0 +1,426 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// createPackageStmt holds the procedures and functions of a package specification,
+// the other declarations and the package body are skipped.
+type createPackageStmt struct {
+	sourceNode
+	Schema   string
+	Name     string
+	Body     bool
+	Routines []*routineSpec
+	// Types are the types the specification declares, parameters of these types are not reported as unknown
+	Types []string
+}
+
+// createRoutineStmt holds the signature of a standalone procedure or function.
+type createRoutineStmt struct {
+	sourceNode
+	Schema  string
+	Routine *routineSpec
+}
+
+// dropRoutineStmt is DROP PACKAGE [BODY], DROP PROCEDURE or DROP FUNCTION.
+type dropRoutineStmt struct {
+	sourceNode
+	// Kind is PACKAGE, PROCEDURE or FUNCTION
+	Kind   string
+	Schema string
+	Name   string
+	Body   bool
+}
+
+type routineSpec struct {
+	Kind       string
+	Name       string
+	Parameters []*parameterSpec
+	Return     *parameterSpec
+	Tokens     []token
+}
+
+// parameterSpec is a parameter as written, Anchor holds the column of "table.column%TYPE".
+type parameterSpec struct {
+	*Parameter
+	Anchor []string
+}
+
+// plsqlIntegerTypes are the PL/SQL subtypes of integers, they map as INTEGER columns.
+var plsqlIntegerTypes = []string{"PLS_INTEGER", "BINARY_INTEGER", "SIMPLE_INTEGER", "NATURAL", "NATURALN", "POSITIVE", "POSITIVEN", "SIGNTYPE"}
+
+// plsqlOnlyTypes have no column type, the generators map them by name.
+var plsqlOnlyTypes = []string{"BOOLEAN", "SYS_REFCURSOR"}
+
+// sizedTypes need a size in a column, a size is added to parameters which are declared without one.
+var sizedTypes = []string{"VARCHAR2", "NVARCHAR2", "VARCHAR", "RAW", "STRING"}
+
+// routineOptions end the signature of a function after its return type.
+var routineOptions = []string{"DETERMINISTIC", "PIPELINED", "PARALLEL_ENABLE", "RESULT_CACHE", "AGGREGATE", "AUTHID", "ACCESSIBLE", "SHARING", "DEFAULT", "SQL_MACRO", "IS", "AS", "USING"}
+
+// parseCreatePackage reads CREATE [OR REPLACE] [EDITIONABLE | NONEDITIONABLE] PACKAGE [schema.]name [AUTHID ...]
+// {IS | AS} declarations END [name]. The procedures and functions declared are kept, CREATE PACKAGE BODY is skipped.
+func parseCreatePackage(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	for r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	if !r.accept("PACKAGE") {
+		return nil, syntaxError(stmt, r.peek(), "expected PACKAGE")
+	}
+	node := &createPackageStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Body = r.accept("BODY")
+	r.accept("IF", "NOT", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	if node.Body {
+		return node, nil
+	}
+	for !r.done() && !r.peek().Is("IS") && !r.peek().Is("AS") {
+		if r.peek().Is("(") {
+			r.group()
+		} else {
+			r.next()
+		}
+	}
+	if !r.accept("IS") && !r.accept("AS") {
+		return nil, syntaxError(stmt, r.peek(), "expected IS or AS")
+	}
+
+	for _, decl := range splitTopLevel(r.tokens[r.pos:], ";") {
+		if len(decl) == 0 {
+			continue
+		}
+		switch {
+		case decl[0].Is("PROCEDURE"), decl[0].Is("FUNCTION"):
+			routine, err := parseRoutineSpec(stmt, decl)
+			if err != nil {
+				return nil, err
+			}
+			node.Routines = append(node.Routines, routine)
+		case (decl[0].Is("TYPE") || decl[0].Is("SUBTYPE")) && len(decl) > 1:
+			node.Types = append(node.Types, decl[1].Value())
+		}
+	}
+	return node, nil
+}
+
+// parseCreateRoutine reads the signature of CREATE [OR REPLACE] [EDITIONABLE | NONEDITIONABLE] {PROCEDURE | FUNCTION}
+// [schema.]name [(parameters)] [RETURN type] ..., the body is skipped.
+func parseCreateRoutine(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	for r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	if !r.peek().Is("PROCEDURE") && !r.peek().Is("FUNCTION") {
+		return nil, syntaxError(stmt, r.peek(), "expected PROCEDURE or FUNCTION")
+	}
+	node := &createRoutineStmt{sourceNode: sourceNode{text: stmt.Source}}
+	kind := r.next()
+	r.accept("IF", "NOT", "EXISTS")
+	if r.pos+1 < len(r.tokens) && r.tokens[r.pos+1].Is(".") {
+		node.Schema = r.next().Value()
+		r.next()
+	}
+	routine, err := parseRoutineSpec(stmt, append([]token{kind}, r.tokens[r.pos:]...))
+	if err != nil {
+		return nil, err
+	}
+	node.Routine = routine
+	return node, nil
+}
+
+// parseRoutineSpec reads {PROCEDURE | FUNCTION} name [(parameters)] [RETURN type], what follows is skipped.
+func parseRoutineSpec(stmt statement, toks []token) (*routineSpec, error) {
+	r := newTokenReader(toks)
+	routine := &routineSpec{Kind: r.next().Value(), Tokens: toks}
+	if !isIdentifier(r.peek()) {
+		return nil, syntaxError(stmt, r.peek(), "expected %v name", strings.ToLower(routine.Kind))
+	}
+	routine.Name = r.next().Value()
+	if r.peek().Is("(") {
+		for _, item := range splitTopLevel(r.group(), ",") {
+			if len(item) == 0 {
+				continue
+			}
+			parameter, err := parseParameter(stmt, item)
+			if err != nil {
+				return nil, err
+			}
+			routine.Parameters = append(routine.Parameters, parameter)
+		}
+	}
+	if routine.Kind == "FUNCTION" {
+		if !r.accept("RETURN") {
+			return nil, syntaxError(stmt, r.peek(), "expected RETURN")
+		}
+		start := r.pos
+		for !r.done() && !slices.ContainsFunc(routineOptions, r.peek().Is) {
+			r.next()
+		}
+		if start == r.pos {
+			return nil, syntaxError(stmt, r.peek(), "expected the return type")
+		}
+		result, err := parseParameterType(stmt, &Parameter{Mode: "OUT"}, r.tokens[start:r.pos])
+		if err != nil {
+			return nil, err
+		}
+		routine.Return = result
+	}
+	return routine, nil
+}
+
+// parseParameter reads "name [IN | OUT | IN OUT] [NOCOPY] type [{:= | DEFAULT} expression]".
+func parseParameter(stmt statement, item []token) (*parameterSpec, error) {
+	r := newTokenReader(item)
+	parameter := &Parameter{Name: r.next().Value(), Mode: "IN"}
+	switch {
+	case r.accept("IN", "OUT"):
+		parameter.Mode = "IN OUT"
+	case r.accept("OUT"):
+		parameter.Mode = "OUT"
+	default:
+		r.accept("IN")
+	}
+	r.accept("NOCOPY")
+	start := r.pos
+	for !r.done() && !r.peek().Is(":=") && !r.peek().Is("DEFAULT") {
+		r.next()
+	}
+	if start == r.pos {
+		return nil, syntaxError(stmt, r.peek(), "expected the type of parameter %v", parameter.Name)
+	}
+	parameter.HasDefault = !r.done()
+	return parseParameterType(stmt, parameter, r.tokens[start:r.pos])
+}
+
+// parseParameterType maps the type of a parameter as the type of a column. PL/SQL integer types map as INTEGER,
+// types without a column type such as BOOLEAN, SYS_REFCURSOR, %ROWTYPE or package types are left without DataType.
+func parseParameterType(stmt statement, parameter *Parameter, toks []token) (*parameterSpec, error) {
+	parameter.Type = clauseText(stmt, toks)
+	spec := &parameterSpec{Parameter: parameter}
+
+	if len(toks) >= 2 && toks[len(toks)-2].Is("%") {
+		if toks[len(toks)-1].Is("TYPE") {
+			for _, t := range toks[:len(toks)-2] {
+				if !t.Is(".") {
+					spec.Anchor = append(spec.Anchor, t.Value())
+				}
+			}
+		}
+		return spec, nil
+	}
+	if len(toks) == 1 && slices.ContainsFunc(plsqlOnlyTypes, toks[0].Is) {
+		return spec, nil
+	}
+	typeToks := toks
+	switch {
+	case len(toks) == 1 && slices.ContainsFunc(plsqlIntegerTypes, toks[0].Is):
+		typeToks = tokenize("INTEGER")
+	case len(toks) == 1 && slices.ContainsFunc(sizedTypes, toks[0].Is):
+		name := toks[0].Value()
+		if name == "STRING" {
+			name = "VARCHAR2"
+		}
+		typeToks = tokenize(name + "(32767)")
+	}
+	item := append([]token{{Kind: tokenWord, Text: "COLUMN_VALUE", Offset: toks[0].Offset, Line: toks[0].Line, Column: toks[0].Column}}, typeToks...)
+	columns, err := parseTypeColumns(stmt, [][]token{item})
+	if err != nil {
+		return nil, syntaxError(stmt, toks[0], "invalid type %v of %v", parameter.Type, parameterName(parameter))
+	}
+	parameter.DataType = columns[0].DataType
+	return spec, nil
+}
+
+func parameterName(parameter *Parameter) string {
+	if parameter.Name == "" {
+		return "the function result"
+	}
+	return "parameter " + parameter.Name
+}
+
+// parseDropRoutine reads DROP PACKAGE [BODY] name, DROP PROCEDURE name and DROP FUNCTION name.
+func parseDropRoutine(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	if !r.accept("DROP") {
+		return nil, syntaxError(stmt, r.peek(), "expected DROP")
+	}
+	if !r.peek().Is("PACKAGE") && !r.peek().Is("PROCEDURE") && !r.peek().Is("FUNCTION") {
+		return nil, syntaxError(stmt, r.peek(), "expected PACKAGE, PROCEDURE or FUNCTION")
+	}
+	node := &dropRoutineStmt{sourceNode: sourceNode{text: stmt.Source}, Kind: r.next().Value()}
+	node.Body = node.Kind == "PACKAGE" && r.accept("BODY")
+	r.accept("IF", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+// createdRoutine returns PACKAGE, PROCEDURE or FUNCTION for the leading words of CREATE PACKAGE [BODY],
+// CREATE PROCEDURE and CREATE FUNCTION, an empty string for other statements.
+func createdRoutine(words []string) string {
+	if len(words) == 0 || words[0] != "CREATE" {
+		return ""
+	}
+	for _, word := range words[1:] {
+		switch word {
+		case "PACKAGE", "PROCEDURE", "FUNCTION":
+			return word
+		case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// createPackage adds the package, a package created again replaces the former one.
+func (b *schemaBuilder) createPackage(stmt statement, node *createPackageStmt) {
+	if node.Body {
+		return
+	}
+	schema := node.Schema
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	pkg := &Package{Schema: schema, Name: node.Name, Routines: []*Routine{}, Span: stmt.span()}
+	for _, spec := range node.Routines {
+		routine := b.routine(stmt, schema, node.Name, spec, node.Types)
+		pkg.Routines = append(pkg.Routines, routine)
+	}
+	b.packages[pkg.QualifiedName()] = pkg
+	b.declared[pkg] = stmt.Index
+}
+
+// createRoutine adds a standalone procedure or function, it replaces a former one of that name.
+func (b *schemaBuilder) createRoutine(stmt statement, node *createRoutineStmt) {
+	schema := node.Schema
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	routine := b.routine(stmt, schema, "", node.Routine, nil)
+	b.routines[routine.QualifiedName()] = routine
+	b.declared[routine] = stmt.Index
+}
+
+// routine resolves the types of the parameters, %TYPE takes the data type of the column it names.
+// Parameters of unknown types are reported unless the package declares the type.
+func (b *schemaBuilder) routine(stmt statement, schema, pkg string, spec *routineSpec, packageTypes []string) *Routine {
+	routine := &Routine{
+		Schema:     schema,
+		Package:    pkg,
+		Name:       spec.Name,
+		Kind:       spec.Kind,
+		Parameters: []*Parameter{},
+		Span:       spanOf(stmt.File, spec.Tokens),
+	}
+	params := slices.Clone(spec.Parameters)
+	if spec.Return != nil {
+		params = append(params, spec.Return)
+	}
+	for _, param := range params {
+		if param.Anchor != nil {
+			b.anchorParameter(stmt, routine, param)
+		} else if datatype, ok := param.DataType.(*UserDatatype); ok {
+			userType, ok := lookupThroughSynonyms(b, b.types, datatype.Schema, datatype.Name)
+			switch {
+			case ok:
+				datatype.Type = userType
+			case !slices.Contains(packageTypes, datatype.Name) || datatype.Schema != "" && datatype.Schema != pkg:
+				b.diags.warnf(stmt, "%v of %v has unknown type %v", parameterName(param.Parameter), routine.QualifiedName(), datatype)
+			}
+		}
+		if param == spec.Return {
+			routine.Return = param.Parameter
+		} else {
+			routine.Parameters = append(routine.Parameters, param.Parameter)
+		}
+	}
+	return routine
+}
+
+// anchorParameter gives a parameter declared as "[schema.]table.column%TYPE" the data type of the column,
+// anchors to variables are left without DataType.
+func (b *schemaBuilder) anchorParameter(stmt statement, routine *Routine, param *parameterSpec) {
+	var schema, table, column string
+	switch len(param.Anchor) {
+	case 2:
+		table, column = param.Anchor[0], param.Anchor[1]
+	case 3:
+		schema, table, column = param.Anchor[0], param.Anchor[1], param.Anchor[2]
+	default:
+		return
+	}
+	relation, ok := b.relation(schema, table)
+	if !ok {
+		return
+	}
+	col := relation.getColumn(column)
+	if col == nil {
+		b.diags.warnf(stmt, "%v of %v has the type of unknown column %v.%v", parameterName(param.Parameter), routine.QualifiedName(), table, column)
+		return
+	}
+	param.DataType = col.DataType
+}
+
+func (b *schemaBuilder) dropRoutine(stmt statement, node *dropRoutineStmt) {
+	if node.Body {
+		return
+	}
+	if node.Kind == "PACKAGE" {
+		pkg, ok := lookupQualified(b.packages, b.defaultSchema, node.Schema, node.Name)
+		if !ok {
+			b.diags.warnf(stmt, "drop of unknown package %v ignored", qualifiedName(node.Schema, node.Name))
+			return
+		}
+		delete(b.packages, pkg.QualifiedName())
+		return
+	}
+	routine, ok := lookupQualified(b.routines, b.defaultSchema, node.Schema, node.Name)
+	if !ok || routine.Kind != node.Kind {
+		b.diags.warnf(stmt, "drop of unknown %v %v ignored", strings.ToLower(node.Kind), qualifiedName(node.Schema, node.Name))
+		return
+	}
+	delete(b.routines, routine.QualifiedName())
+}
+
+func (b *schemaBuilder) packageList() []*Package {
+	packages := maps.Values(b.packages)
+	slices.SortFunc(packages, func(x, y *Package) int { return b.declared[x] - b.declared[y] })
+	return packages
+}
+
+func (b *schemaBuilder) routineList() []*Routine {
+	routines := maps.Values(b.routines)
+	slices.SortFunc(routines, func(x, y *Routine) int { return b.declared[x] - b.declared[y] })
+	return routines
+}
+
+// QualifiedName identifies the package by schema and name.
+func (p *Package) QualifiedName() string {
+	return qualifiedName(p.Schema, p.Name)
+}
+
+// QualifiedName is the name the routine is called by, e.g. "APP.ORDER_API.PLACE_ORDER".
+func (r *Routine) QualifiedName() string {
+	if r.Package == "" {
+		return qualifiedName(r.Schema, r.Name)
+	}
+	return qualifiedName(r.Schema, r.Package) + "." + r.Name
+}
+
+// IsFunction reports whether the routine returns a result.
+func (r *Routine) IsFunction() bool {
+	return r.Return != nil
+}
+
+// IsOut reports whether the routine passes a value back through the parameter.
+func (p *Parameter) IsOut() bool {
+	return p.Mode == "OUT" || p.Mode == "IN OUT"
+}
@@ -0,0 +1,450 @@
+package ddlcode
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	Kind   tokenKind
+	Text   string
+	Offset int
+	Line   int
+	Column int
+}
+
+// Value returns the identifier or the literal without quotes.
+func (t token) Value() string {
+	switch t.Kind {
+	case tokenQuotedIdent:
+		return strings.ReplaceAll(t.Text[1:len(t.Text)-1], `""`, `"`)
+	case tokenString:
+		text := t.Text
+		if len(text) > 0 && (text[0] == 'n' || text[0] == 'N') {
+			text = text[1:]
+		}
+		if len(text) > 2 && (text[0] == 'q' || text[0] == 'Q') {
+			return text[3 : len(text)-2]
+		}
+		if len(text) < 2 {
+			return text
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'")
+	case tokenWord:
+		// unquoted identifiers are case insensitive, Oracle folds them to upper case
+		return strings.ToUpper(t.Text)
+	}
+	return t.Text
+}
+
+// Is reports whether the token is the given keyword or punctuation, keywords are case insensitive.
+func (t token) Is(s string) bool {
+	switch t.Kind {
+	case tokenWord:
+		return strings.EqualFold(t.Text, s)
+	case tokenPunct:
+		return t.Text == s
+	}
+	return false
+}
+
+func (t token) End() int {
+	return t.Offset + len(t.Text)
+}
+
+// endPosition returns the line and the column just after the token.
+func (t token) endPosition() (line, column int) {
+	if i := strings.LastIndexByte(t.Text, '\n'); i >= 0 {
+		return t.Line + strings.Count(t.Text, "\n"), len(t.Text) - i
+	}
+	return t.Line, t.Column + len(t.Text)
+}
+
+// spanOf returns the span from the first to the last token.
+func spanOf(file string, tokens []token) SourceSpan {
+	if len(tokens) == 0 {
+		return SourceSpan{}
+	}
+	span := SourceSpan{File: file, Line: tokens[0].Line, Column: tokens[0].Column}
+	span.EndLine, span.EndColumn = tokens[len(tokens)-1].endPosition()
+	return span
+}
+
+var multiCharPuncts = []string{"||", ":=", "=>", "<=", ">=", "<>", "!=", "^=", ".."}
+
+// tokenize splits Oracle SQL text into tokens, comments and whitespace are dropped.
+func tokenize(sql string) []token {
+	tokens := []token{}
+	line, lineStart := 1, 0
+	i := 0
+	newToken := func(kind tokenKind, start int) {
+		tokens = append(tokens, token{
+			Kind:   kind,
+			Text:   sql[start:i],
+			Offset: start,
+			Line:   line,
+			Column: start - lineStart + 1,
+		})
+	}
+	// advance moves i to end and keeps track of line breaks in between
+	advance := func(end int) {
+		for ; i < end && i < len(sql); i++ {
+			if sql[i] == '\n' {
+				line += 1
+				lineStart = i + 1
+			}
+		}
+	}
+
+	for i < len(sql) {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			advance(i + 1)
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			advance(i + end)
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				advance(len(sql))
+			} else {
+				advance(i + 2 + end + 2)
+			}
+		case isQuoteLiteralStart(sql[i:]):
+			start, startLine, startColumn := i, line, i-lineStart+1
+			prefix := 0
+			if c == 'n' || c == 'N' {
+				prefix = 1
+			}
+			var end int
+			if sql[i+prefix] == '\'' {
+				end = scanQuoted(sql, i+prefix, '\'')
+			} else {
+				end = scanAlternativeQuoted(sql, i+prefix)
+			}
+			advance(end)
+			tokens = append(tokens, token{Kind: tokenString, Text: sql[start:i], Offset: start, Line: startLine, Column: startColumn})
+		case c == '"':
+			start, startLine, startColumn := i, line, i-lineStart+1
+			advance(scanQuoted(sql, i, '"'))
+			tokens = append(tokens, token{Kind: tokenQuotedIdent, Text: sql[start:i], Offset: start, Line: startLine, Column: startColumn})
+		case isWordStart(c):
+			start := i
+			for i < len(sql) && isWordPart(sql[i]) {
+				i++
+			}
+			newToken(tokenWord, start)
+		case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			start := i
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+			if i+1 < len(sql) && sql[i] == '.' && sql[i+1] != '.' {
+				i++
+				for i < len(sql) && isDigit(sql[i]) {
+					i++
+				}
+			}
+			if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+				j := i + 1
+				if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+					j++
+				}
+				if j < len(sql) && isDigit(sql[j]) {
+					for i = j; i < len(sql) && isDigit(sql[i]); i++ {
+					}
+				}
+			}
+			newToken(tokenNumber, start)
+		default:
+			start := i
+			i++
+			for _, p := range multiCharPuncts {
+				if strings.HasPrefix(sql[start:], p) {
+					i = start + len(p)
+					break
+				}
+			}
+			newToken(tokenPunct, start)
+		}
+	}
+	return tokens
+}
+
+func isQuoteLiteralStart(s string) bool {
+	if s[0] == '\'' {
+		return true
+	}
+	if s[0] == 'n' || s[0] == 'N' {
+		s = s[1:]
+		if len(s) > 0 && s[0] == '\'' {
+			return true
+		}
+	}
+	return len(s) > 2 && (s[0] == 'q' || s[0] == 'Q') && s[1] == '\''
+}
+
+// scanQuoted returns the offset after the closing quote, doubled quotes are escapes.
+func scanQuoted(sql string, start int, quote byte) int {
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+// scanAlternativeQuoted handles q'[...]' literals.
+func scanAlternativeQuoted(sql string, start int) int {
+	delimiter := sql[start+2]
+	switch delimiter {
+	case '[':
+		delimiter = ']'
+	case '{':
+		delimiter = '}'
+	case '(':
+		delimiter = ')'
+	case '<':
+		delimiter = '>'
+	}
+	end := strings.Index(sql[start+3:], string(delimiter)+"'")
+	if end < 0 {
+		return len(sql)
+	}
+	return start + 3 + end + 2
+}
+
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$' || c == '#'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitStatements splits a script into statements. Statements end with ';',
+// PL/SQL units end with a line holding a single '/' like in SQL*Plus.
+func splitStatements(sql string) []statement {
+	stmts := []statement{}
+	tokens := tokenize(sql)
+
+	isSlashLine := func(i int) bool {
+		t := tokens[i]
+		if !t.Is("/") {
+			return false
+		}
+		if i > 0 && tokens[i-1].Line == t.Line {
+			return false
+		}
+		return i+1 >= len(tokens) || tokens[i+1].Line > t.Line
+	}
+
+	for i := 0; i < len(tokens); {
+		if tokens[i].Is(";") || isSlashLine(i) {
+			i++
+			continue
+		}
+
+		start := i
+		block := isPlsqlUnit(tokens[start:])
+		end := len(tokens)
+		next := len(tokens)
+		for j := start; j < len(tokens); j++ {
+			if block && isSlashLine(j) {
+				end, next = j, j+1
+				break
+			}
+			if !block && tokens[j].Is(";") {
+				end, next = j, j+1
+				break
+			}
+		}
+
+		source := sql[tokens[start].Offset:tokens[end-1].End()]
+		stmts = append(stmts, statement{
+			Index:  len(stmts),
+			Source: source,
+			Tokens: tokens[start:end],
+			Offset: tokens[start].Offset,
+			Line:   tokens[start].Line,
+			Column: tokens[start].Column,
+		})
+		i = next
+	}
+	return stmts
+}
+
+// isPlsqlUnit reports whether the statement carries PL/SQL code which may contain ';'.
+func isPlsqlUnit(tokens []token) bool {
+	words := leadingWords(tokens, 6)
+	if len(words) == 0 {
+		return false
+	}
+	if words[0] == "DECLARE" || words[0] == "BEGIN" {
+		return true
+	}
+	if words[0] != "CREATE" {
+		return false
+	}
+	words = words[1:]
+	if len(words) >= 2 && words[0] == "OR" && words[1] == "REPLACE" {
+		words = words[2:]
+	}
+	if len(words) > 0 && (words[0] == "EDITIONABLE" || words[0] == "NONEDITIONABLE" || words[0] == "EDITIONING") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "PROCEDURE", "FUNCTION", "PACKAGE", "TRIGGER", "LIBRARY":
+		return true
+	case "TYPE":
+		return len(words) > 1 && words[1] == "BODY"
+	}
+	return false
+}
+
+// leadingWords returns up to n upper-cased keywords at the beginning of the statement.
+func leadingWords(tokens []token, n int) []string {
+	words := []string{}
+	for _, t := range tokens {
+		if len(words) >= n || t.Kind != tokenWord {
+			break
+		}
+		words = append(words, strings.ToUpper(t.Text))
+	}
+	return words
+}
+
+type tokenReader struct {
+	tokens []token
+	pos    int
+}
+
+func newTokenReader(tokens []token) *tokenReader {
+	return &tokenReader{tokens: tokens}
+}
+
+func (r *tokenReader) done() bool {
+	return r.pos >= len(r.tokens)
+}
+
+func (r *tokenReader) peek() token {
+	if r.done() {
+		return token{Kind: tokenPunct}
+	}
+	return r.tokens[r.pos]
+}
+
+func (r *tokenReader) next() token {
+	t := r.peek()
+	r.pos += 1
+	return t
+}
+
+// accept consumes the given keywords when all of them are next in the stream.
+func (r *tokenReader) accept(keywords ...string) bool {
+	if r.pos+len(keywords) > len(r.tokens) {
+		return false
+	}
+	for i, k := range keywords {
+		if !r.tokens[r.pos+i].Is(k) {
+			return false
+		}
+	}
+	r.pos += len(keywords)
+	return true
+}
+
+// group consumes a parenthesized group and returns the tokens inside of it.
+func (r *tokenReader) group() []token {
+	if !r.peek().Is("(") {
+		return nil
+	}
+	start := r.pos + 1
+	depth := 0
+	for !r.done() {
+		t := r.next()
+		if t.Is("(") {
+			depth += 1
+		} else if t.Is(")") {
+			depth -= 1
+			if depth == 0 {
+				return r.tokens[start : r.pos-1]
+			}
+		}
+	}
+	return r.tokens[start:]
+}
+
+// qualifiedName consumes "name" or "schema.name".
+func (r *tokenReader) qualifiedName() (schema, name string) {
+	name = r.next().Value()
+	if r.peek().Is(".") {
+		r.next()
+		schema, name = name, r.next().Value()
+	}
+	return
+}
+
+// splitTopLevel splits tokens by the separator outside of parentheses.
+func splitTopLevel(tokens []token, separator string) [][]token {
+	parts := [][]token{}
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		switch {
+		case t.Is("("):
+			depth += 1
+		case t.Is(")"):
+			depth -= 1
+		case depth == 0 && t.Is(separator):
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// blankTokens replaces the text of the tokens with spaces, line breaks are kept
+// so positions reported by the oracle parser still match the original source.
+func blankTokens(source string, base int, tokens []token) string {
+	if len(tokens) == 0 {
+		return source
+	}
+	start := tokens[0].Offset - base
+	end := tokens[len(tokens)-1].End() - base
+	b := []byte(source)
+	for i := start; i < end; i++ {
+		if b[i] != '\n' && b[i] != '\r' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
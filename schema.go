@@ -0,0 +1,864 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// schemaBuilder applies the statements of a script in order, the tables end up in the state
+// after the last statement.
+type schemaBuilder struct {
+	diags *diagnostics
+	// defaultSchema is the schema of unqualified names, tables and views are keyed by their qualified name
+	defaultSchema string
+	tables        map[string]*Table
+	views         map[string]*View
+	// pending are foreign keys to tables created later in the script, resolved when the table is created
+	pending []pendingReference
+	// declared holds the index of the statement creating each table and view
+	declared map[any]int
+	triggers []*Trigger
+	// synonyms are keyed by their qualified name, public synonyms belong to the PUBLIC schema
+	synonyms map[string]*Synonym
+	types    map[string]*UserType
+	// privileges and roleGrants are the grants not revoked
+	privileges []*ObjectPrivilege
+	roleGrants []*RoleGrant
+	packages   map[string]*Package
+	// routines are the standalone procedures and functions
+	routines map[string]*Routine
+	// sequences are those created so far, sequenceLinks the columns filled from a sequence
+	sequences     []*Sequence
+	sequenceLinks []sequenceLink
+}
+
+type pendingReference struct {
+	stmt  statement
+	table *Table
+	spec  *ast.OutOfLineConstraint
+	span  SourceSpan
+}
+
+func newSchemaBuilder(diags *diagnostics, defaultSchema string) *schemaBuilder {
+	return &schemaBuilder{diags: diags, defaultSchema: defaultSchema, tables: map[string]*Table{}, views: map[string]*View{}, declared: map[any]int{}, synonyms: map[string]*Synonym{}, types: map[string]*UserType{},
+		packages: map[string]*Package{}, routines: map[string]*Routine{}}
+}
+
+// key returns the qualified name of a table or a view, unqualified names belong to the default schema.
+func (b *schemaBuilder) key(schema, name string) string {
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	return qualifiedName(schema, name)
+}
+
+// lookupTable finds a table by its name or by a synonym of it.
+func (b *schemaBuilder) lookupTable(schema, name string) (*Table, bool) {
+	return lookupThroughSynonyms(b, b.tables, schema, name)
+}
+
+func (b *schemaBuilder) lookupView(schema, name string) (*View, bool) {
+	return lookupThroughSynonyms(b, b.views, schema, name)
+}
+
+// lookupQualified finds an entry by its qualified name. Without a default schema the schema of unqualified
+// names is unknown, then a qualified name matches an unqualified entry and an unqualified name matches
+// the only entry of that name in any schema.
+func lookupQualified[T any](m map[string]T, defaultSchema, schema, name string) (T, bool) {
+	if schema == "" {
+		schema = defaultSchema
+	}
+	if v, ok := m[qualifiedName(schema, name)]; ok {
+		return v, true
+	}
+	var found T
+	if defaultSchema != "" {
+		return found, false
+	}
+	if schema != "" {
+		found, ok := m[name]
+		return found, ok
+	}
+	count := 0
+	for key, v := range m {
+		if _, rest, ok := strings.Cut(key, "."); ok && rest == name {
+			found = v
+			count += 1
+		}
+	}
+	if count != 1 {
+		var zero T
+		return zero, false
+	}
+	return found, true
+}
+
+// unknownTable describes a table or a view which is not found for a warning. An unqualified name matching
+// relations of several schemas is ambiguous without a default schema, their schemas are listed.
+func (b *schemaBuilder) unknownTable(schema, name string) string {
+	if schema != "" || b.defaultSchema != "" {
+		return "unknown table " + qualifiedName(schema, name)
+	}
+	schemas := []string{}
+	for _, key := range append(maps.Keys(b.tables), maps.Keys(b.views)...) {
+		if s, rest, ok := strings.Cut(key, "."); ok && rest == name && !slices.Contains(schemas, s) {
+			schemas = append(schemas, s)
+		}
+	}
+	if len(schemas) < 2 {
+		return "unknown table " + name
+	}
+	slices.Sort(schemas)
+	return fmt.Sprintf("ambiguous table %v (schemas %v: qualify the name or set ParseOptions.DefaultSchema)", name, strings.Join(schemas, ", "))
+}
+
+func (b *schemaBuilder) apply(stmt statement) {
+	switch node := stmt.Node.(type) {
+	case *ast.CreateTableStmt:
+		b.createTable(stmt, node)
+	case *createIndexStmt:
+		b.createIndex(stmt, node)
+	case *createSequenceStmt:
+		b.createSequence(node)
+	case *alterTableStmt:
+		b.alterTable(stmt, node)
+	case *ast.CommentStmt:
+		b.comment(stmt, node)
+	case *renameStmt:
+		if _, ok := lookupQualified(b.tables, b.defaultSchema, "", node.Name); !ok {
+			if synonym := b.synonyms[b.key("", node.Name)]; synonym != nil {
+				delete(b.synonyms, b.key("", node.Name))
+				synonym.Name = node.NewName
+				b.synonyms[b.key("", node.NewName)] = synonym
+				return
+			}
+		}
+		table, ok := b.lookupTable("", node.Name)
+		if !ok {
+			b.diags.warnf(stmt, "rename of %v ignored", b.unknownTable("", node.Name))
+			return
+		}
+		b.renameTable(stmt, table, node.NewName, node.Quoted)
+	case *dropTableStmt:
+		// a table is not dropped through a synonym
+		table, ok := lookupQualified(b.tables, b.defaultSchema, node.Schema, node.Name)
+		if !ok {
+			b.diags.warnf(stmt, "drop of %v ignored", b.unknownTable(node.Schema, node.Name))
+			return
+		}
+		b.dropTable(table)
+	case *dropIndexStmt:
+		b.dropIndex(stmt, node)
+	case *createViewStmt:
+		b.createView(stmt, node)
+	case *createTriggerStmt:
+		b.createTrigger(stmt, node)
+	case *dropTriggerStmt:
+		trigger := b.lookupTrigger(node.Schema, node.Name)
+		if trigger == nil {
+			b.diags.warnf(stmt, "drop of unknown trigger %v ignored", qualifiedName(node.Schema, node.Name))
+			return
+		}
+		b.dropTrigger(trigger)
+	case *createSynonymStmt:
+		b.createSynonym(stmt, node)
+	case *dropSynonymStmt:
+		b.dropSynonym(stmt, node)
+	case *grantStmt:
+		b.grant(stmt, node)
+	case *createTypeStmt:
+		b.createType(stmt, node)
+	case *dropTypeStmt:
+		b.dropType(stmt, node)
+	case *createPackageStmt:
+		b.createPackage(stmt, node)
+	case *createRoutineStmt:
+		b.createRoutine(stmt, node)
+	case *dropRoutineStmt:
+		b.dropRoutine(stmt, node)
+	case *dropViewStmt:
+		view, ok := b.lookupView(node.Schema, node.Name)
+		if !ok {
+			b.diags.warnf(stmt, "drop of unknown view %v ignored", qualifiedName(node.Schema, node.Name))
+			return
+		}
+		b.dropPrivileges(view.AsTable())
+		delete(b.views, qualifiedName(view.Schema, view.Name))
+	}
+}
+
+func (b *schemaBuilder) createTable(stmt statement, ct *ast.CreateTableStmt) {
+	table := translateTable(ct, stmt.Clauses.Columns)
+	table.Span = stmt.span()
+	if table.Schema == "" {
+		table.Schema = b.defaultSchema
+		for _, col := range table.Columns {
+			col.Schema = b.defaultSchema
+		}
+	}
+	if existing, ok := b.tables[table.QualifiedName()]; ok {
+		b.diags.warnf(stmt, "table %v created again, the former definition is dropped", table.QualifiedName())
+		b.dropTable(existing)
+	}
+	b.tables[table.QualifiedName()] = table
+	b.declared[table] = stmt.Index
+	b.resolveUserTypes(stmt, table.Columns)
+	b.addProperties(stmt, table, table.Columns, ct, stmt.Clauses)
+	b.addTableProperties(stmt, table, stmt.Clauses.Properties)
+	b.linkDefaults(stmt, table.Columns, stmt.Clauses.Columns)
+	b.resolvePending()
+}
+
+// addTableProperties sets the organization, the tablespace, the storage and the partitioning of a created table.
+func (b *schemaBuilder) addTableProperties(stmt statement, table *Table, props tableProperties) {
+	table.Engine = "HEAP"
+	if props.Organization != "" {
+		table.Engine = props.Organization
+	}
+	if props.Temporary != "" {
+		table.Type = strings.ToLower(props.Temporary) + " temporary table"
+	}
+	table.Tablespace = props.Tablespace
+	if len(props.Storage) > 0 {
+		table.Storage = props.Storage
+	}
+	if props.Partitioning != nil {
+		table.Partitioning = b.partitioning(stmt, table, props.Partitioning)
+	}
+}
+
+func (b *schemaBuilder) partitioning(stmt statement, table *Table, def *partitionDef) *Partitioning {
+	partitioning := &Partitioning{
+		Method:     def.Method,
+		Columns:    []*Column{},
+		Interval:   def.Interval,
+		Count:      def.Count,
+		Partitions: def.Partitions,
+	}
+	// reference partitioning names a foreign key instead of columns
+	if def.Method != "REFERENCE" {
+		for _, name := range def.Columns {
+			col := table.getColumn(name)
+			if col == nil {
+				b.diags.warnf(stmt, "unknown partition key column %v.%v ignored", table.Table, name)
+				continue
+			}
+			partitioning.Columns = append(partitioning.Columns, col)
+		}
+	}
+	if def.Sub != nil {
+		partitioning.Subpartitioning = b.partitioning(stmt, table, def.Sub)
+	}
+	return partitioning
+}
+
+// addProperties adds the constraints of CREATE TABLE or of ALTER TABLE ADD to the table,
+// columns are the columns defined by the statement.
+func (b *schemaBuilder) addProperties(stmt statement, table *Table, columns []*Column, ct *ast.CreateTableStmt, clauses *tableClauses) {
+	for _, col := range columns {
+		if clause, ok := clauses.Columns[col.Name]; ok {
+			for _, spec := range clause.References {
+				b.addForeignKey(stmt, table, spec, clauses.Spans[spec])
+			}
+		}
+	}
+	for _, def := range ct.RelTable.TableStructs {
+		spec, ok := def.(*ast.OutOfLineConstraint)
+		if !ok {
+			continue
+		}
+		switch spec.Type {
+		case ast.ConstraintTypePK:
+			b.addPrimaryKey(stmt, table, spec)
+		case ast.ConstraintTypeUnique:
+			b.addUniqueConstraint(stmt, table, spec, clauses.Spans[spec])
+		case ast.ConstraintTypeReferences:
+			b.addForeignKey(stmt, table, spec, clauses.Spans[spec])
+		default:
+			b.diags.warnf(stmt, "unsupported constraint %v on table %v ignored", constraintName(spec), table.Table)
+		}
+	}
+	for _, col := range columns {
+		if clause, ok := clauses.Columns[col.Name]; ok {
+			b.addCheckConstraints(stmt, table, clause.Checks)
+		}
+	}
+	b.addCheckConstraints(stmt, table, clauses.Checks)
+}
+
+func (b *schemaBuilder) addPrimaryKey(stmt statement, table *Table, spec *ast.OutOfLineConstraint) {
+	cols := []*Column{}
+	for _, k := range spec.Columns {
+		col := table.getColumn(k.Value)
+		if col == nil {
+			b.diags.warnf(stmt, "primary key %v ignored: unknown column: %v.%v", constraintName(spec), table.Table, k.Value)
+			return
+		}
+		cols = append(cols, col)
+	}
+	table.dropPrimaryKey()
+	for _, col := range cols {
+		col.Attribute[ast.ConstraintTypePK] = nil
+	}
+	table.PrimaryKey = cols
+	if spec.Name != nil {
+		table.PrimaryKeyName = spec.Name.Value
+	}
+}
+
+func (b *schemaBuilder) addForeignKey(stmt statement, table *Table, spec *ast.OutOfLineConstraint, span SourceSpan) {
+	refTable, ok := b.lookupTable(referenceName(spec))
+	if !ok {
+		b.pending = append(b.pending, pendingReference{stmt: stmt, table: table, spec: spec, span: span})
+		return
+	}
+	fk, err := translateForeignKey(table, refTable, spec)
+	if err != nil {
+		b.diags.warnAt(stmt, span, "foreign key %v: %v", constraintName(spec), err)
+		return
+	}
+	fk.Span = span
+	assignRefColumns(fk)
+}
+
+// resolvePending resolves the foreign keys waiting for a table created or named by a synonym
+// after they were declared.
+func (b *schemaBuilder) resolvePending() {
+	pending := b.pending
+	b.pending = nil
+	for _, p := range pending {
+		if _, ok := b.lookupTable(referenceName(p.spec)); !ok {
+			b.pending = append(b.pending, p)
+			continue
+		}
+		if b.tables[p.table.QualifiedName()] == p.table {
+			b.addForeignKey(p.stmt, p.table, p.spec, p.span)
+		}
+	}
+}
+
+// reportPending warns about the foreign keys to tables never created.
+func (b *schemaBuilder) reportPending() {
+	for _, p := range b.pending {
+		b.diags.warnAt(p.stmt, p.span, "foreign key %v references %v", constraintName(p.spec), b.unknownTable(referenceName(p.spec)))
+	}
+	b.pending = nil
+}
+
+func (b *schemaBuilder) addUniqueConstraint(stmt statement, table *Table, spec *ast.OutOfLineConstraint, span SourceSpan) {
+	uc, err := translateUniqueConstraint(table, spec)
+	if err != nil {
+		b.diags.warnAt(stmt, span, "unique constraint %v ignored: %v", constraintName(spec), err)
+		return
+	}
+	uc.Span = span
+	table.UniqueConstraints = append(table.UniqueConstraints, uc)
+}
+
+func (b *schemaBuilder) addCheckConstraints(stmt statement, table *Table, defs []checkDef) {
+	for _, def := range defs {
+		check, err := translateCheck(table, def)
+		if err != nil {
+			b.diags.warnAt(stmt, def.Span, "check constraint %v ignored: %v", def.Name, err)
+			continue
+		}
+		check.Span = def.Span
+		table.CheckConstraints = append(table.CheckConstraints, check)
+		for _, col := range check.Columns {
+			col.Checks = append(col.Checks, check)
+		}
+	}
+}
+
+func (b *schemaBuilder) createIndex(stmt statement, node *createIndexStmt) {
+	table, ok := b.lookupTable(node.TableSchema, node.Table)
+	if !ok {
+		b.diags.warnf(stmt, "index %v is on %v", node.Name, b.unknownTable(node.TableSchema, node.Table))
+		return
+	}
+	index, err := translateIndex(table, node)
+	if err != nil {
+		b.diags.warnf(stmt, "index %v ignored: %v", node.Name, err)
+		return
+	}
+	index.Span = stmt.span()
+	table.Indexes = append(table.Indexes, index)
+}
+
+// dropIndex finds the index by the rules of lookupQualified, unqualified indexes belong to the default schema.
+// A name matching indexes of several schemas, or of several tables without a schema, is ambiguous.
+func (b *schemaBuilder) dropIndex(stmt statement, node *dropIndexStmt) {
+	// indexes are those of the name keyed by their qualified name
+	indexes := map[string][]*Index{}
+	tableOf := map[*Index]*Table{}
+	for _, table := range b.tables {
+		for _, index := range table.Indexes {
+			if index.Name == node.Name {
+				key := b.key(index.Schema, index.Name)
+				indexes[key] = append(indexes[key], index)
+				tableOf[index] = table
+			}
+		}
+	}
+	found, ok := lookupQualified(indexes, b.defaultSchema, node.Schema, node.Name)
+	switch {
+	case !ok && node.Schema == "" && b.defaultSchema == "" && len(indexes) > 1:
+		schemas := []string{}
+		for key := range indexes {
+			schema, _, _ := strings.Cut(key, ".")
+			schemas = append(schemas, schema)
+		}
+		slices.Sort(schemas)
+		b.diags.warnf(stmt, "drop of ambiguous index %v ignored (schemas %v: qualify the name or set ParseOptions.DefaultSchema)", node.Name, strings.Join(schemas, ", "))
+		return
+	case !ok:
+		b.diags.warnf(stmt, "drop of unknown index %v ignored", qualifiedName(node.Schema, node.Name))
+		return
+	case len(found) > 1:
+		names := mapping(found, func(index *Index) string { return tableOf[index].QualifiedName() })
+		slices.Sort(names)
+		b.diags.warnf(stmt, "drop of ambiguous index %v ignored (on tables %v: qualify the index names where they are created)", node.Name, strings.Join(names, ", "))
+		return
+	}
+	table := tableOf[found[0]]
+	table.Indexes = slices.DeleteFunc(table.Indexes, func(index *Index) bool { return index == found[0] })
+}
+
+func (b *schemaBuilder) comment(stmt statement, node *ast.CommentStmt) {
+	table, ok := b.relation(tableName(node.TableName))
+	if !ok {
+		b.diags.warnf(stmt, "comment on %v ignored", b.unknownTable(tableName(node.TableName)))
+		return
+	}
+	switch node.Type {
+	case ast.CommentOnTable:
+		table.Comment = node.Comment
+	case ast.CommentOnColumn:
+		col := table.getColumn(node.ColumnName.Value)
+		if col == nil {
+			b.diags.warnf(stmt, "comment on unknown column %v.%v ignored", table.Table, node.ColumnName.Value)
+			return
+		}
+		col.Comment = node.Comment
+	}
+}
+
+func (b *schemaBuilder) alterTable(stmt statement, node *alterTableStmt) {
+	table, ok := b.lookupTable(node.TableSchema, node.Table)
+	if !ok {
+		b.diags.warnf(stmt, "alter of %v ignored", b.unknownTable(node.TableSchema, node.Table))
+		return
+	}
+	for _, clause := range node.Clauses {
+		switch clause := clause.(type) {
+		case *addClause:
+			b.addColumns(stmt, table, clause)
+		case *modifyClause:
+			for _, mc := range clause.Columns {
+				b.modifyColumn(stmt, table, mc)
+			}
+		case *ast.DropColumnClause:
+			for _, name := range clause.Columns {
+				col := table.getColumn(name.Value)
+				if col == nil {
+					b.diags.warnf(stmt, "drop of unknown column %v.%v ignored", table.Table, name.Value)
+					continue
+				}
+				b.dropColumn(table, col)
+			}
+		case *ast.RenameColumnClause:
+			col := table.getColumn(clause.OldName.Value)
+			if col == nil {
+				b.diags.warnf(stmt, "rename of unknown column %v.%v ignored", table.Table, clause.OldName.Value)
+				continue
+			}
+			if table.getColumn(clause.NewName.Value) != nil {
+				b.diags.warnf(stmt, "column %v.%v already exists, rename ignored", table.Table, clause.NewName.Value)
+				continue
+			}
+			for _, check := range table.CheckConstraints {
+				if slices.Contains(check.Columns, col) {
+					check.renameColumn(col.Name, clause.NewName.Value, isQuoted(clause.NewName))
+				}
+			}
+			col.Name, col.Quoted = clause.NewName.Value, isQuoted(clause.NewName)
+		case *ast.DropConstraintClause:
+			b.dropConstraint(stmt, table, clause.Constraint)
+		case *ast.RenameConstraintClause:
+			if !table.renameConstraint(clause.OldName.Value, clause.NewName.Value) {
+				b.diags.warnf(stmt, "rename of unknown constraint %v on table %v ignored", clause.OldName.Value, table.Table)
+			}
+		case *renameTableClause:
+			b.renameTable(stmt, table, clause.Name, clause.Quoted)
+		case *unsupportedClause:
+			b.diags.warnf(stmt, "unsupported clause %v on table %v ignored", alterClauseKind(clause), table.Table)
+		}
+	}
+}
+
+func (b *schemaBuilder) addColumns(stmt statement, table *Table, clause *addClause) {
+	added := translateTable(clause.Create, clause.Clauses.Columns)
+	columns := []*Column{}
+	for _, col := range added.Columns {
+		if table.getColumn(col.Name) != nil {
+			b.diags.warnf(stmt, "column %v.%v already exists, add ignored", table.Table, col.Name)
+			continue
+		}
+		col.Schema = table.Schema
+		col.Table = table.Table
+		col.OrdinalPosition = len(table.Columns)
+		table.Columns = append(table.Columns, col)
+		columns = append(columns, col)
+	}
+	for _, uc := range added.UniqueConstraints {
+		if slices.Contains(columns, uc.Columns[0]) {
+			table.UniqueConstraints = append(table.UniqueConstraints, uc)
+		}
+	}
+	for _, col := range added.PrimaryKey {
+		if slices.Contains(columns, col) {
+			table.PrimaryKey = append(table.PrimaryKey, col)
+		}
+	}
+	if added.PrimaryKeyName != "" {
+		table.PrimaryKeyName = added.PrimaryKeyName
+	}
+	b.resolveUserTypes(stmt, columns)
+	b.addProperties(stmt, table, columns, clause.Create, clause.Clauses)
+	b.linkDefaults(stmt, columns, clause.Clauses.Columns)
+}
+
+func (b *schemaBuilder) modifyColumn(stmt statement, table *Table, mc modifyColumn) {
+	col := table.getColumn(mc.Name)
+	if col == nil {
+		b.diags.warnf(stmt, "modify of unknown column %v.%v ignored", table.Table, mc.Name)
+		return
+	}
+	if mc.DataType != nil {
+		col.DataType = mc.DataType
+		col.Type = typeStr(mc.DataType.DataDef())
+		col.CharacterMaximumLength = ""
+		col.Precision = ""
+		setCharacterMaximumLength(col, mc.DataType)
+		setPrecision(col, mc.DataType)
+	}
+	switch mc.Nullable {
+	case "true":
+		delete(col.Attribute, ast.ConstraintTypeNotNull)
+		col.Attribute[ast.ConstraintTypeNull] = nil
+		col.Nullable = "true"
+	case "false":
+		delete(col.Attribute, ast.ConstraintTypeNull)
+		col.Attribute[ast.ConstraintTypeNotNull] = nil
+		col.Nullable = "false"
+	}
+	if mc.HasDefault {
+		col.setDefault(mc.Default)
+		// the former default no longer fills the column, a trigger assigning a sequence still does
+		b.unlinkSequences(func(link sequenceLink) bool { return link.column == col && link.trigger == nil })
+		if mc.Default != nil && mc.Default.Kind == DefaultNextval {
+			b.linkSequence(stmt, col, mc.Default.Schema, mc.Default.Value, nil)
+		}
+	}
+	if mc.PrimaryKey && !slices.Contains(table.PrimaryKey, col) {
+		col.Attribute[ast.ConstraintTypePK] = nil
+		table.PrimaryKey = append(table.PrimaryKey, col)
+	}
+	if mc.Unique {
+		table.UniqueConstraints = append(table.UniqueConstraints, &UniqueConstraint{Columns: []*Column{col}, Span: stmt.span()})
+	}
+	b.addCheckConstraints(stmt, table, mc.Checks)
+}
+
+// dropColumn removes the column together with the constraints and indexes on it.
+func (b *schemaBuilder) dropColumn(table *Table, col *Column) {
+	b.unlinkSequences(func(link sequenceLink) bool { return link.column == col })
+	for _, fk := range slices.Clone(table.ForeignKeys) {
+		if slices.Contains(fk.Columns, col) {
+			unassignRefColumns(fk)
+		}
+	}
+	for _, fk := range slices.Clone(table.ReferencedBy) {
+		if slices.Contains(fk.RefColumns, col) {
+			unassignRefColumns(fk)
+		}
+	}
+	table.PrimaryKey = slices.DeleteFunc(table.PrimaryKey, func(c *Column) bool { return c == col })
+	table.UniqueConstraints = slices.DeleteFunc(table.UniqueConstraints, func(uc *UniqueConstraint) bool {
+		return slices.Contains(uc.Columns, col)
+	})
+	table.CheckConstraints = slices.DeleteFunc(table.CheckConstraints, func(check *CheckConstraint) bool {
+		if !slices.Contains(check.Columns, col) {
+			return false
+		}
+		for _, c := range check.Columns {
+			c.Checks = slices.DeleteFunc(c.Checks, func(other *CheckConstraint) bool { return other == check })
+		}
+		return true
+	})
+	table.Indexes = slices.DeleteFunc(table.Indexes, func(index *Index) bool {
+		return slices.ContainsFunc(index.Columns, func(ic IndexColumn) bool { return ic.Column == col })
+	})
+
+	table.Columns = slices.DeleteFunc(table.Columns, func(c *Column) bool { return c == col })
+	for i, c := range table.Columns {
+		c.OrdinalPosition = i
+	}
+}
+
+func (b *schemaBuilder) dropConstraint(stmt statement, table *Table, spec *ast.OutOfLineConstraint) {
+	if spec.Name == nil {
+		switch spec.Type {
+		case ast.ConstraintTypePK:
+			table.dropPrimaryKey()
+		case ast.ConstraintTypeUnique:
+			names := mapping(spec.Columns, getColumnName)
+			i := slices.IndexFunc(table.UniqueConstraints, func(uc *UniqueConstraint) bool {
+				return slices.Equal(mapping(uc.Columns, getName), names)
+			})
+			if i < 0 {
+				b.diags.warnf(stmt, "drop of unknown unique constraint (%v) on table %v ignored", strings.Join(names, ", "), table.Table)
+				return
+			}
+			table.UniqueConstraints = slices.Delete(table.UniqueConstraints, i, i+1)
+		}
+		return
+	}
+
+	name := spec.Name.Value
+	switch {
+	case table.PrimaryKeyName == name:
+		table.dropPrimaryKey()
+	case slices.ContainsFunc(table.ForeignKeys, func(fk *ForeignKey) bool { return fk.Name == name }):
+		i := slices.IndexFunc(table.ForeignKeys, func(fk *ForeignKey) bool { return fk.Name == name })
+		unassignRefColumns(table.ForeignKeys[i])
+	case slices.ContainsFunc(table.UniqueConstraints, func(uc *UniqueConstraint) bool { return uc.Name == name }):
+		table.UniqueConstraints = slices.DeleteFunc(table.UniqueConstraints, func(uc *UniqueConstraint) bool { return uc.Name == name })
+	case slices.ContainsFunc(table.CheckConstraints, func(check *CheckConstraint) bool { return check.Name == name }):
+		table.CheckConstraints = slices.DeleteFunc(table.CheckConstraints, func(check *CheckConstraint) bool { return check.Name == name })
+		for _, col := range table.Columns {
+			col.Checks = slices.DeleteFunc(col.Checks, func(check *CheckConstraint) bool { return check.Name == name })
+		}
+	default:
+		b.diags.warnf(stmt, "drop of unknown constraint %v on table %v ignored", name, table.Table)
+	}
+}
+
+// renameTable renames the table unless the name is used by a table, a view or a synonym of its schema,
+// which Oracle rejects with ORA-00955.
+func (b *schemaBuilder) renameTable(stmt statement, table *Table, name string, quoted bool) {
+	key := qualifiedName(table.Schema, name)
+	used := ""
+	switch {
+	case b.tables[key] != nil:
+		used = "table"
+	case b.views[key] != nil:
+		used = "view"
+	case b.synonyms[key] != nil:
+		used = "synonym"
+	}
+	if used != "" {
+		b.diags.warnf(stmt, "rename of %v to %v ignored: the name is used by a %v", table.QualifiedName(), name, used)
+		return
+	}
+	delete(b.tables, table.QualifiedName())
+	table.Table, table.Quoted = name, quoted
+	for _, col := range table.Columns {
+		col.Table = name
+	}
+	b.tables[table.QualifiedName()] = table
+}
+
+// dropTable removes the table and the foreign keys referencing it.
+func (b *schemaBuilder) dropTable(table *Table) {
+	for _, fk := range slices.Clone(table.ReferencedBy) {
+		unassignRefColumns(fk)
+	}
+	for _, fk := range slices.Clone(table.ForeignKeys) {
+		unassignRefColumns(fk)
+	}
+	b.dropTableTriggers(table)
+	b.unlinkSequences(func(link sequenceLink) bool { return slices.Contains(table.Columns, link.column) })
+	b.dropPrivileges(table)
+	delete(b.tables, table.QualifiedName())
+}
+
+// relation looks up a table or a view.
+func (b *schemaBuilder) relation(schema, name string) (*Table, bool) {
+	if table, ok := b.lookupTable(schema, name); ok {
+		return table, true
+	}
+	if view, ok := b.lookupView(schema, name); ok {
+		return view.AsTable(), true
+	}
+	return nil, false
+}
+
+// createView derives the columns of the view from its query and links them to the columns they select.
+func (b *schemaBuilder) createView(stmt statement, node *createViewStmt) {
+	schema := node.Schema
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	if _, ok := b.tables[qualifiedName(schema, node.Name)]; ok {
+		b.diags.warnf(stmt, "view %v ignored: a table has the same name", qualifiedName(schema, node.Name))
+		return
+	}
+	view := &View{
+		Span:         stmt.span(),
+		Schema:       schema,
+		Name:         node.Name,
+		Materialized: node.Materialized,
+		Query:        node.Query,
+		Columns:      []*Column{},
+	}
+
+	// scope maps the names the query uses for its tables, nil for subqueries and unknown tables
+	scope := map[string]*Table{}
+	scopeOrder := []string{}
+	for _, item := range node.From {
+		name := item.Alias
+		if name == "" {
+			name = item.Table
+		}
+		var table *Table
+		if item.Table != "" {
+			if t, ok := b.relation(item.Schema, item.Table); ok {
+				table = t
+				if !slices.Contains(view.BaseTables, t) {
+					view.BaseTables = append(view.BaseTables, t)
+				}
+			} else {
+				b.diags.warnf(stmt, "view %v selects from %v", view.Name, b.unknownTable(item.Schema, item.Table))
+			}
+		}
+		if name != "" {
+			scope[name] = table
+			scopeOrder = append(scopeOrder, name)
+		}
+	}
+
+	for _, item := range node.Items {
+		switch {
+		case item.Star && item.Qualifier != "":
+			table := scope[item.Qualifier]
+			if table == nil {
+				b.diags.warnf(stmt, "view %v: columns of %v.* unknown", view.Name, item.Qualifier)
+				continue
+			}
+			for _, col := range table.Columns {
+				view.Columns = append(view.Columns, viewColumn(view, col.Name, col, node.OuterJoin))
+			}
+		case item.Star:
+			for _, name := range scopeOrder {
+				table := scope[name]
+				if table == nil {
+					b.diags.warnf(stmt, "view %v: columns of %v unknown", view.Name, name)
+					continue
+				}
+				for _, col := range table.Columns {
+					view.Columns = append(view.Columns, viewColumn(view, col.Name, col, node.OuterJoin))
+				}
+			}
+		case item.Unnamed && len(node.Columns) == 0:
+			// Oracle rejects the view with ORA-00998, the expression has no usable column name
+			b.diags.warnf(stmt, "view %v: expression %v needs an alias, column left out", view.Name, item.Name)
+		default:
+			var source *Column
+			if item.Column != "" && item.Qualifier != "" {
+				if table := scope[item.Qualifier]; table != nil {
+					source = table.getColumn(item.Column)
+				}
+			} else if item.Column != "" {
+				for _, name := range scopeOrder {
+					if table := scope[name]; table != nil {
+						if source = table.getColumn(item.Column); source != nil {
+							break
+						}
+					}
+				}
+			}
+			view.Columns = append(view.Columns, viewColumn(view, item.Name, source, node.OuterJoin))
+		}
+	}
+
+	if len(node.Columns) > 0 {
+		if len(node.Columns) != len(view.Columns) {
+			b.diags.warnf(stmt, "view %v names %v column(s) but selects %v", view.Name, len(node.Columns), len(view.Columns))
+		}
+		for i, name := range node.Columns {
+			if i < len(view.Columns) {
+				view.Columns[i].Name = name
+			}
+		}
+	}
+	for i, col := range view.Columns {
+		col.OrdinalPosition = i
+	}
+	for _, name := range node.PrimaryKey {
+		i := slices.IndexFunc(view.Columns, func(col *Column) bool { return col.Name == name })
+		if i < 0 {
+			b.diags.warnf(stmt, "primary key of view %v names unknown column %v", view.Name, name)
+			continue
+		}
+		col := view.Columns[i]
+		col.Attribute[ast.ConstraintTypePK] = nil
+		view.PrimaryKey = append(view.PrimaryKey, col)
+	}
+	b.views[qualifiedName(view.Schema, view.Name)] = view
+	b.declared[view] = stmt.Index
+}
+
+// fill adds the views, the tables and their flattened keys and indexes to the database in the given order.
+func (b *schemaBuilder) fill(db *Database, order TableOrder) {
+	views := maps.Values(b.views)
+	slices.SortFunc(views, func(x, y *View) int { return b.declared[x] - b.declared[y] })
+	if order == TableOrderAlphabetical {
+		slices.SortStableFunc(views, func(x, y *View) int {
+			return strings.Compare(qualifiedName(x.Schema, x.Name), qualifiedName(y.Schema, y.Name))
+		})
+	}
+	db.Views = append(db.Views, views...)
+	db.Triggers = append(db.Triggers, b.triggers...)
+	db.Synonyms = append(db.Synonyms, b.resolveSynonyms()...)
+	db.UserTypes = append(db.UserTypes, b.userTypes()...)
+	db.Privileges.Objects = append(db.Privileges.Objects, b.privilegesOnCurrentNames()...)
+	db.Privileges.Roles = append(db.Privileges.Roles, b.roleGrants...)
+	db.Packages = append(db.Packages, b.packageList()...)
+	db.Routines = append(db.Routines, b.routineList()...)
+
+	tables := maps.Values(b.tables)
+	slices.SortFunc(tables, func(x, y *Table) int { return b.declared[x] - b.declared[y] })
+	for _, t := range sortTables(tables, order) {
+		db.Tables = append(db.Tables, t)
+		db.Columns = append(db.Columns, t.Columns...)
+		if pkInfo := primaryKeyInfo(t); pkInfo.FieldCount > 0 {
+			db.PkInfo = append(db.PkInfo, pkInfo)
+		}
+		for _, fk := range t.ForeignKeys {
+			db.FkInfo = append(db.FkInfo, fkInfos(fk)...)
+		}
+		for _, index := range t.Indexes {
+			db.Indexes = append(db.Indexes, indexInfos(index)...)
+		}
+	}
+}
+
+func primaryKeyInfo(table *Table) PkInfo {
+	pks := primaryKeyColumns(table)
+	if len(pks) == 0 {
+		return PkInfo{}
+	}
+	colNames := joinStr(mapping(pks, getName))
+	return PkInfo{
+		Schema:     table.Schema,
+		Table:      table.Table,
+		FieldCount: len(pks),
+		PkColumn:   colNames,
+		PkDef:      fmt.Sprintf("PRIMARY KEY (%v)", colNames),
+	}
+}
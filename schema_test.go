@@ -0,0 +1,120 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestQualifiedNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   ParseOptions
+		// want lists the tables as SCHEMA.TABLE, followed by the tables their foreign keys reference
+		// and by their indexes
+		want  []string
+		diags []string
+	}{
+		{
+			name: "same name in two schemas",
+			script: `CREATE TABLE app.users (id NUMBER PRIMARY KEY);
+CREATE TABLE audit.users (id NUMBER PRIMARY KEY);
+CREATE TABLE app.logins (user_id NUMBER REFERENCES app.users (id));
+CREATE TABLE audit.logins (user_id NUMBER REFERENCES audit.users (id));`,
+			want: []string{"APP.USERS", "AUDIT.USERS", "APP.LOGINS -> APP.USERS", "AUDIT.LOGINS -> AUDIT.USERS"},
+		},
+		{
+			name: "default schema",
+			script: `CREATE TABLE users (id NUMBER PRIMARY KEY);
+CREATE TABLE audit.users (id NUMBER PRIMARY KEY);
+ALTER TABLE users ADD (name VARCHAR2(10));
+CREATE TABLE logins (user_id NUMBER REFERENCES users (id));
+CREATE INDEX ix_users_name ON users (name);`,
+			opts: ParseOptions{DefaultSchema: "APP"},
+			want: []string{"APP.USERS ix IX_USERS_NAME", "AUDIT.USERS", "APP.LOGINS -> APP.USERS"},
+		},
+		{
+			name: "unqualified name matches the only qualified table",
+			script: `CREATE TABLE app.users (id NUMBER PRIMARY KEY);
+CREATE TABLE logins (user_id NUMBER REFERENCES users (id));`,
+			want: []string{"APP.USERS", "LOGINS -> APP.USERS"},
+		},
+		{
+			name: "qualified name matches an unqualified table",
+			script: `CREATE TABLE users (id NUMBER PRIMARY KEY);
+CREATE TABLE app.logins (user_id NUMBER REFERENCES app.users (id));`,
+			want: []string{"USERS", "APP.LOGINS -> USERS"},
+		},
+		{
+			name: "ambiguous name without a default schema",
+			script: `CREATE TABLE a.t (id NUMBER PRIMARY KEY);
+CREATE TABLE b.t (id NUMBER PRIMARY KEY);
+CREATE INDEX a.ix ON a.t (id);
+CREATE INDEX b.ix ON b.t (id);
+CREATE TABLE c.u (t_id NUMBER, CONSTRAINT fk_u_t FOREIGN KEY (t_id) REFERENCES t (id));
+DROP INDEX ix;
+COMMENT ON TABLE t IS 'which';`,
+			want: []string{"A.T ix IX", "B.T ix IX", "C.U"},
+			diags: []string{
+				"foreign key FK_U_T references ambiguous table T (schemas A, B: qualify the name or set ParseOptions.DefaultSchema)",
+				"drop of ambiguous index IX ignored (schemas A, B: qualify the name or set ParseOptions.DefaultSchema)",
+				"comment on ambiguous table T (schemas A, B: qualify the name or set ParseOptions.DefaultSchema) ignored",
+			},
+		},
+		{
+			name: "qualified index dropped",
+			script: `CREATE TABLE a.t (id NUMBER PRIMARY KEY);
+CREATE TABLE b.t (id NUMBER PRIMARY KEY);
+CREATE INDEX a.ix ON a.t (id);
+CREATE INDEX b.ix ON b.t (id);
+DROP INDEX b.ix;`,
+			want: []string{"A.T ix IX", "B.T"},
+		},
+		{
+			name: "index of the default schema dropped",
+			script: `CREATE TABLE t (id NUMBER PRIMARY KEY);
+CREATE TABLE b.t (id NUMBER PRIMARY KEY);
+CREATE INDEX ix ON t (id);
+CREATE INDEX b.ix ON b.t (id);
+DROP INDEX ix;`,
+			opts: ParseOptions{DefaultSchema: "A"},
+			want: []string{"A.T", "B.T ix IX"},
+		},
+		{
+			name: "unqualified indexes of tables in two schemas",
+			script: `CREATE TABLE a.t (id NUMBER);
+CREATE TABLE b.t (id NUMBER);
+CREATE INDEX ix ON a.t (id);
+CREATE INDEX ix ON b.t (id);
+DROP INDEX ix;`,
+			want:  []string{"A.T ix IX", "B.T ix IX"},
+			diags: []string{"drop of ambiguous index IX ignored (on tables A.T, B.T: qualify the index names where they are created)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := ParseWithOptions(tt.script, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := mapping(db.Tables, func(table *Table) string {
+				text := table.QualifiedName()
+				for _, fk := range table.ForeignKeys {
+					text += " -> " + fk.RefTable.QualifiedName()
+				}
+				for _, index := range table.Indexes {
+					text += " ix " + index.Name
+				}
+				return text
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.diags)
+			}
+		})
+	}
+}
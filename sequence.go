@@ -0,0 +1,283 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/slices"
+)
+
+// ConstraintTypeAutoIncrement marks identity columns and columns filled from a sequence
+// in an AttributeMap, the oracle parser has no constraint type for them.
+const ConstraintTypeAutoIncrement ast.ConstraintType = -1
+
+type SequenceOptions struct {
+	StartWith   string `json:"start_with,omitempty"`
+	IncrementBy string `json:"increment_by,omitempty"`
+	MinValue    string `json:"min_value,omitempty"`
+	MaxValue    string `json:"max_value,omitempty"`
+	Cache       string `json:"cache,omitempty"`
+	Cycle       bool   `json:"cycle,omitempty"`
+	Order       bool   `json:"order,omitempty"`
+}
+
+type Sequence struct {
+	SequenceOptions
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+}
+
+type Identity struct {
+	SequenceOptions
+	// Generation is one of "ALWAYS", "BY DEFAULT" and "BY DEFAULT ON NULL"
+	Generation string `json:"generation"`
+}
+
+// sourceNode implements ast.Node for the statements ddlcode parses by itself.
+type sourceNode struct {
+	text string
+}
+
+func (n *sourceNode) Text() string {
+	return n.text
+}
+
+func (n *sourceNode) SetText(text string) {
+	n.text = text
+}
+
+type createSequenceStmt struct {
+	sourceNode
+	Sequence *Sequence
+}
+
+type sequenceAssignment struct {
+	Schema   string
+	Sequence string
+	Column   string
+}
+
+func castCreateSequenceStmt(v ast.Node) *createSequenceStmt {
+	r, _ := v.(*createSequenceStmt)
+	return r
+}
+
+func parseCreateSequence(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	if !r.accept("CREATE", "SEQUENCE") {
+		return nil, fmt.Errorf("not a sequence")
+	}
+	r.accept("IF", "NOT", "EXISTS")
+	seq := &Sequence{}
+	seq.Schema, seq.Name = r.qualifiedName()
+	seq.SequenceOptions = parseSequenceOptions(r)
+
+	return &createSequenceStmt{sourceNode: sourceNode{text: stmt.Source}, Sequence: seq}, nil
+}
+
+func parseSequenceOptions(r *tokenReader) SequenceOptions {
+	opts := SequenceOptions{}
+	number := func() string {
+		sign := ""
+		if r.peek().Is("-") || r.peek().Is("+") {
+			sign = strings.TrimPrefix(r.next().Text, "+")
+		}
+		return sign + r.next().Text
+	}
+	for !r.done() {
+		switch {
+		case r.accept("START", "WITH"):
+			if r.accept("LIMIT", "VALUE") {
+				continue
+			}
+			opts.StartWith = number()
+		case r.accept("INCREMENT", "BY"):
+			opts.IncrementBy = number()
+		case r.accept("MINVALUE"):
+			opts.MinValue = number()
+		case r.accept("MAXVALUE"):
+			opts.MaxValue = number()
+		case r.accept("CACHE"):
+			opts.Cache = number()
+		case r.accept("CYCLE"):
+			opts.Cycle = true
+		case r.accept("ORDER"):
+			opts.Order = true
+		default:
+			r.next()
+		}
+	}
+	return opts
+}
+
+// findSequenceAssignments looks for ":NEW.col := seq.NEXTVAL" and
+// "SELECT seq.NEXTVAL INTO :NEW.col" in a trigger body, newName is NEW or its REFERENCING alias.
+func findSequenceAssignments(toks []token, newName string) []sequenceAssignment {
+	assignments := []sequenceAssignment{}
+	isNewColumn := func(i int) bool {
+		return i >= 0 && i+3 < len(toks) && toks[i].Is(":") && toks[i+1].Is(newName) && toks[i+2].Is(".")
+	}
+
+	for i := 2; i < len(toks); i++ {
+		if !toks[i].Is("NEXTVAL") || !toks[i-1].Is(".") {
+			continue
+		}
+		a := sequenceAssignment{Sequence: toks[i-2].Value()}
+		start := i - 2
+		if start >= 2 && toks[start-1].Is(".") {
+			a.Schema = toks[start-2].Value()
+			start -= 2
+		}
+
+		if start >= 1 && toks[start-1].Is(":=") && isNewColumn(start-5) {
+			a.Column = toks[start-2].Value()
+		} else if i+5 < len(toks) && toks[i+1].Is("INTO") && isNewColumn(i+2) {
+			a.Column = toks[i+5].Value()
+		} else {
+			continue
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments
+}
+
+func nextvalReference(expr []token) (schema, name string, ok bool) {
+	switch {
+	case len(expr) == 3 && expr[1].Is(".") && expr[2].Is("NEXTVAL"):
+		return "", expr[0].Value(), true
+	case len(expr) == 5 && expr[1].Is(".") && expr[3].Is(".") && expr[4].Is("NEXTVAL"):
+		return expr[0].Value(), expr[2].Value(), true
+	}
+	return "", "", false
+}
+
+// parseIdentityClause reads "GENERATED ... AS IDENTITY [(options)]" and the number of its tokens.
+func parseIdentityClause(toks []token) (*Identity, int) {
+	r := newTokenReader(toks)
+	r.accept("GENERATED")
+	identity := &Identity{Generation: "ALWAYS"}
+	switch {
+	case r.accept("BY", "DEFAULT", "ON", "NULL"):
+		identity.Generation = "BY DEFAULT ON NULL"
+	case r.accept("BY", "DEFAULT"):
+		identity.Generation = "BY DEFAULT"
+	default:
+		r.accept("ALWAYS")
+	}
+	if !r.accept("AS", "IDENTITY") {
+		return nil, 0
+	}
+	if r.peek().Is("(") {
+		identity.SequenceOptions = parseSequenceOptions(newTokenReader(r.group()))
+	}
+	return identity, r.pos
+}
+
+// sequenceLink is a column filled from a sequence through DEFAULT seq.NEXTVAL or through a trigger assigning
+// seq.NEXTVAL to :NEW.col. The sequence of a link may be created later in the script.
+type sequenceLink struct {
+	stmt   statement
+	column *Column
+	schema string
+	name   string
+	// trigger is the trigger assigning the sequence, nil for a default
+	trigger *Trigger
+}
+
+// findSequence matches the schema only when both the reference and the sequence have one.
+func (b *schemaBuilder) findSequence(schema, name string) *Sequence {
+	for _, seq := range b.sequences {
+		if strings.EqualFold(seq.Name, name) && (schema == "" || seq.Schema == "" || strings.EqualFold(seq.Schema, schema)) {
+			return seq
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) createSequence(node *createSequenceStmt) {
+	b.sequences = append(b.sequences, node.Sequence)
+	for _, link := range b.sequenceLinks {
+		if link.column.Sequence == nil {
+			link.column.Sequence = b.findSequence(link.schema, link.name)
+		}
+	}
+}
+
+// linkSequence fills the column from the sequence, trigger is the trigger assigning it or nil for a default.
+func (b *schemaBuilder) linkSequence(stmt statement, col *Column, schema, name string, trigger *Trigger) {
+	b.sequenceLinks = append(b.sequenceLinks, sequenceLink{stmt: stmt, column: col, schema: schema, name: name, trigger: trigger})
+	if seq := b.findSequence(schema, name); seq != nil {
+		col.Sequence = seq
+	}
+}
+
+// linkDefaults links the columns whose default is seq.NEXTVAL.
+func (b *schemaBuilder) linkDefaults(stmt statement, columns []*Column, clauses map[string]*columnClauses) {
+	for _, col := range columns {
+		if c, ok := clauses[col.Name]; ok && c.SequenceName != "" {
+			b.linkSequence(stmt, col, c.SequenceSchema, c.SequenceName, nil)
+		}
+	}
+}
+
+// unlinkSequences removes the links matching drop, a column keeps the sequence of another link left.
+func (b *schemaBuilder) unlinkSequences(drop func(link sequenceLink) bool) {
+	dropped := []*Column{}
+	b.sequenceLinks = slices.DeleteFunc(b.sequenceLinks, func(link sequenceLink) bool {
+		if drop(link) {
+			dropped = append(dropped, link.column)
+			return true
+		}
+		return false
+	})
+	for _, col := range dropped {
+		col.Sequence = nil
+		delete(col.Attribute, ConstraintTypeAutoIncrement)
+		for _, link := range b.sequenceLinks {
+			if link.column == col {
+				col.Sequence = b.findSequence(link.schema, link.name)
+			}
+		}
+	}
+}
+
+// finishSequences warns about the sequences never created, links a sequence named <TABLE>_SEQ to the single
+// column numeric primary key of TABLE unless skipped, and marks the identity and sequence filled columns.
+func (b *schemaBuilder) finishSequences(skipNaming bool) {
+	for _, link := range b.sequenceLinks {
+		if link.column.Sequence == nil {
+			b.diags.warnf(link.stmt, "column %v.%v uses unknown sequence %v", link.column.Table, link.column.Name, link.name)
+		}
+	}
+
+	if !skipNaming {
+		for _, table := range b.tables {
+			seq := b.findSequence(table.Schema, table.Table+"_SEQ")
+			if seq == nil {
+				continue
+			}
+			pks := primaryKeyColumns(table)
+			if len(pks) != 1 || pks[0].Sequence != nil || pks[0].Identity != nil || !isNumericType(pks[0]) {
+				continue
+			}
+			pks[0].Sequence = seq
+		}
+	}
+
+	for _, table := range b.tables {
+		for _, col := range table.Columns {
+			if col.Sequence != nil || col.Identity != nil {
+				col.Attribute[ConstraintTypeAutoIncrement] = nil
+			}
+		}
+	}
+}
+
+func isNumericType(col *Column) bool {
+	switch col.Type {
+	case "number", "numeric", "decimal", "dec", "integer", "int", "smallint":
+		return true
+	}
+	return false
+}
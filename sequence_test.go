@@ -0,0 +1,166 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSequenceLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   ParseOptions
+		// want maps TABLE.COLUMN to the sequence filling it, or to "" for a column filled by none
+		want  map[string]string
+		diags []string
+	}{
+		{
+			name: "default",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL, n NUMBER);`,
+			want: map[string]string{"A.ID": "S", "A.N": ""},
+		},
+		{
+			name: "default of an added column",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (n NUMBER);
+ALTER TABLE a ADD (id NUMBER DEFAULT s.NEXTVAL);`,
+			want: map[string]string{"A.ID": "S"},
+		},
+		{
+			name: "sequence created after the table",
+			script: `CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+CREATE SEQUENCE s;`,
+			want: map[string]string{"A.ID": "S"},
+		},
+		{
+			name: "default modified",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+ALTER TABLE a MODIFY (id DEFAULT 0);`,
+			want: map[string]string{"A.ID": ""},
+		},
+		{
+			name: "default modified to a sequence",
+			script: `CREATE SEQUENCE s;
+CREATE SEQUENCE t;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+ALTER TABLE a MODIFY (id DEFAULT t.NEXTVAL);`,
+			want: map[string]string{"A.ID": "T"},
+		},
+		{
+			name: "table dropped and created again",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+DROP TABLE a;
+CREATE TABLE a (id NUMBER);`,
+			want: map[string]string{"A.ID": ""},
+		},
+		{
+			name: "table renamed",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+RENAME a TO b;`,
+			want: map[string]string{"B.ID": "S"},
+		},
+		{
+			name: "trigger",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER, n NUMBER);
+CREATE TRIGGER a_bi BEFORE INSERT ON a REFERENCING NEW AS n FOR EACH ROW
+BEGIN
+  SELECT s.NEXTVAL INTO :n.id FROM dual;
+END;
+/`,
+			want: map[string]string{"A.ID": "S"},
+		},
+		{
+			name: "trigger dropped",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER, n NUMBER);
+CREATE TRIGGER a_bi BEFORE INSERT ON a FOR EACH ROW
+BEGIN
+  :NEW.id := s.NEXTVAL;
+END;
+/
+DROP TRIGGER a_bi;`,
+			want: map[string]string{"A.ID": ""},
+		},
+		{
+			name: "trigger dropped keeps the default",
+			script: `CREATE SEQUENCE s;
+CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);
+CREATE TRIGGER a_bi BEFORE INSERT ON a FOR EACH ROW
+BEGIN
+  :NEW.id := s.NEXTVAL;
+END;
+/
+DROP TRIGGER a_bi;`,
+			want: map[string]string{"A.ID": "S"},
+		},
+		{
+			name: "naming convention",
+			script: `CREATE SEQUENCE orders_seq;
+CREATE TABLE orders (id NUMBER(10) PRIMARY KEY, code VARCHAR2(10));`,
+			want: map[string]string{"ORDERS.ID": "ORDERS_SEQ", "ORDERS.CODE": ""},
+		},
+		{
+			name: "naming convention skipped",
+			script: `CREATE SEQUENCE orders_seq;
+CREATE TABLE orders (id NUMBER(10) PRIMARY KEY);`,
+			opts: ParseOptions{SkipSequenceNaming: true},
+			want: map[string]string{"ORDERS.ID": ""},
+		},
+		{
+			name:   "unknown sequence",
+			script: `CREATE TABLE a (id NUMBER DEFAULT s.NEXTVAL);`,
+			want:   map[string]string{"A.ID": ""},
+			diags:  []string{"column A.ID uses unknown sequence S"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := ParseWithOptions(tt.script, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, table := range db.Tables {
+				for _, col := range table.Columns {
+					want, ok := tt.want[table.Table+"."+col.Name]
+					if !ok {
+						continue
+					}
+					got := ""
+					if col.Sequence != nil {
+						got = col.Sequence.Name
+					}
+					if got != want {
+						t.Errorf("%v.%v is filled from %q, want %q", table.Table, col.Name, got, want)
+					}
+					if col.Attribute.IsAutoIncrement() != (want != "") {
+						t.Errorf("%v.%v IsAutoIncrement is %v", table.Table, col.Name, col.Attribute.IsAutoIncrement())
+					}
+				}
+			}
+			if messages := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(messages, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", messages, tt.diags)
+			}
+		})
+	}
+}
+
+func TestIdentityColumn(t *testing.T) {
+	db, _, err := Parse(`CREATE TABLE a (id NUMBER GENERATED BY DEFAULT ON NULL AS IDENTITY (START WITH 10 INCREMENT BY 5), n NUMBER);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, n := db.Tables[0].Columns[0], db.Tables[0].Columns[1]
+	if id.Identity == nil || id.Identity.Generation != "BY DEFAULT ON NULL" || id.Identity.StartWith != "10" || id.Identity.IncrementBy != "5" {
+		t.Errorf("got identity %+v", id.Identity)
+	}
+	if !id.Attribute.IsAutoIncrement() || n.Attribute.IsAutoIncrement() {
+		t.Errorf("got IsAutoIncrement %v and %v", id.Attribute.IsAutoIncrement(), n.Attribute.IsAutoIncrement())
+	}
+}
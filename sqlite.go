@@ -0,0 +1,87 @@
+package ddlcode
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+)
+
+type SQLiteConfig struct {
+	ExportDir string
+	FileName  string
+	// Indent indents the columns and the constraints in CREATE TABLE
+	Indent string
+	// Types overrides the type mapping, see PostgresConfig.Types
+	Types map[string]string
+}
+
+// sqliteKeywords are the SQLite keywords, they are quoted when used as names.
+var sqliteKeywords = []string{
+	"abort", "action", "add", "after", "all", "alter", "always", "analyze", "and", "as", "asc", "attach",
+	"autoincrement", "before", "begin", "between", "by", "cascade", "case", "cast", "check", "collate", "column",
+	"commit", "conflict", "constraint", "create", "cross", "current", "current_date", "current_time",
+	"current_timestamp", "database", "default", "deferrable", "deferred", "delete", "desc", "detach", "distinct",
+	"do", "drop", "each", "else", "end", "escape", "except", "exclude", "exclusive", "exists", "explain", "fail",
+	"filter", "first", "following", "for", "foreign", "from", "full", "generated", "glob", "group", "groups",
+	"having", "if", "ignore", "immediate", "in", "index", "indexed", "initially", "inner", "insert", "instead",
+	"intersect", "into", "is", "isnull", "join", "key", "last", "left", "like", "limit", "match", "materialized",
+	"natural", "no", "not", "nothing", "notnull", "null", "nulls", "of", "offset", "on", "or", "order", "others",
+	"outer", "over", "partition", "plan", "pragma", "preceding", "primary", "query", "raise", "range", "recursive",
+	"references", "regexp", "reindex", "release", "rename", "replace", "restrict", "returning", "right",
+	"rollback", "row", "rows", "savepoint", "select", "set", "table", "temp", "temporary", "then", "ties", "to",
+	"transaction", "trigger", "unbounded", "union", "unique", "update", "using", "vacuum", "values", "view",
+	"virtual", "when", "where", "window", "with", "without",
+}
+
+func GetDefaultSQLiteConfig() SQLiteConfig {
+	return SQLiteConfig{
+		ExportDir: ".",
+		FileName:  "sqlite.sql",
+		Indent:    "  ",
+		Types:     map[string]string{},
+	}
+}
+
+// GenerateSQLite translates the schema to SQLite DDL: the tables referenced tables first with their foreign keys,
+// the indexes and the views. Schemas are left out, an identity or sequence numbered primary key becomes
+// INTEGER PRIMARY KEY AUTOINCREMENT. The diagnostics are those of CheckConformance, the types which cannot be
+// mapped and the expressions kept as written which call other functions than the portable ones or use Oracle keywords.
+func GenerateSQLite(db Database, config SQLiteConfig) (map[string]string, []Diagnostic, error) {
+	if config.FileName == "" {
+		return nil, nil, fmt.Errorf("file name is empty")
+	}
+	w := &dialectWriter{dialect: DialectSQLite, indent: config.Indent, types: config.Types}
+	content := w.script(db)
+	diags := append(CheckConformance(db, DialectSQLite, ""), w.diags...)
+	return map[string]string{filepath.Join(config.ExportDir, config.FileName): content}, diags, nil
+}
+
+// toSQLiteTypeName maps an Oracle type to a type name giving the SQLite column the matching affinity, dates
+// map to the names drivers read as time. It returns an empty name for the types SQLite has no equivalent of.
+func toSQLiteTypeName(datatype element.Datatype) string {
+	switch datatype.DataDef() {
+	case element.DataDefNumber, element.DataDefDecimal, element.DataDefDec, element.DataDefNumeric:
+		realType := datatype.(*element.Number)
+		if realType.Precision == nil || realType.Precision.IsAsterisk || realType.Scale != nil && *realType.Scale > 0 {
+			return "numeric"
+		}
+		return "integer"
+	case element.DataDefInteger, element.DataDefInt, element.DataDefSmallInt:
+		return "integer"
+	case element.DataDefFloat, element.DataDefReal, element.DataDefBinaryFloat, element.DataDefBinaryDouble, element.DataDefDoublePrecision:
+		return "real"
+	case element.DataDefChar, element.DataDefVarchar2, element.DataDefNChar, element.DataDefNVarChar2, element.DataDefCharacter,
+		element.DataDefCharacterVarying, element.DataDefCharVarying, element.DataDefNCharVarying, element.DataDefVarchar,
+		element.DataDefNationalCharacter, element.DataDefNationalCharacterVarying, element.DataDefNationalChar,
+		element.DataDefNationalCharVarying, element.DataDefLong, element.DataDefClob, element.DataDefNClob, element.DataDefXMLType:
+		return "text"
+	case element.DataDefDate:
+		return "datetime"
+	case element.DataDefTimestamp:
+		return "timestamp"
+	case element.DataDefBlob, element.DataDefRaw, element.DataDefLongRaw:
+		return "blob"
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package ddlcode
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestGenerateSQLite(t *testing.T) {
+	sql := `CREATE SEQUENCE b_seq;
+CREATE TABLE a (id NUMBER(10) GENERATED AS IDENTITY PRIMARY KEY, name VARCHAR2(20));
+CREATE TABLE b (id NUMBER PRIMARY KEY, a_id NUMBER(10) REFERENCES a (id));
+CREATE VIEW v AS SELECT id, SUBSTR(name, 1, 2) AS short FROM a;`
+	config := GetDefaultSQLiteConfig()
+	files, diags, err := GenerateSQLite(parseSchema(t, sql), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := files[config.FileName]
+	for _, want := range []string{
+		"CREATE TABLE a (\n  id integer PRIMARY KEY AUTOINCREMENT,\n  name text\n);",
+		"  id numeric,\n  a_id integer,\n  PRIMARY KEY (id),\n  FOREIGN KEY (a_id) REFERENCES a (id)\n);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in\n%v", want, content)
+		}
+	}
+	want := []string{
+		"sequence B_SEQ left out, SQLite has no sequences",
+		"numbering of B.ID left out, SQLite only numbers an integer primary key",
+		"view V uses SUBSTR, kept as written",
+	}
+	if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, want) {
+		t.Errorf("got diagnostics %q, want %q", got, want)
+	}
+}
+
+func TestGenerateSQLiteGolden(t *testing.T) {
+	config := GetDefaultSQLiteConfig()
+	files, diags, err := GenerateSQLite(parseTestSchema(t), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "sqlite.sql", files[config.FileName])
+	checkGolden(t, "sqlite.diagnostics", diagnosticLines(diags))
+}
@@ -0,0 +1,172 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type createSynonymStmt struct {
+	sourceNode
+	Schema       string
+	Name         string
+	Public       bool
+	TargetSchema string
+	TargetName   string
+	DBLink       string
+}
+
+type dropSynonymStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+	Public bool
+}
+
+// publicSchema owns the public synonyms as in ALL_SYNONYMS.
+const publicSchema = "PUBLIC"
+
+// parseCreateSynonym reads CREATE [OR REPLACE] [EDITIONABLE | NONEDITIONABLE] [PUBLIC] SYNONYM [schema.]name
+// [SHARING = ...] FOR [schema.]object[@dblink].
+func parseCreateSynonym(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	for r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	synonym := &createSynonymStmt{sourceNode: sourceNode{text: stmt.Source}}
+	synonym.Public = r.accept("PUBLIC")
+	if !r.accept("SYNONYM") {
+		return nil, syntaxError(stmt, r.peek(), "expected SYNONYM")
+	}
+	synonym.Schema, synonym.Name = r.qualifiedName()
+	if r.accept("SHARING", "=") {
+		r.next()
+	}
+	if !r.accept("FOR") {
+		return nil, syntaxError(stmt, r.peek(), "expected FOR")
+	}
+	if !isIdentifier(r.peek()) {
+		return nil, syntaxError(stmt, r.peek(), "expected the object of the synonym")
+	}
+	synonym.TargetSchema, synonym.TargetName = r.qualifiedName()
+	if r.accept("@") {
+		parts := []string{}
+		for !r.done() {
+			parts = append(parts, r.next().Text)
+		}
+		synonym.DBLink = strings.Join(parts, "")
+	}
+	return synonym, nil
+}
+
+func parseDropSynonym(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("DROP")
+	node := &dropSynonymStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Public = r.accept("PUBLIC")
+	if !r.accept("SYNONYM") {
+		return nil, syntaxError(stmt, r.peek(), "expected SYNONYM")
+	}
+	r.accept("IF", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+// createSynonym records the synonym, references through it are resolved from now on.
+func (b *schemaBuilder) createSynonym(stmt statement, node *createSynonymStmt) {
+	schema := node.Schema
+	switch {
+	case node.Public:
+		schema = publicSchema
+	case schema == "":
+		schema = b.defaultSchema
+	}
+	targetSchema := node.TargetSchema
+	if targetSchema == "" && node.DBLink == "" {
+		// an unqualified object belongs to the schema of the synonym, the creator's for a public one
+		targetSchema = schema
+		if node.Public {
+			targetSchema = b.defaultSchema
+		}
+	}
+	synonym := &Synonym{
+		Schema:       schema,
+		Name:         node.Name,
+		Public:       node.Public,
+		TargetSchema: targetSchema,
+		TargetName:   node.TargetName,
+		DBLink:       node.DBLink,
+		Span:         stmt.span(),
+	}
+	b.synonyms[qualifiedName(schema, node.Name)] = synonym
+	b.declared[synonym] = stmt.Index
+	b.resolvePending()
+}
+
+func (b *schemaBuilder) dropSynonym(stmt statement, node *dropSynonymStmt) {
+	schema := node.Schema
+	if node.Public {
+		schema = publicSchema
+	}
+	synonym, ok := lookupQualified(b.synonyms, b.defaultSchema, schema, node.Name)
+	if !ok {
+		b.diags.warnf(stmt, "drop of unknown synonym %v ignored", qualifiedName(schema, node.Name))
+		return
+	}
+	delete(b.synonyms, qualifiedName(synonym.Schema, synonym.Name))
+}
+
+// lookupSynonym finds the synonym a name refers to, a private synonym comes before a public one
+// which only applies to unqualified names.
+func (b *schemaBuilder) lookupSynonym(schema, name string) *Synonym {
+	if synonym, ok := lookupQualified(b.synonyms, b.defaultSchema, schema, name); ok {
+		return synonym
+	}
+	if schema == "" {
+		return b.synonyms[qualifiedName(publicSchema, name)]
+	}
+	return nil
+}
+
+// lookupThroughSynonyms looks up the name in m and, when it is not there, follows the synonyms of that name
+// until an entry is found. Synonyms to remote objects are not followed.
+func lookupThroughSynonyms[T any](b *schemaBuilder, m map[string]T, schema, name string) (T, bool) {
+	seen := map[*Synonym]bool{}
+	for {
+		if v, ok := lookupQualified(m, b.defaultSchema, schema, name); ok {
+			return v, true
+		}
+		synonym := b.lookupSynonym(schema, name)
+		if synonym == nil || synonym.DBLink != "" || seen[synonym] {
+			var zero T
+			return zero, false
+		}
+		seen[synonym] = true
+		schema, name = synonym.TargetSchema, synonym.TargetName
+	}
+}
+
+// resolveSynonyms links the synonyms to the tables and views they finally stand for
+// and records them as aliases of the tables.
+func (b *schemaBuilder) resolveSynonyms() []*Synonym {
+	synonyms := maps.Values(b.synonyms)
+	slices.SortFunc(synonyms, func(x, y *Synonym) int { return b.declared[x] - b.declared[y] })
+	for _, synonym := range synonyms {
+		if synonym.DBLink != "" {
+			continue
+		}
+		if table, ok := b.relation(synonym.TargetSchema, synonym.TargetName); ok {
+			synonym.Table = table
+			table.Aliases = append(table.Aliases, synonym)
+		}
+	}
+	return synonyms
+}
+
+// String returns the qualified name of the synonym.
+func (s *Synonym) String() string {
+	return qualifiedName(s.Schema, s.Name)
+}
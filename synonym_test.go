@@ -0,0 +1,99 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSynonyms(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   ParseOptions
+		// synonyms lists the synonyms as "SCHEMA.NAME -> SCHEMA.TABLE", with no table when it is not known
+		synonyms []string
+		// references lists the tables referenced by the foreign keys of APP.BADGES
+		references []string
+		// aliases lists the aliases of HR.EMPLOYEES
+		aliases []string
+		diags   []string
+	}{
+		{
+			name: "private and public synonyms",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+CREATE SYNONYM app.emp FOR hr.employees;
+CREATE PUBLIC SYNONYM employees FOR hr.employees;
+CREATE TABLE app.badges (emp_id NUMBER REFERENCES emp (id), boss_id NUMBER REFERENCES employees (id));`,
+			opts:       ParseOptions{DefaultSchema: "APP"},
+			synonyms:   []string{"APP.EMP -> HR.EMPLOYEES", "PUBLIC.EMPLOYEES -> HR.EMPLOYEES"},
+			references: []string{"HR.EMPLOYEES", "HR.EMPLOYEES"},
+			aliases:    []string{"APP.EMP", "PUBLIC.EMPLOYEES"},
+		},
+		{
+			name: "synonym of a synonym",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+CREATE SYNONYM hr.staff FOR hr.employees;
+CREATE PUBLIC SYNONYM people FOR hr.staff;
+CREATE TABLE app.badges (emp_id NUMBER REFERENCES people (id));`,
+			synonyms:   []string{"HR.STAFF -> HR.EMPLOYEES", "PUBLIC.PEOPLE -> HR.EMPLOYEES"},
+			references: []string{"HR.EMPLOYEES"},
+			aliases:    []string{"HR.STAFF", "PUBLIC.PEOPLE"},
+		},
+		{
+			name: "table created after the reference",
+			script: `CREATE SYNONYM app.emp FOR hr.employees;
+CREATE TABLE app.badges (emp_id NUMBER REFERENCES app.emp (id));
+CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);`,
+			synonyms:   []string{"APP.EMP -> HR.EMPLOYEES"},
+			references: []string{"HR.EMPLOYEES"},
+		},
+		{
+			name: "dropped and remote synonyms",
+			script: `CREATE TABLE hr.employees (id NUMBER PRIMARY KEY);
+CREATE SYNONYM app.emp FOR hr.employees;
+CREATE SYNONYM app.remote_emp FOR hr.employees@hq;
+DROP SYNONYM app.emp;
+CREATE TABLE app.badges (emp_id NUMBER REFERENCES app.emp (id), r_id NUMBER REFERENCES app.remote_emp (id));`,
+			synonyms: []string{"APP.REMOTE_EMP -> "},
+			diags: []string{
+				"foreign key <unnamed> references unknown table APP.EMP",
+				"foreign key <unnamed> references unknown table APP.REMOTE_EMP",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := ParseWithOptions(tt.script, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			synonyms := mapping(db.Synonyms, func(synonym *Synonym) string {
+				text := qualifiedName(synonym.Schema, synonym.Name) + " -> "
+				if synonym.Table != nil {
+					text += synonym.Table.QualifiedName()
+				}
+				return text
+			})
+			if !slices.Equal(synonyms, tt.synonyms) {
+				t.Errorf("got synonyms %q, want %q", synonyms, tt.synonyms)
+			}
+			i := slices.IndexFunc(db.Tables, func(table *Table) bool { return table.QualifiedName() == "APP.BADGES" })
+			references := mapping(db.Tables[i].ForeignKeys, func(fk *ForeignKey) string { return fk.RefTable.QualifiedName() })
+			if !slices.Equal(references, tt.references) {
+				t.Errorf("got references %q, want %q", references, tt.references)
+			}
+			if len(tt.aliases) > 0 {
+				i := slices.IndexFunc(db.Tables, func(table *Table) bool { return table.QualifiedName() == "HR.EMPLOYEES" })
+				aliases := mapping(db.Tables[i].Aliases, func(synonym *Synonym) string { return qualifiedName(synonym.Schema, synonym.Name) })
+				if !slices.Equal(aliases, tt.aliases) {
+					t.Errorf("got aliases %q, want %q", aliases, tt.aliases)
+				}
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.diags)
+			}
+		})
+	}
+}
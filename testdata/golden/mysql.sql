@@ -0,0 +1,37 @@
+CREATE TABLE customers (
+  id bigint AUTO_INCREMENT,
+  email varchar(200) NOT NULL,
+  name varchar(100),
+  status char(1) DEFAULT 'A' NOT NULL,
+  created_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6) NOT NULL,
+  CONSTRAINT pk_customers PRIMARY KEY (id),
+  CONSTRAINT uq_customers_email UNIQUE (email),
+  CONSTRAINT ck_customers_status CHECK (status IN ('A', 'I'))
+) ENGINE=InnoDB;
+CREATE TABLE orders (
+  id bigint AUTO_INCREMENT,
+  customer_id bigint NOT NULL,
+  ordered_on datetime DEFAULT CURRENT_TIMESTAMP,
+  total decimal(12, 2) DEFAULT 0 NOT NULL COMMENT 'Total in the currency of the customer',
+  note longtext,
+  CONSTRAINT pk_orders PRIMARY KEY (id),
+  CONSTRAINT ck_orders_total CHECK (total >= 0),
+  CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE
+) ENGINE=InnoDB COMMENT='Orders of the customers';
+CREATE TABLE order_lines (
+  order_id bigint,
+  line_no int,
+  product varchar(50) NOT NULL,
+  quantity int DEFAULT 1 NOT NULL,
+  price decimal(10, 2),
+  image longblob,
+  CONSTRAINT pk_order_lines PRIMARY KEY (order_id, line_no),
+  FOREIGN KEY (order_id) REFERENCES orders (id)
+) ENGINE=InnoDB;
+
+CREATE INDEX ix_orders_customer ON orders (customer_id, ordered_on DESC);
+CREATE UNIQUE INDEX ux_order_lines_product ON order_lines (order_id, product);
+
+CREATE VIEW customer_orders AS
+SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;
@@ -0,0 +1,42 @@
+CREATE SEQUENCE ORDERS_SEQ START WITH 1000 INCREMENT BY 1 CACHE 20;
+
+CREATE TABLE CUSTOMERS (
+  ID NUMBER(10) GENERATED BY DEFAULT AS IDENTITY,
+  EMAIL VARCHAR2(200) NOT NULL,
+  NAME NVARCHAR2(100),
+  STATUS CHAR(1) DEFAULT 'A' NOT NULL,
+  CREATED_AT TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL,
+  CONSTRAINT PK_CUSTOMERS PRIMARY KEY (ID),
+  CONSTRAINT UQ_CUSTOMERS_EMAIL UNIQUE (EMAIL),
+  CONSTRAINT CK_CUSTOMERS_STATUS CHECK (status IN ('A', 'I'))
+);
+CREATE TABLE ORDERS (
+  ID NUMBER(10),
+  CUSTOMER_ID NUMBER(10) NOT NULL,
+  ORDERED_ON DATE DEFAULT SYSDATE,
+  TOTAL NUMBER(12, 2) DEFAULT 0 NOT NULL,
+  NOTE CLOB,
+  CONSTRAINT PK_ORDERS PRIMARY KEY (ID),
+  CONSTRAINT CK_ORDERS_TOTAL CHECK (total >= 0),
+  CONSTRAINT FK_ORDERS_CUSTOMER FOREIGN KEY (CUSTOMER_ID) REFERENCES CUSTOMERS (ID) ON DELETE CASCADE
+);
+CREATE TABLE ORDER_LINES (
+  ORDER_ID NUMBER(10),
+  LINE_NO NUMBER(5),
+  PRODUCT VARCHAR2(50) NOT NULL,
+  QUANTITY NUMBER(8) DEFAULT 1 NOT NULL,
+  PRICE NUMBER(10, 2),
+  IMAGE BLOB,
+  CONSTRAINT PK_ORDER_LINES PRIMARY KEY (ORDER_ID, LINE_NO),
+  FOREIGN KEY (ORDER_ID) REFERENCES ORDERS (ID)
+);
+
+CREATE INDEX IX_ORDERS_CUSTOMER ON ORDERS (CUSTOMER_ID, ORDERED_ON DESC);
+CREATE UNIQUE INDEX UX_ORDER_LINES_PRODUCT ON ORDER_LINES (ORDER_ID, PRODUCT);
+
+COMMENT ON TABLE ORDERS IS 'Orders of the customers';
+COMMENT ON COLUMN ORDERS.TOTAL IS 'Total in the currency of the customer';
+
+CREATE VIEW CUSTOMER_ORDERS AS
+SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;
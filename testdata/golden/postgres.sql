@@ -0,0 +1,42 @@
+CREATE SEQUENCE orders_seq START WITH 1000 INCREMENT BY 1 CACHE 20;
+
+CREATE TABLE customers (
+  id bigint GENERATED BY DEFAULT AS IDENTITY,
+  email varchar(200) NOT NULL,
+  name varchar(100),
+  status char(1) DEFAULT 'A' NOT NULL,
+  created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT pk_customers PRIMARY KEY (id),
+  CONSTRAINT uq_customers_email UNIQUE (email),
+  CONSTRAINT ck_customers_status CHECK (status IN ('A', 'I'))
+);
+CREATE TABLE orders (
+  id bigint DEFAULT nextval('orders_seq'),
+  customer_id bigint NOT NULL,
+  ordered_on timestamp(0) DEFAULT LOCALTIMESTAMP(0),
+  total numeric(12, 2) DEFAULT 0 NOT NULL,
+  note text,
+  CONSTRAINT pk_orders PRIMARY KEY (id),
+  CONSTRAINT ck_orders_total CHECK (total >= 0),
+  CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE
+);
+CREATE TABLE order_lines (
+  order_id bigint,
+  line_no integer,
+  product varchar(50) NOT NULL,
+  quantity integer DEFAULT 1 NOT NULL,
+  price numeric(10, 2),
+  image bytea,
+  CONSTRAINT pk_order_lines PRIMARY KEY (order_id, line_no),
+  FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE INDEX ix_orders_customer ON orders (customer_id, ordered_on DESC);
+CREATE UNIQUE INDEX ux_order_lines_product ON order_lines (order_id, product);
+
+COMMENT ON TABLE orders IS 'Orders of the customers';
+COMMENT ON COLUMN orders.total IS 'Total in the currency of the customer';
+
+CREATE VIEW customer_orders AS
+SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;
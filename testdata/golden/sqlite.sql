@@ -0,0 +1,35 @@
+CREATE TABLE customers (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  email text NOT NULL,
+  name text,
+  status text DEFAULT 'A' NOT NULL,
+  created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT uq_customers_email UNIQUE (email),
+  CONSTRAINT ck_customers_status CHECK (status IN ('A', 'I'))
+);
+CREATE TABLE orders (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  customer_id integer NOT NULL,
+  ordered_on datetime DEFAULT CURRENT_TIMESTAMP,
+  total numeric DEFAULT 0 NOT NULL,
+  note text,
+  CONSTRAINT ck_orders_total CHECK (total >= 0),
+  CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE
+);
+CREATE TABLE order_lines (
+  order_id integer,
+  line_no integer,
+  product text NOT NULL,
+  quantity integer DEFAULT 1 NOT NULL,
+  price numeric,
+  image blob,
+  CONSTRAINT pk_order_lines PRIMARY KEY (order_id, line_no),
+  FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE INDEX ix_orders_customer ON orders (customer_id, ordered_on DESC);
+CREATE UNIQUE INDEX ux_order_lines_product ON order_lines (order_id, product);
+
+CREATE VIEW customer_orders AS
+SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;
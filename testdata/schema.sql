@@ -0,0 +1,43 @@
+CREATE SEQUENCE orders_seq START WITH 1000 INCREMENT BY 1 CACHE 20;
+
+CREATE TABLE customers (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY,
+  email VARCHAR2(200) NOT NULL,
+  name NVARCHAR2(100),
+  status CHAR(1) DEFAULT 'A' NOT NULL,
+  created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL,
+  CONSTRAINT pk_customers PRIMARY KEY (id),
+  CONSTRAINT uq_customers_email UNIQUE (email),
+  CONSTRAINT ck_customers_status CHECK (status IN ('A', 'I'))
+);
+
+CREATE TABLE orders (
+  id NUMBER(10),
+  customer_id NUMBER(10) NOT NULL,
+  ordered_on DATE DEFAULT SYSDATE,
+  total NUMBER(12,2) DEFAULT 0 NOT NULL,
+  note CLOB,
+  CONSTRAINT pk_orders PRIMARY KEY (id),
+  CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE,
+  CONSTRAINT ck_orders_total CHECK (total >= 0)
+);
+
+CREATE TABLE order_lines (
+  order_id NUMBER(10) NOT NULL REFERENCES orders (id),
+  line_no NUMBER(5) NOT NULL,
+  product VARCHAR2(50) NOT NULL,
+  quantity NUMBER(8) DEFAULT 1 NOT NULL,
+  price NUMBER(10,2),
+  image BLOB,
+  CONSTRAINT pk_order_lines PRIMARY KEY (order_id, line_no)
+);
+
+CREATE INDEX ix_orders_customer ON orders (customer_id, ordered_on DESC);
+CREATE UNIQUE INDEX ux_order_lines_product ON order_lines (order_id, product);
+
+COMMENT ON TABLE orders IS 'Orders of the customers';
+COMMENT ON COLUMN orders.total IS 'Total in the currency of the customer';
+
+CREATE VIEW customer_orders AS
+SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;
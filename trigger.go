@@ -0,0 +1,257 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"golang.org/x/exp/slices"
+)
+
+// createTriggerStmt holds the header of a trigger, the body is kept as text.
+type createTriggerStmt struct {
+	sourceNode
+	Schema      string
+	Name        string
+	Timing      string
+	Events      []string
+	TableSchema string
+	Table       string
+	ForEachRow  bool
+	When        string
+	Body        string
+	// Assigned are the columns the body assigns to by ":NEW.col :=" or "SELECT ... INTO :NEW.col"
+	Assigned []string
+	// Conditional are the assigned columns the body only assigns within IF, CASE or a loop
+	Conditional []string
+	Assignments []sequenceAssignment
+}
+
+type dropTriggerStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+}
+
+var triggerTimings = []string{"BEFORE", "AFTER", "INSTEAD", "FOR"}
+
+// parseCreateTrigger reads CREATE [OR REPLACE] [EDITIONABLE] TRIGGER name timing events ON table
+// [REFERENCING ...] [FOR EACH ROW] [FOLLOWS | PRECEDES name] [ENABLE | DISABLE] [WHEN (condition)] body.
+func parseCreateTrigger(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	for r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	if !r.accept("TRIGGER") {
+		return nil, syntaxError(stmt, r.peek(), "expected TRIGGER")
+	}
+	trigger := &createTriggerStmt{sourceNode: sourceNode{text: stmt.Source}}
+	trigger.Schema, trigger.Name = r.qualifiedName()
+
+	if !slices.ContainsFunc(triggerTimings, r.peek().Is) {
+		return nil, syntaxError(stmt, r.peek(), "expected BEFORE, AFTER, INSTEAD OF or FOR")
+	}
+	trigger.Timing = r.next().Value()
+	if r.accept("OF") {
+		trigger.Timing = "INSTEAD OF"
+	}
+	for !r.done() && !r.peek().Is("ON") {
+		if t := r.next(); t.Is("INSERT") || t.Is("UPDATE") || t.Is("DELETE") {
+			trigger.Events = append(trigger.Events, t.Value())
+		}
+	}
+	if !r.accept("ON") {
+		return nil, syntaxError(stmt, r.peek(), "expected ON")
+	}
+	r.accept("NESTED", "TABLE", "OF")
+	trigger.TableSchema, trigger.Table = r.qualifiedName()
+	if trigger.Table == "SCHEMA" || trigger.Table == "DATABASE" {
+		// system and DDL triggers have no table
+		trigger.TableSchema, trigger.Table = "", ""
+	}
+
+	newName := "NEW"
+	for !r.done() {
+		switch {
+		case r.accept("REFERENCING"):
+			for r.peek().Is("OLD") || r.peek().Is("NEW") || r.peek().Is("PARENT") {
+				correlation := r.next()
+				r.accept("AS")
+				alias := r.next()
+				if correlation.Is("NEW") {
+					newName = alias.Value()
+				}
+			}
+		case r.accept("FOR", "EACH", "ROW"):
+			trigger.ForEachRow = true
+		case r.accept("FOLLOWS") || r.accept("PRECEDES"):
+			r.qualifiedName()
+			for r.accept(",") {
+				r.qualifiedName()
+			}
+		case r.accept("ENABLE") || r.accept("DISABLE") || r.accept("CROSSEDITION") || r.accept("FORWARD") || r.accept("REVERSE"):
+		case r.accept("WHEN"):
+			trigger.When = clauseText(stmt, r.group())
+		default:
+			trigger.Body = clauseText(stmt, r.tokens[r.pos:])
+			r.pos = len(r.tokens)
+		}
+	}
+
+	trigger.Assigned, trigger.Conditional = findNewAssignments(stmt.Tokens, newName)
+	trigger.Assignments = findSequenceAssignments(stmt.Tokens, newName)
+	return trigger, nil
+}
+
+// findNewAssignments returns the columns assigned by ":NEW.col := ..." or "SELECT ... INTO :NEW.a, :NEW.b",
+// and those of them which are only assigned within IF, CASE or a loop.
+func findNewAssignments(toks []token, newName string) (columns, conditional []string) {
+	isNewColumn := func(i int) bool {
+		return i >= 0 && i+3 < len(toks) && toks[i].Is(":") && toks[i+1].Is(newName) && toks[i+2].Is(".")
+	}
+	columns = []string{}
+	always := []string{}
+	// blocks are the BEGIN, IF, CASE and LOOP enclosing the token
+	blocks := []string{}
+	for i := 0; i < len(toks); i++ {
+		switch {
+		case toks[i].Is("END"):
+			if i+1 < len(toks) && (toks[i+1].Is("IF") || toks[i+1].Is("CASE") || toks[i+1].Is("LOOP")) {
+				i++
+			}
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		case toks[i].Is("BEGIN"), toks[i].Is("IF"), toks[i].Is("CASE"), toks[i].Is("LOOP"):
+			blocks = append(blocks, strings.ToUpper(toks[i].Text))
+			continue
+		}
+		if !isNewColumn(i) {
+			continue
+		}
+		assigned := i+4 < len(toks) && toks[i+4].Is(":=")
+		j := i - 1
+		for j >= 4 && toks[j].Is(",") && isNewColumn(j-4) {
+			j -= 5
+		}
+		if j >= 0 && toks[j].Is("INTO") {
+			assigned = true
+		}
+		if !assigned {
+			continue
+		}
+		column := toks[i+3].Value()
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+		if !slices.ContainsFunc(blocks, func(block string) bool { return block != "BEGIN" }) && !slices.Contains(always, column) {
+			always = append(always, column)
+		}
+	}
+	for _, column := range columns {
+		if !slices.Contains(always, column) {
+			conditional = append(conditional, column)
+		}
+	}
+	return columns, conditional
+}
+
+func parseDropTrigger(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	if !r.accept("DROP", "TRIGGER") {
+		return nil, syntaxError(stmt, r.peek(), "expected DROP TRIGGER")
+	}
+	r.accept("IF", "EXISTS")
+	node := &dropTriggerStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+// createTrigger attaches the trigger to its table or view and marks the columns its body assigns.
+func (b *schemaBuilder) createTrigger(stmt statement, node *createTriggerStmt) {
+	schema := node.Schema
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	if node.Table == "" {
+		b.diags.warnf(stmt, "trigger %v on the schema or the database ignored", node.Name)
+		return
+	}
+	table, ok := b.relation(node.TableSchema, node.Table)
+	if !ok {
+		b.diags.warnf(stmt, "trigger %v on %v ignored", node.Name, b.unknownTable(node.TableSchema, node.Table))
+		return
+	}
+	if existing := b.lookupTrigger(schema, node.Name); existing != nil {
+		b.dropTrigger(existing)
+	}
+
+	trigger := &Trigger{
+		Schema:     schema,
+		Name:       node.Name,
+		Timing:     node.Timing,
+		Events:     node.Events,
+		Table:      table,
+		ForEachRow: node.ForEachRow,
+		When:       node.When,
+		Body:       node.Body,
+		Span:       stmt.span(),
+	}
+	for _, name := range node.Assigned {
+		col := table.getColumn(name)
+		if col == nil {
+			b.diags.warnf(stmt, "trigger %v assigns unknown column %v.%v", node.Name, table.Table, name)
+			continue
+		}
+		trigger.Columns = append(trigger.Columns, col)
+		if slices.Contains(node.Conditional, name) {
+			trigger.Conditional = append(trigger.Conditional, col)
+		}
+		col.Triggers = append(col.Triggers, trigger)
+	}
+	for _, a := range node.Assignments {
+		// the assigned columns are warned about above, the columns of a view are not filled from a sequence
+		if col := table.getColumn(a.Column); col != nil && !table.IsView() {
+			b.linkSequence(stmt, col, a.Schema, a.Sequence, trigger)
+		}
+	}
+	table.Triggers = append(table.Triggers, trigger)
+	b.triggers = append(b.triggers, trigger)
+}
+
+func (b *schemaBuilder) lookupTrigger(schema, name string) *Trigger {
+	i := slices.IndexFunc(b.triggers, func(t *Trigger) bool {
+		return t.Name == name && (t.Schema == schema || schema == "" || t.Schema == "")
+	})
+	if i < 0 {
+		return nil
+	}
+	return b.triggers[i]
+}
+
+// dropTrigger removes the trigger from its table and from the columns it assigns.
+func (b *schemaBuilder) dropTrigger(trigger *Trigger) {
+	isTrigger := func(t *Trigger) bool { return t == trigger }
+	b.triggers = slices.DeleteFunc(b.triggers, isTrigger)
+	trigger.Table.Triggers = slices.DeleteFunc(trigger.Table.Triggers, isTrigger)
+	for _, col := range trigger.Columns {
+		col.Triggers = slices.DeleteFunc(col.Triggers, isTrigger)
+	}
+	b.unlinkSequences(func(link sequenceLink) bool { return link.trigger == trigger })
+}
+
+// dropTableTriggers drops the triggers of a dropped table.
+func (b *schemaBuilder) dropTableTriggers(table *Table) {
+	b.triggers = slices.DeleteFunc(b.triggers, func(t *Trigger) bool { return t.Table == table })
+}
+
+// String describes the trigger as "BEFORE INSERT OR UPDATE FOR EACH ROW".
+func (t *Trigger) String() string {
+	text := fmt.Sprintf("%v %v", t.Timing, strings.Join(t.Events, " OR "))
+	if t.ForEachRow {
+		text += " FOR EACH ROW"
+	}
+	return text
+}
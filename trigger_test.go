@@ -0,0 +1,142 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestParseCreateTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		want    createTriggerStmt
+	}{
+		{
+			name: "before insert or update",
+			trigger: `CREATE OR REPLACE TRIGGER app.t_audit BEFORE INSERT OR UPDATE ON app.t FOR EACH ROW
+BEGIN
+  :NEW.modified := SYSDATE;
+  IF INSERTING THEN
+    :NEW.created := SYSDATE;
+  END IF;
+END;`,
+			want: createTriggerStmt{Schema: "APP", Name: "T_AUDIT", Timing: "BEFORE", Events: []string{"INSERT", "UPDATE"},
+				TableSchema: "APP", Table: "T", ForEachRow: true, Assigned: []string{"MODIFIED", "CREATED"}, Conditional: []string{"CREATED"}},
+		},
+		{
+			name: "referencing and when",
+			trigger: `CREATE TRIGGER t_bi BEFORE INSERT ON t REFERENCING NEW AS n OLD AS o FOR EACH ROW WHEN (n.id IS NULL)
+BEGIN
+  SELECT s.NEXTVAL INTO :n.id FROM dual;
+END;`,
+			want: createTriggerStmt{Name: "T_BI", Timing: "BEFORE", Events: []string{"INSERT"}, Table: "T", ForEachRow: true,
+				When: "n.id IS NULL", Assigned: []string{"ID"}},
+		},
+		{
+			name:    "instead of on a view",
+			trigger: `CREATE TRIGGER v_io INSTEAD OF DELETE ON v BEGIN NULL; END;`,
+			want:    createTriggerStmt{Name: "V_IO", Timing: "INSTEAD OF", Events: []string{"DELETE"}, Table: "V", Assigned: []string{}},
+		},
+		{
+			name:    "after update of columns",
+			trigger: `CREATE TRIGGER t_au AFTER UPDATE OF a, b ON t FOR EACH ROW BEGIN log_change(:OLD.a); END;`,
+			want:    createTriggerStmt{Name: "T_AU", Timing: "AFTER", Events: []string{"UPDATE"}, Table: "T", ForEachRow: true, Assigned: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := splitStatements(tt.trigger)
+			node, err := parseCreateTrigger(stmts[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := node.(*createTriggerStmt)
+			if got.Schema != tt.want.Schema || got.Name != tt.want.Name || got.Timing != tt.want.Timing ||
+				!slices.Equal(got.Events, tt.want.Events) || got.TableSchema != tt.want.TableSchema || got.Table != tt.want.Table ||
+				got.ForEachRow != tt.want.ForEachRow || got.When != tt.want.When {
+				t.Errorf("got header %v.%v %v %v ON %v.%v row %v when %q", got.Schema, got.Name, got.Timing, got.Events,
+					got.TableSchema, got.Table, got.ForEachRow, got.When)
+			}
+			if !slices.Equal(got.Assigned, tt.want.Assigned) || !slices.Equal(got.Conditional, tt.want.Conditional) {
+				t.Errorf("got assigned %v conditional %v, want %v and %v", got.Assigned, got.Conditional, tt.want.Assigned, tt.want.Conditional)
+			}
+			if got.Body == "" {
+				t.Errorf("body not kept")
+			}
+		})
+	}
+}
+
+func TestTriggerPermissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		// want maps the columns to their gorm permission and JPA permissions
+		want map[string][2]string
+	}{
+		{
+			name: "assigned on insert and update",
+			script: `CREATE TABLE t (id NUMBER, created DATE, modified DATE, note VARCHAR2(10), kind CHAR(1));
+CREATE TRIGGER t_bi BEFORE INSERT ON t FOR EACH ROW
+BEGIN
+  :NEW.created := SYSDATE;
+  IF :NEW.kind IS NULL THEN
+    :NEW.kind := 'A';
+  END IF;
+END;
+/
+CREATE TRIGGER t_biu BEFORE INSERT OR UPDATE ON t FOR EACH ROW
+BEGIN
+  :NEW.modified := SYSDATE;
+END;
+/`,
+			want: map[string][2]string{
+				"CREATED":  {";<-:update", ", insertable = false"},
+				"MODIFIED": {";<-:false", ", insertable = false, updatable = false"},
+				"NOTE":     {"", ""},
+				"KIND":     {"", ""},
+			},
+		},
+		{
+			name: "after and when",
+			script: `CREATE TABLE t (a NUMBER, b NUMBER);
+CREATE TRIGGER t_ai AFTER INSERT ON t FOR EACH ROW BEGIN :NEW.a := 1; END;
+/
+CREATE TRIGGER t_bu BEFORE UPDATE ON t FOR EACH ROW WHEN (new.b IS NULL) BEGIN :NEW.b := 0; END;
+/`,
+			want: map[string][2]string{"A": {"", ""}, "B": {"", ""}},
+		},
+		{
+			name: "dropped and replaced",
+			script: `CREATE TABLE t (a NUMBER, b NUMBER);
+CREATE TRIGGER t_bi BEFORE INSERT ON t FOR EACH ROW BEGIN :NEW.a := 1; END;
+/
+CREATE OR REPLACE TRIGGER t_bi BEFORE UPDATE ON t FOR EACH ROW BEGIN :NEW.b := 1; END;
+/
+CREATE TRIGGER t_bu BEFORE UPDATE ON t FOR EACH ROW BEGIN :NEW.a := 2; END;
+/
+DROP TRIGGER t_bu;`,
+			want: map[string][2]string{"A": {"", ""}, "B": {";<-:create", ", updatable = false"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := Parse(tt.script)
+			if err != nil || len(diags) > 0 {
+				t.Fatalf("parse: %v %v", err, diags)
+			}
+			for _, col := range db.Tables[0].Columns {
+				want, ok := tt.want[col.Name]
+				if !ok {
+					continue
+				}
+				if got := [2]string{toTriggerPermission(col), toColumnPermissions(col)}; got != want {
+					t.Errorf("%v got permissions %q, want %q", col.Name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package ddlcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// DataDefUserType is the DataDef of UserDatatype, the oracle parser knows built-in data types only.
+const DataDefUserType element.DataDef = -1
+
+// UserDatatype is the data type of a column, an attribute or a collection element of a user-defined type,
+// Type is nil while the type is not in the schema.
+type UserDatatype struct {
+	Schema string
+	Name   string
+	// Ref is set for REF type, a reference to an object instead of the object
+	Ref  bool
+	Type *UserType
+}
+
+func (d *UserDatatype) DataDef() element.DataDef {
+	return DataDefUserType
+}
+
+// String prints the data type as in the DDL, e.g. "REF HR.ADDRESS_T".
+func (d *UserDatatype) String() string {
+	name := qualifiedName(d.Schema, d.Name)
+	if d.Ref {
+		return "REF " + name
+	}
+	return name
+}
+
+// IsPlaceholder reports whether the generators cannot map the data type, that is a REF
+// or a type whose definition is not in the schema.
+func (d *UserDatatype) IsPlaceholder() bool {
+	return d.Ref || d.Type == nil
+}
+
+type createTypeStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+	// Body is set for CREATE TYPE BODY, the methods are not part of the model
+	Body bool
+	// Kind is OBJECT, VARRAY or TABLE, empty for an incomplete type
+	Kind            string
+	SupertypeSchema string
+	Supertype       string
+	Attributes      []*Column
+	Element         *Column
+	Limit           int
+	Final           bool
+}
+
+type dropTypeStmt struct {
+	sourceNode
+	Schema string
+	Name   string
+	Body   bool
+}
+
+// builtinTypeWords start the built-in data types, any other name in the place of a data type is a user-defined type.
+var builtinTypeWords = []string{
+	"CHAR", "VARCHAR2", "NCHAR", "NVARCHAR2", "NUMBER", "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE", "LONG", "RAW", "DATE",
+	"TIMESTAMP", "INTERVAL", "BLOB", "CLOB", "NCLOB", "BFILE", "ROWID", "UROWID", "CHARACTER", "VARCHAR", "NATIONAL",
+	"NUMERIC", "DECIMAL", "DEC", "INTEGER", "INT", "SMALLINT", "DOUBLE", "REAL", "XMLTYPE",
+}
+
+// columnKeywords may follow a column name in place of a data type.
+var columnKeywords = []string{"AS", "GENERATED", "DEFAULT", "NOT", "NULL", "CONSTRAINT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "VISIBLE", "INVISIBLE", "COLLATE", "SORT"}
+
+// userDatatype reads "[REF] [schema.]name" in the place of a data type, it returns nil and 0 for a built-in type.
+func userDatatype(toks []token) (*UserDatatype, int) {
+	r := newTokenReader(toks)
+	ref := r.accept("REF")
+	if r.done() || !isIdentifier(r.peek()) {
+		return nil, 0
+	}
+	if !ref && r.peek().Kind == tokenWord && (slices.ContainsFunc(builtinTypeWords, r.peek().Is) || slices.ContainsFunc(columnKeywords, r.peek().Is)) {
+		return nil, 0
+	}
+	if ref && r.peek().Is("SYS") {
+		return nil, 0
+	}
+	schema, name := r.qualifiedName()
+	return &UserDatatype{Schema: schema, Name: name, Ref: ref}, r.pos
+}
+
+// typePlaceholder stands for a user-defined type in the source handed to the oracle parser.
+const typePlaceholder = "DATE"
+
+// substituteUserTypes writes the placeholder in place of the user-defined types of the columns. It comes last
+// as it moves the text following a type name shorter than the placeholder.
+func (c *tableClauses) substituteUserTypes(source string, base int) string {
+	spans := [][]token{}
+	for _, clause := range c.Columns {
+		if clause.UserType != nil {
+			spans = append(spans, clause.typeTokens)
+		}
+	}
+	slices.SortFunc(spans, func(x, y []token) int { return y[0].Offset - x[0].Offset })
+	for _, toks := range spans {
+		start := toks[0].Offset - base
+		end := toks[len(toks)-1].End() - base
+		source = source[:start] + fmt.Sprintf("%-*v", end-start, typePlaceholder) + source[end:]
+	}
+	return source
+}
+
+// typeModifiers end the specification of an object type or of a collection type.
+var typeModifiers = []string{"NOT", "FINAL", "INSTANTIABLE", "PERSISTABLE"}
+
+// methodWords start the methods of an object type, they are left out of the attributes.
+var methodWords = []string{"MEMBER", "STATIC", "CONSTRUCTOR", "MAP", "ORDER", "FINAL", "NOT", "OVERRIDING", "INSTANTIABLE", "PRAGMA"}
+
+// parseCreateType reads CREATE [OR REPLACE] [EDITIONABLE | NONEDITIONABLE] TYPE [schema.]name [FORCE] [OID '...']
+// [AUTHID ...] followed by AS OBJECT (attributes), UNDER supertype (attributes), AS VARRAY (n) OF type
+// or AS TABLE OF type. The attributes and the element are parsed as the columns of a table.
+func parseCreateType(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	for r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	if !r.accept("TYPE") {
+		return nil, syntaxError(stmt, r.peek(), "expected TYPE")
+	}
+	node := &createTypeStmt{sourceNode: sourceNode{text: stmt.Source}, Final: true}
+	node.Body = r.accept("BODY")
+	r.accept("IF", "NOT", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	if node.Body {
+		return node, nil
+	}
+	for !r.done() && !r.peek().Is("AS") && !r.peek().Is("IS") && !r.peek().Is("UNDER") {
+		if r.peek().Is("(") {
+			r.group()
+		} else {
+			r.next()
+		}
+	}
+	if r.done() {
+		// an incomplete type declared ahead of the types referring to it
+		return node, nil
+	}
+
+	switch {
+	case r.accept("UNDER"):
+		node.Kind = "OBJECT"
+		node.SupertypeSchema, node.Supertype = r.qualifiedName()
+	case r.accept("AS", "OBJECT"), r.accept("IS", "OBJECT"):
+		node.Kind = "OBJECT"
+	case r.accept("AS", "VARRAY"), r.accept("IS", "VARRAY"), r.accept("AS", "VARYING", "ARRAY"), r.accept("IS", "VARYING", "ARRAY"):
+		node.Kind = "VARRAY"
+		limit := r.group()
+		if len(limit) == 1 {
+			node.Limit, _ = strconv.Atoi(limit[0].Text)
+		}
+	case r.accept("AS", "TABLE"), r.accept("IS", "TABLE"):
+		node.Kind = "TABLE"
+	default:
+		return nil, syntaxError(stmt, r.peek(), "expected OBJECT, VARRAY, TABLE or UNDER")
+	}
+
+	if node.Kind == "OBJECT" {
+		items := [][]token{}
+		for _, item := range splitTopLevel(r.group(), ",") {
+			if len(item) > 0 && !slices.ContainsFunc(methodWords, item[0].Is) {
+				items = append(items, item)
+			}
+		}
+		attributes, err := parseTypeColumns(stmt, items)
+		if err != nil {
+			return nil, err
+		}
+		node.Attributes = attributes
+	} else {
+		if !r.accept("OF") {
+			return nil, syntaxError(stmt, r.peek(), "expected OF")
+		}
+		start := r.pos
+		for !r.done() && !slices.ContainsFunc(typeModifiers, r.peek().Is) {
+			if r.peek().Is("(") {
+				r.group()
+			} else {
+				r.next()
+			}
+		}
+		if start == r.pos {
+			return nil, syntaxError(stmt, r.peek(), "expected the element type")
+		}
+		element := append([]token{{Kind: tokenWord, Text: "COLUMN_VALUE", Offset: r.tokens[start].Offset, Line: r.tokens[start].Line, Column: r.tokens[start].Column}}, r.tokens[start:r.pos]...)
+		if r.accept("NOT", "NULL") {
+			element = append(element, tokenize("NOT NULL")...)
+		}
+		columns, err := parseTypeColumns(stmt, [][]token{element})
+		if err != nil {
+			return nil, err
+		}
+		node.Element = columns[0]
+	}
+	for !r.done() {
+		if r.accept("NOT", "FINAL") {
+			node.Final = false
+		} else {
+			r.next()
+		}
+	}
+	return node, nil
+}
+
+// parseTypeColumns parses the items "name datatype [NOT NULL]" as the columns of a table,
+// their names are upper-cased unless quoted.
+func parseTypeColumns(stmt statement, items [][]token) ([]*Column, error) {
+	texts := []string{}
+	for _, item := range items {
+		words := []string{}
+		for _, t := range item {
+			words = append(words, t.Text)
+		}
+		texts = append(texts, strings.Join(words, " "))
+	}
+	if len(texts) == 0 {
+		return []*Column{}, nil
+	}
+	source := "CREATE TABLE x (" + strings.Join(texts, ", ") + ")"
+	sub := statement{Index: stmt.Index, File: stmt.File, Source: source, Tokens: tokenize(source)}
+	blanked, clauses := scanColumnClauses(sub)
+	nodes, err := parseOracle(clauses.substituteUserTypes(blanked, 0))
+	if err != nil || len(nodes) == 0 || castCreateTableStmt(nodes[0]) == nil {
+		return nil, syntaxError(stmt, items[0][0], "invalid attributes or element type")
+	}
+	return translateTable(castCreateTableStmt(nodes[0]), clauses.Columns).Columns, nil
+}
+
+func parseDropType(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	if !r.accept("DROP", "TYPE") {
+		return nil, syntaxError(stmt, r.peek(), "expected DROP TYPE")
+	}
+	node := &dropTypeStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Body = r.accept("BODY")
+	r.accept("IF", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+// isCreateType reports whether the leading words are those of CREATE TYPE or CREATE TYPE BODY.
+func isCreateType(words []string) bool {
+	if len(words) == 0 || words[0] != "CREATE" {
+		return false
+	}
+	for _, word := range words[1:] {
+		switch word {
+		case "TYPE":
+			return true
+		case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// createType adds the type, a type created again replaces the former one for the columns of that type.
+func (b *schemaBuilder) createType(stmt statement, node *createTypeStmt) {
+	if node.Body {
+		return
+	}
+	schema := node.Schema
+	if schema == "" {
+		schema = b.defaultSchema
+	}
+	if node.Kind == "" {
+		return
+	}
+	existing := b.types[qualifiedName(schema, node.Name)]
+	userType := &UserType{
+		Schema:     schema,
+		Name:       node.Name,
+		Kind:       node.Kind,
+		Attributes: node.Attributes,
+		Element:    node.Element,
+		Limit:      node.Limit,
+		Final:      node.Final,
+		Span:       stmt.span(),
+	}
+	if node.Supertype != "" {
+		supertype, ok := lookupThroughSynonyms(b, b.types, node.SupertypeSchema, node.Supertype)
+		if !ok {
+			b.diags.warnf(stmt, "type %v under unknown type %v", node.Name, qualifiedName(node.SupertypeSchema, node.Supertype))
+		}
+		userType.Supertype = supertype
+	}
+	columns := slices.Clone(userType.Attributes)
+	if userType.Element != nil {
+		columns = append(columns, userType.Element)
+	}
+	for _, col := range columns {
+		col.Schema, col.Table = schema, node.Name
+	}
+	b.resolveUserTypes(stmt, columns)
+
+	b.types[userType.QualifiedName()] = userType
+	b.declared[userType] = stmt.Index
+	if existing != nil {
+		b.replaceUserType(existing, userType)
+	}
+}
+
+// resolveUserTypes links the columns of user-defined types to their types, columns of unknown types
+// are kept with a nil type.
+func (b *schemaBuilder) resolveUserTypes(stmt statement, columns []*Column) {
+	for _, col := range columns {
+		datatype, ok := col.DataType.(*UserDatatype)
+		if !ok {
+			continue
+		}
+		userType, ok := lookupThroughSynonyms(b, b.types, datatype.Schema, datatype.Name)
+		if !ok {
+			b.diags.warnf(stmt, "column %v.%v of unknown type %v", col.Table, col.Name, datatype)
+			continue
+		}
+		datatype.Type = userType
+	}
+}
+
+// replaceUserType points the columns and the subtypes of the former type to the new one.
+func (b *schemaBuilder) replaceUserType(former, userType *UserType) {
+	replace := func(col *Column) {
+		if datatype, ok := col.DataType.(*UserDatatype); ok && datatype.Type == former {
+			datatype.Type = userType
+		}
+	}
+	for _, table := range b.tables {
+		for _, col := range table.Columns {
+			replace(col)
+		}
+	}
+	for _, t := range b.types {
+		for _, col := range t.Attributes {
+			replace(col)
+		}
+		if t.Element != nil {
+			replace(t.Element)
+		}
+		if t.Supertype == former {
+			t.Supertype = userType
+		}
+	}
+}
+
+func (b *schemaBuilder) dropType(stmt statement, node *dropTypeStmt) {
+	if node.Body {
+		return
+	}
+	userType, ok := lookupQualified(b.types, b.defaultSchema, node.Schema, node.Name)
+	if !ok {
+		b.diags.warnf(stmt, "drop of unknown type %v ignored", qualifiedName(node.Schema, node.Name))
+		return
+	}
+	delete(b.types, userType.QualifiedName())
+}
+
+func (b *schemaBuilder) userTypes() []*UserType {
+	types := maps.Values(b.types)
+	slices.SortFunc(types, func(x, y *UserType) int { return b.declared[x] - b.declared[y] })
+	return types
+}
+
+// QualifiedName identifies the type by schema and name.
+func (t *UserType) QualifiedName() string {
+	return qualifiedName(t.Schema, t.Name)
+}
+
+// IsCollection reports whether the type is a VARRAY or a nested table.
+func (t *UserType) IsCollection() bool {
+	return t.Kind == "VARRAY" || t.Kind == "TABLE"
+}
+
+// AllAttributes returns the attributes of the supertypes followed by those of the type.
+func (t *UserType) AllAttributes() []*Column {
+	if t.Supertype == nil {
+		return t.Attributes
+	}
+	return append(slices.Clone(t.Supertype.AllAttributes()), t.Attributes...)
+}
+
+// ObjectTypes returns the object types the columns are of, directly, as collection elements or as attributes
+// of other object types, each once and ahead of the types containing it.
+func ObjectTypes(columns []*Column) []*UserType {
+	types := []*UserType{}
+	visited := map[*UserType]bool{}
+	var visit func(col *Column)
+	visit = func(col *Column) {
+		datatype, ok := col.DataType.(*UserDatatype)
+		if !ok || datatype.IsPlaceholder() || visited[datatype.Type] {
+			return
+		}
+		visited[datatype.Type] = true
+		if datatype.Type.IsCollection() {
+			visit(datatype.Type.Element)
+			return
+		}
+		for _, attribute := range datatype.Type.AllAttributes() {
+			visit(attribute)
+		}
+		types = append(types, datatype.Type)
+	}
+	for _, col := range columns {
+		visit(col)
+	}
+	return types
+}
+
+// placeholderTypes returns the data types of the columns the generators cannot map, each name once.
+func placeholderTypes(columns []*Column) []*UserDatatype {
+	placeholders := []*UserDatatype{}
+	visited := map[*UserType]bool{}
+	var visit func(col *Column)
+	visit = func(col *Column) {
+		datatype, ok := col.DataType.(*UserDatatype)
+		if !ok || visited[datatype.Type] {
+			return
+		}
+		if datatype.IsPlaceholder() {
+			if !slices.ContainsFunc(placeholders, func(d *UserDatatype) bool { return d.String() == datatype.String() }) {
+				placeholders = append(placeholders, datatype)
+			}
+			return
+		}
+		visited[datatype.Type] = true
+		if datatype.Type.IsCollection() {
+			visit(datatype.Type.Element)
+			return
+		}
+		for _, attribute := range datatype.Type.AllAttributes() {
+			visit(attribute)
+		}
+	}
+	for _, col := range columns {
+		visit(col)
+	}
+	return placeholders
+}
@@ -0,0 +1,365 @@
+package ddlcode
+
+import (
+	"strings"
+
+	"github.com/codeindex2937/oracle-sql-parser/ast"
+	"github.com/codeindex2937/oracle-sql-parser/ast/element"
+	"golang.org/x/exp/slices"
+)
+
+type createViewStmt struct {
+	sourceNode
+	Schema       string
+	Name         string
+	Materialized bool
+	// Columns is the column list given after the view name, empty when the query names the columns
+	Columns []string
+	// PrimaryKey are the columns of a primary key declared in the column list
+	PrimaryKey []string
+	Query      string
+	Items      []selectItem
+	From       []fromItem
+	// OuterJoin is set when the query joins a table by LEFT, RIGHT or FULL JOIN or by (+)
+	OuterJoin bool
+}
+
+// selectItem is an item of the select list of a view query.
+type selectItem struct {
+	Name string
+	// Qualifier is the table or alias of a column reference or of "t.*"
+	Qualifier string
+	// Column is set when the item refers to a column
+	Column string
+	Star   bool
+	// Unnamed is set for an expression without an alias, Name is then the expression text
+	Unnamed bool
+}
+
+// fromItem is a table of the FROM clause, Table is empty for subqueries.
+type fromItem struct {
+	Schema string
+	Table  string
+	Alias  string
+}
+
+type dropViewStmt struct {
+	sourceNode
+	Schema       string
+	Name         string
+	Materialized bool
+}
+
+// isCreateView reports whether the leading keywords are those of CREATE [MATERIALIZED] VIEW,
+// materialized view logs are not views.
+func isCreateView(words []string) bool {
+	if len(words) == 0 || words[0] != "CREATE" {
+		return false
+	}
+	index := slices.Index(words, "VIEW")
+	if index < 0 {
+		return false
+	}
+	return index+1 >= len(words) || words[index+1] != "LOG"
+}
+
+// parseCreateView reads CREATE [OR REPLACE] [[NO] FORCE] [EDITIONING] [MATERIALIZED] VIEW name [(columns)] ... AS query,
+// the query is only read as far as needed to name the columns and to find the tables they come from.
+func parseCreateView(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("CREATE")
+	r.accept("OR", "REPLACE")
+	if !r.accept("FORCE") {
+		r.accept("NO", "FORCE")
+	}
+	for r.accept("EDITIONING") || r.accept("EDITIONABLE") || r.accept("NONEDITIONABLE") {
+	}
+	view := &createViewStmt{sourceNode: sourceNode{text: stmt.Source}}
+	view.Materialized = r.accept("MATERIALIZED")
+	if !r.accept("VIEW") {
+		return nil, syntaxError(stmt, r.peek(), "expected VIEW")
+	}
+	r.accept("IF", "NOT", "EXISTS")
+	view.Schema, view.Name = r.qualifiedName()
+	for _, item := range splitTopLevel(r.group(), ",") {
+		if len(item) == 0 {
+			continue
+		}
+		key := newTokenReader(item)
+		column := ""
+		if !isOutOfLineConstraintStart(item[0]) {
+			// a column, possibly with an inline constraint
+			column = key.next().Value()
+			view.Columns = append(view.Columns, column)
+		}
+		if key.accept("CONSTRAINT") {
+			key.next()
+		}
+		if !key.accept("PRIMARY", "KEY") {
+			continue
+		}
+		if column != "" {
+			view.PrimaryKey = append(view.PrimaryKey, column)
+			continue
+		}
+		for _, col := range splitTopLevel(key.group(), ",") {
+			if len(col) > 0 {
+				view.PrimaryKey = append(view.PrimaryKey, col[0].Value())
+			}
+		}
+	}
+
+	// skip the view and materialized view properties
+	depth := 0
+	for ; !r.done(); r.next() {
+		t := r.peek()
+		if t.Is("(") {
+			depth += 1
+		} else if t.Is(")") {
+			depth -= 1
+		} else if depth == 0 && t.Is("AS") {
+			break
+		}
+	}
+	if !r.accept("AS") {
+		return nil, syntaxError(stmt, r.peek(), "expected AS")
+	}
+	query := r.tokens[r.pos:]
+	query = trimQueryRestriction(query)
+	if len(query) == 0 {
+		return nil, syntaxError(stmt, r.peek(), "expected query")
+	}
+	view.Query = stmt.Source[query[0].Offset-stmt.Offset : query[len(query)-1].End()-stmt.Offset]
+	view.Items, view.From = parseSelect(query)
+	view.OuterJoin = hasOuterJoin(query)
+	return view, nil
+}
+
+// hasOuterJoin looks for LEFT, RIGHT or FULL [OUTER] JOIN and for the (+) of Oracle outer joins.
+func hasOuterJoin(toks []token) bool {
+	for i, t := range toks {
+		if i+1 >= len(toks) {
+			break
+		}
+		next := toks[i+1]
+		switch {
+		case (t.Is("LEFT") || t.Is("RIGHT") || t.Is("FULL")) && (next.Is("JOIN") || next.Is("OUTER")):
+			return true
+		case t.Is("(") && next.Is("+") && i+2 < len(toks) && toks[i+2].Is(")"):
+			return true
+		}
+	}
+	return false
+}
+
+// trimQueryRestriction drops a trailing WITH READ ONLY or WITH CHECK OPTION [CONSTRAINT name].
+func trimQueryRestriction(toks []token) []token {
+	depth := 0
+	for i, t := range toks {
+		switch {
+		case t.Is("("):
+			depth += 1
+		case t.Is(")"):
+			depth -= 1
+		case depth == 0 && t.Is("WITH") && i+1 < len(toks) && (toks[i+1].Is("READ") || toks[i+1].Is("CHECK")):
+			return toks[:i]
+		}
+	}
+	return toks
+}
+
+// parseSelect reads the select list and the FROM clause of the first query block,
+// subquery factoring and parentheses around the query are skipped.
+func parseSelect(toks []token) ([]selectItem, []fromItem) {
+	r := newTokenReader(toks)
+	for r.peek().Is("(") {
+		r = newTokenReader(r.group())
+	}
+	if r.accept("WITH") {
+		for !r.done() && !r.peek().Is("SELECT") {
+			if r.peek().Is("(") {
+				r.group()
+			} else {
+				r.next()
+			}
+		}
+	}
+	if !r.accept("SELECT") {
+		return nil, nil
+	}
+	if !r.accept("DISTINCT") && !r.accept("UNIQUE") {
+		r.accept("ALL")
+	}
+
+	rest := r.tokens[r.pos:]
+	end := topLevelIndex(rest, "FROM")
+	items := []selectItem{}
+	for _, item := range splitTopLevel(rest[:end], ",") {
+		if len(item) > 0 {
+			items = append(items, parseSelectItem(item))
+		}
+	}
+	if end >= len(rest) {
+		return items, nil
+	}
+	return items, parseFrom(rest[end+1:])
+}
+
+// topLevelIndex returns the index of the keyword outside of parentheses or len(toks).
+func topLevelIndex(toks []token, keyword string) int {
+	depth := 0
+	for i, t := range toks {
+		switch {
+		case t.Is("("):
+			depth += 1
+		case t.Is(")"):
+			depth -= 1
+		case depth == 0 && t.Is(keyword):
+			return i
+		}
+	}
+	return len(toks)
+}
+
+func isIdentifier(t token) bool {
+	return t.Kind == tokenWord || t.Kind == tokenQuotedIdent
+}
+
+// parseSelectItem names a select item by its alias, by its column or by its expression.
+func parseSelectItem(item []token) selectItem {
+	n := len(item)
+	if n >= 2 && isIdentifier(item[n-1]) && !item[n-1].Is("END") {
+		before := item[n-2]
+		if before.Is("AS") || isIdentifier(before) || before.Kind == tokenString || before.Kind == tokenNumber || before.Is(")") {
+			expr := item[:n-1]
+			if before.Is("AS") {
+				expr = item[:n-2]
+			}
+			ref := parseSelectItem(expr)
+			ref.Name = item[n-1].Value()
+			ref.Star = false
+			ref.Unnamed = false
+			return ref
+		}
+	}
+
+	// a column is "col", "t.col" or "schema.t.col"
+	isColumnRef := n%2 == 1
+	for i, t := range item {
+		if (i%2 == 0 && !isIdentifier(t) && !t.Is("*")) || (i%2 == 1 && !t.Is(".")) || (t.Is("*") && i != n-1) {
+			isColumnRef = false
+		}
+	}
+	if !isColumnRef || n > 5 {
+		text := []string{}
+		for _, t := range item {
+			text = append(text, t.Text)
+		}
+		return selectItem{Name: strings.Join(text, ""), Unnamed: true}
+	}
+
+	ref := selectItem{}
+	if n >= 3 {
+		ref.Qualifier = item[n-3].Value()
+	}
+	if item[n-1].Is("*") {
+		ref.Star = true
+		return ref
+	}
+	ref.Column = item[n-1].Value()
+	ref.Name = ref.Column
+	return ref
+}
+
+var fromClauseEnd = []string{"WHERE", "GROUP", "HAVING", "ORDER", "CONNECT", "START", "UNION", "INTERSECT", "MINUS", "EXCEPT", "FETCH", "OFFSET", "WINDOW", "MODEL"}
+var joinKeywords = []string{"JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "OUTER", "ON", "USING", "PARTITION"}
+
+// parseFrom reads the tables of a FROM clause joined by commas or by JOIN.
+func parseFrom(toks []token) []fromItem {
+	items := []fromItem{}
+	r := newTokenReader(toks)
+	for !r.done() {
+		if slices.ContainsFunc(fromClauseEnd, r.peek().Is) {
+			break
+		}
+
+		item := fromItem{}
+		if r.peek().Is("(") {
+			r.group()
+		} else if isIdentifier(r.peek()) {
+			item.Schema, item.Table = r.qualifiedName()
+			if r.peek().Is("@") {
+				// database link
+				r.next()
+				r.next()
+			}
+		}
+		r.accept("AS")
+		if isIdentifier(r.peek()) && !slices.ContainsFunc(joinKeywords, r.peek().Is) && !slices.ContainsFunc(fromClauseEnd, r.peek().Is) {
+			item.Alias = r.next().Value()
+		}
+		items = append(items, item)
+
+		// skip join conditions up to the next table
+		for !r.done() && !slices.ContainsFunc(fromClauseEnd, r.peek().Is) {
+			t := r.next()
+			if t.Is("(") {
+				r.pos -= 1
+				r.group()
+			} else if t.Is(",") || t.Is("JOIN") || t.Is("APPLY") {
+				break
+			}
+		}
+	}
+	return items
+}
+
+func parseDropView(stmt statement) (ast.Node, error) {
+	r := newTokenReader(stmt.Tokens)
+	r.accept("DROP")
+	node := &dropViewStmt{sourceNode: sourceNode{text: stmt.Source}}
+	node.Materialized = r.accept("MATERIALIZED")
+	if !r.accept("VIEW") {
+		return nil, syntaxError(stmt, r.peek(), "expected VIEW")
+	}
+	r.accept("IF", "EXISTS")
+	node.Schema, node.Name = r.qualifiedName()
+	return node, nil
+}
+
+// viewColumn derives the column of a view from the column it selects, expressions get a character column.
+// The column takes the type and the nullability of the source but not its keys, a column of an outer join
+// is nullable.
+func viewColumn(view *View, name string, source *Column, outerJoin bool) *Column {
+	col := &Column{
+		Name:      name,
+		Schema:    view.Schema,
+		Table:     view.Name,
+		Attribute: AttributeMap{},
+		Source:    source,
+	}
+	if source == nil {
+		datatype := &element.Varchar2{}
+		datatype.SetDataDef(element.DataDefVarchar2)
+		col.DataType = datatype
+		col.Type = typeStr(datatype.DataDef())
+		col.Nullable = "true"
+		return col
+	}
+
+	col.DataType = source.DataType
+	col.Type = source.Type
+	col.CharacterMaximumLength = source.CharacterMaximumLength
+	col.Precision = source.Precision
+	col.Comment = source.Comment
+	col.Checks = source.Checks
+	// a primary key column is not null without saying so, the view column says it
+	if outerJoin || source.IsNullable() {
+		col.Nullable = "true"
+		col.Attribute[ast.ConstraintTypeNull] = nil
+	} else {
+		col.Nullable = "false"
+		col.Attribute[ast.ConstraintTypeNotNull] = nil
+	}
+	return col
+}
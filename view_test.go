@@ -0,0 +1,89 @@
+package ddlcode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestViewColumns(t *testing.T) {
+	tables := `CREATE TABLE customers (id NUMBER(10) PRIMARY KEY, email VARCHAR2(200) NOT NULL);
+CREATE TABLE orders (id NUMBER(10) PRIMARY KEY, customer_id NUMBER(10) NOT NULL REFERENCES customers (id), total NUMBER(12,2));
+`
+	tests := []struct {
+		name string
+		view string
+		// want lists the columns as "NAME type", with " PK" and " NOT NULL" when set
+		want       []string
+		primaryKey []string
+		diags      []string
+	}{
+		{
+			name: "join",
+			view: `CREATE VIEW customer_orders AS SELECT c.id AS customer_id, c.email, o.id AS order_id, o.total
+FROM customers c JOIN orders o ON o.customer_id = c.id;`,
+			want: []string{"CUSTOMER_ID number NOT NULL", "EMAIL varchar NOT NULL", "ORDER_ID number NOT NULL", "TOTAL number"},
+		},
+		{
+			name: "outer join",
+			view: `CREATE VIEW customer_orders AS SELECT c.id AS customer_id, o.id AS order_id
+FROM customers c LEFT JOIN orders o ON o.customer_id = c.id;`,
+			want: []string{"CUSTOMER_ID number", "ORDER_ID number"},
+		},
+		{
+			name: "oracle outer join",
+			view: `CREATE VIEW customer_orders AS SELECT c.id AS customer_id, o.id AS order_id
+FROM customers c, orders o WHERE o.customer_id (+) = c.id;`,
+			want: []string{"CUSTOMER_ID number", "ORDER_ID number"},
+		},
+		{
+			name: "declared primary key",
+			view: `CREATE VIEW customer_orders (customer_id, order_id,
+  CONSTRAINT customer_orders_pk PRIMARY KEY (order_id) RELY DISABLE NOVALIDATE) AS
+SELECT c.id, o.id FROM customers c JOIN orders o ON o.customer_id = c.id;`,
+			want:       []string{"CUSTOMER_ID number NOT NULL", "ORDER_ID number PK NOT NULL"},
+			primaryKey: []string{"ORDER_ID"},
+		},
+		{
+			name:       "inline primary key",
+			view:       `CREATE VIEW emails (id PRIMARY KEY RELY DISABLE NOVALIDATE, email) AS SELECT id, email FROM customers;`,
+			want:       []string{"ID number PK NOT NULL", "EMAIL varchar NOT NULL"},
+			primaryKey: []string{"ID"},
+		},
+		{
+			name:  "expression without an alias",
+			view:  `CREATE VIEW totals AS SELECT id, total + 1, UPPER(email) AS email FROM orders, customers;`,
+			want:  []string{"ID number NOT NULL", "EMAIL varchar"},
+			diags: []string{"view TOTALS: expression total+1 needs an alias, column left out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, diags, err := Parse(tables + tt.view)
+			if err != nil {
+				t.Fatal(err)
+			}
+			view := db.Views[0]
+			got := mapping(view.Columns, func(col *Column) string {
+				text := col.Name + " " + col.Type
+				if col.Attribute.IsPrimaryKey() {
+					text += " PK"
+				}
+				if !col.IsNullable() {
+					text += " NOT NULL"
+				}
+				return text
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got columns %q, want %q", got, tt.want)
+			}
+			if got := mapping(view.AsTable().PrimaryKey, getName); !slices.Equal(got, tt.primaryKey) {
+				t.Errorf("got primary key %v, want %v", got, tt.primaryKey)
+			}
+			if got := mapping(diags, func(d Diagnostic) string { return d.Message }); !slices.Equal(got, tt.diags) {
+				t.Errorf("got diagnostics %q, want %q", got, tt.diags)
+			}
+		})
+	}
+}